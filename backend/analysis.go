@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // AnalysisRule 分析规则接口
 type AnalysisRule interface {
 	GetName() string
 	GetDescription() string
-	Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider) (interface{}, error)
+	GetParameters() []RuleParameter
+	Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error)
 }
 
 // RowCountRule 行数统计规则
@@ -24,11 +28,41 @@ func (r *RowCountRule) GetDescription() string {
 	return "统计表的行数"
 }
 
-func (r *RowCountRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider) (interface{}, error) {
+func (r *RowCountRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *RowCountRule) GetParameters() []RuleParameter {
+	return nil
+}
+
+func (r *RowCountRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, _ RuleParams) (interface{}, error) {
 	if provider == nil {
 		return nil, fmt.Errorf("database provider not available")
 	}
-	return provider.ExecuteRowCount(ctx, db, config, tableName)
+	rowCount, err := provider.ExecuteRowCount(ctx, db, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return scaleSampledRowCount(ctx, rowCount), nil
+}
+
+func (r *RowCountRule) PlanAggregates(ctx context.Context, provider DatabaseProvider, _ []ColumnMetadata, _ RuleParams) *aggregatePlan {
+	expressions, _ := provider.AggregateExpressions(AggregateRowCount, ColumnMetadata{})
+	return &aggregatePlan{
+		groups: [][]string{expressions},
+		build: func(values [][]sql.NullFloat64) interface{} {
+			return scaleSampledRowCount(ctx, int64(values[0][0].Float64))
+		},
+	}
+}
+
+func (r *RowCountRule) PlanPartial(ctx context.Context, provider DatabaseProvider, _ []ColumnMetadata, _ RuleParams) *partialPlan {
+	return rowCountPartial(ctx, provider)
+}
+
+func (r *RowCountRule) FromStatistics(stats *TableStatistics, _ []ColumnMetadata) (interface{}, bool) {
+	return stats.RowCount, true
 }
 
 // NonNullRateRule 非空值率统计规则
@@ -42,44 +76,394 @@ func (r *NonNullRateRule) GetDescription() string {
 	return "统计列的非空值率"
 }
 
-func (r *NonNullRateRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider) (interface{}, error) {
+func (r *NonNullRateRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *NonNullRateRule) GetParameters() []RuleParameter {
+	return nil
+}
+
+func (r *NonNullRateRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, _ RuleParams) (interface{}, error) {
 	if provider == nil {
 		return nil, fmt.Errorf("database provider not available")
 	}
 	return provider.ExecuteNonNullRate(ctx, db, config, tableName)
 }
 
+func (r *NonNullRateRule) PlanAggregates(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *aggregatePlan {
+	return nonNullRatePlan(provider, columns)
+}
+
+func (r *NonNullRateRule) PlanPartial(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *partialPlan {
+	return nonNullRatePartial(provider, columns)
+}
+
+// FromStatistics 统计信息缺少任一列的空值数时扫描数据
+func (r *NonNullRateRule) FromStatistics(stats *TableStatistics, columns []ColumnMetadata) (interface{}, bool) {
+	result := make(map[string]float64, len(columns))
+	for _, column := range columns {
+		nullCount, ok := stats.NullCounts[column.ColumnName]
+		if !ok {
+			return nil, false
+		}
+		rate := 0.0
+		if stats.RowCount > 0 {
+			rate = clampRatio(1 - float64(nullCount)/float64(stats.RowCount))
+		}
+		result[column.ColumnName] = rate
+	}
+	return result, true
+}
+
+// ColumnDistinctStats 列去重统计结果
+type ColumnDistinctStats struct {
+	DistinctCount int64   `json:"distinct_count"`
+	DistinctRatio float64 `json:"distinct_ratio"` // 去重值数量 / 表总行数
+}
+
+// DistinctCountRule 去重计数与基数统计规则
+type DistinctCountRule struct{}
+
+func (r *DistinctCountRule) GetName() string {
+	return "distinct_count"
+}
+
+func (r *DistinctCountRule) GetDescription() string {
+	return "统计列的去重值数量和去重率"
+}
+
+func (r *DistinctCountRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *DistinctCountRule) GetParameters() []RuleParameter {
+	return nil
+}
+
+func (r *DistinctCountRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, _ RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+	return provider.ExecuteDistinctCount(ctx, db, config, tableName)
+}
+
+func (r *DistinctCountRule) PlanAggregates(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *aggregatePlan {
+	return distinctCountPlan(provider, columns)
+}
+
+// NumericColumnStats 数值列统计结果，全部为空的列对应字段为 nil
+type NumericColumnStats struct {
+	Min         *float64 `json:"min"`
+	Max         *float64 `json:"max"`
+	Mean        *float64 `json:"mean"`
+	StdDev      *float64 `json:"stddev"`
+	P25         *float64 `json:"p25"`
+	P50         *float64 `json:"p50"`
+	P75         *float64 `json:"p75"`
+	P95         *float64 `json:"p95"`
+	Approximate bool     `json:"approximate"` // 分位数是否为近似值
+	// PercentilesUnavailable 分位数无法由各分区（或增量区间）合并得出，P25-P95 为空而不是 0
+	PercentilesUnavailable bool `json:"percentilesUnavailable,omitempty"`
+}
+
+// numericPercentiles 数值统计规则计算的分位点
+var numericPercentiles = []float64{0.25, 0.5, 0.75, 0.95}
+
+// NumericStatsRule 数值列统计规则
+type NumericStatsRule struct{}
+
+func (r *NumericStatsRule) GetName() string {
+	return "numeric_stats"
+}
+
+func (r *NumericStatsRule) GetDescription() string {
+	return "统计数值列的最小值、最大值、均值、标准差及分位数"
+}
+
+func (r *NumericStatsRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *NumericStatsRule) GetParameters() []RuleParameter {
+	return nil
+}
+
+func (r *NumericStatsRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, _ RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+	return provider.ExecuteNumericStats(ctx, db, config, tableName)
+}
+
+func (r *NumericStatsRule) PlanAggregates(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *aggregatePlan {
+	return numericStatsPlan(provider, columns)
+}
+
+// PlanPartial 分区执行时分位数无法合并，结果中的分位数为空
+func (r *NumericStatsRule) PlanPartial(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *partialPlan {
+	return numericStatsPartial(provider, columns)
+}
+
+// ValueFrequency 单个取值的出现频次
+type ValueFrequency struct {
+	Value      *string `json:"value"` // NULL 值为 nil
+	Count      int64   `json:"count"`
+	Percentage float64 `json:"percentage"` // 占总行数的百分比 0-100
+}
+
+// defaultTopValuesLimit 默认每列返回的高频值数量
+const defaultTopValuesLimit = 10
+
+// TopValuesRule 高频值统计规则
+type TopValuesRule struct {
+	Limit int // 每列返回的高频值数量
+}
+
+// NewTopValuesRule 创建高频值统计规则，limit 非正数时使用默认值
+func NewTopValuesRule(limit int) *TopValuesRule {
+	if limit <= 0 {
+		limit = defaultTopValuesLimit
+	}
+	return &TopValuesRule{Limit: limit}
+}
+
+func (r *TopValuesRule) GetName() string {
+	return "top_values"
+}
+
+func (r *TopValuesRule) GetDescription() string {
+	return "统计每列出现频次最高的取值及其占比"
+}
+
+func (r *TopValuesRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *TopValuesRule) GetParameters() []RuleParameter {
+	return []RuleParameter{
+		{Name: "limit", Type: ParamInteger, Description: "每列返回的高频值数量", Default: r.Limit, Min: paramBound(1), Max: paramBound(1000)},
+		{Name: "columns", Type: ParamStringList, Description: "只统计指定的列，为空时统计全部列"},
+	}
+}
+
+func (r *TopValuesRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	// 大对象类型无法 GROUP BY，跳过
+	columns = filterColumns(selectColumns(columns, params.StringList("columns", nil)), func(column ColumnMetadata) bool {
+		return !isLOBColumn(provider, column)
+	})
+
+	result := make(map[string][]ValueFrequency, len(columns))
+	if len(columns) == 0 {
+		return result, nil
+	}
+
+	rowCount, err := provider.ExecuteRowCount(ctx, db, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := params.Int("limit", r.Limit)
+	if limit <= 0 {
+		limit = defaultTopValuesLimit
+	}
+
+	// 单列失败（如类型不支持 GROUP BY）时记录错误并继续统计其余列，全部失败时规则失败
+	tableRef := sampledTableRef(ctx, provider, config, tableName)
+	columnErrors := make(map[string]string)
+	var firstErr error
+	for _, column := range columns {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		col := provider.QuoteIdentifier(column.ColumnName)
+		query := fmt.Sprintf("SELECT %s AS val, COUNT(*) AS cnt FROM %s GROUP BY %s ORDER BY COUNT(*) DESC, %s", col, tableRef, col, col)
+		frequencies, err := queryValueFrequencies(ctx, db, provider.LimitQuery(query, limit), rowCount)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("column %s: %w", column.ColumnName, err)
+			}
+			columnErrors[column.ColumnName] = err.Error()
+			continue
+		}
+		result[column.ColumnName] = frequencies
+	}
+
+	if len(columnErrors) == 0 {
+		return result, nil
+	}
+	if len(result) == 0 {
+		return nil, firstErr
+	}
+	return &columnOutput{Values: result, Errors: columnErrors}, nil
+}
+
+// queryValueFrequencies 执行 (值, 频次) 形式的查询并计算占比
+func queryValueFrequencies(ctx context.Context, db *sql.DB, query string, rowCount int64) ([]ValueFrequency, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	frequencies := []ValueFrequency{}
+	for rows.Next() {
+		var value sql.NullString
+		var frequency ValueFrequency
+		if err := rows.Scan(&value, &frequency.Count); err != nil {
+			return nil, err
+		}
+		if value.Valid {
+			v := value.String
+			frequency.Value = &v
+		}
+		if rowCount > 0 {
+			frequency.Percentage = float64(frequency.Count) * 100 / float64(rowCount)
+		}
+		frequencies = append(frequencies, frequency)
+	}
+
+	return frequencies, rows.Err()
+}
+
+// StringColumnStats 字符列长度与空白统计结果
+// 比例均以非空值数量为分母；Oracle 将空串视为 NULL，其空串数恒为 0
+type StringColumnStats struct {
+	NonNullCount            int64    `json:"non_null_count"`
+	MinLength               *float64 `json:"min_length"`
+	AvgLength               *float64 `json:"avg_length"`
+	MaxLength               *float64 `json:"max_length"`
+	EmptyCount              int64    `json:"empty_count"`
+	EmptyRate               float64  `json:"empty_rate"`
+	LeadingWhitespaceCount  int64    `json:"leading_whitespace_count"`
+	TrailingWhitespaceCount int64    `json:"trailing_whitespace_count"` // 定长字符类型不统计
+	WhitespaceOnlyCount     int64    `json:"whitespace_only_count"`     // 非空串但全部由空白组成
+	WhitespaceOnlyRate      float64  `json:"whitespace_only_rate"`
+}
+
+// StringStatsRule 字符列长度与空白统计规则
+type StringStatsRule struct{}
+
+func (r *StringStatsRule) GetName() string {
+	return "string_stats"
+}
+
+func (r *StringStatsRule) GetDescription() string {
+	return "统计字符列的长度分布、空串及首尾空白情况"
+}
+
+func (r *StringStatsRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *StringStatsRule) GetParameters() []RuleParameter {
+	return nil
+}
+
+func (r *StringStatsRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, _ RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+	return provider.ExecuteStringStats(ctx, db, config, tableName)
+}
+
+func (r *StringStatsRule) PlanAggregates(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *aggregatePlan {
+	return stringStatsPlan(provider, columns)
+}
+
+func (r *StringStatsRule) PlanPartial(_ context.Context, provider DatabaseProvider, columns []ColumnMetadata, _ RuleParams) *partialPlan {
+	return stringStatsPartial(provider, columns)
+}
+
 // AnalysisEngine 分析引擎
 type AnalysisEngine struct {
-	rules map[string]AnalysisRule
+	mu       sync.RWMutex
+	rules    map[string]AnalysisRule
+	patterns *PatternRegistry
 }
 
 // NewAnalysisEngine 创建分析引擎
 func NewAnalysisEngine() *AnalysisEngine {
 	engine := &AnalysisEngine{
-		rules: make(map[string]AnalysisRule),
+		rules:    make(map[string]AnalysisRule),
+		patterns: NewPatternRegistry(),
 	}
 
 	// 注册默认规则
 	engine.RegisterRule(&RowCountRule{})
 	engine.RegisterRule(&NonNullRateRule{})
+	engine.RegisterRule(&DistinctCountRule{})
+	engine.RegisterRule(&NumericStatsRule{})
+	engine.RegisterRule(NewTopValuesRule(defaultTopValuesLimit))
+	engine.RegisterRule(NewHistogramRule(defaultHistogramBins, HistogramEqualWidth))
+	engine.RegisterRule(&StringStatsRule{})
+	engine.RegisterRule(&PatternMatchRule{Registry: engine.patterns, SampleSize: defaultPatternSampleSize})
+	engine.RegisterRule(&DuplicateRule{SampleLimit: defaultDuplicateSampleLimit})
+	engine.RegisterRule(&ForeignKeyOrphanRule{SampleLimit: defaultOrphanSampleLimit})
 
 	return engine
 }
 
+// Patterns 返回格式模式注册表
+func (e *AnalysisEngine) Patterns() *PatternRegistry {
+	return e.patterns
+}
+
 // RegisterRule 注册规则
 func (e *AnalysisEngine) RegisterRule(rule AnalysisRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.rules[rule.GetName()] = rule
 }
 
+// RegisterCustomRule 注册或替换自定义 SQL 断言规则，不能覆盖内置规则
+func (e *AnalysisEngine) RegisterCustomRule(definition CustomRuleDefinition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if existing, ok := e.rules[definition.Name]; ok {
+		if _, custom := existing.(*CustomSQLRule); !custom {
+			return fmt.Errorf("rule %s conflicts with a builtin rule", definition.Name)
+		}
+	}
+	e.rules[definition.Name] = &CustomSQLRule{Definition: definition}
+	return nil
+}
+
+// RemoveCustomRule 移除自定义规则，内置规则不可移除
+func (e *AnalysisEngine) RemoveCustomRule(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, custom := e.rules[name].(*CustomSQLRule); custom {
+		delete(e.rules, name)
+	}
+}
+
 // GetRule 获取规则
 func (e *AnalysisEngine) GetRule(name string) (AnalysisRule, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	rule, exists := e.rules[name]
 	return rule, exists
 }
 
 // GetAvailableRules 获取可用规则列表
 func (e *AnalysisEngine) GetAvailableRules() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	var rules []string
 	for name := range e.rules {
 		rules = append(rules, name)
@@ -87,37 +471,212 @@ func (e *AnalysisEngine) GetAvailableRules() []string {
 	return rules
 }
 
+// GetRuleSchemas 按名称排序返回规则及其参数定义
+func (e *AnalysisEngine) GetRuleSchemas() []RuleSchema {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	schemas := make([]RuleSchema, 0, len(e.rules))
+	for name, rule := range e.rules {
+		parameters := rule.GetParameters()
+		if parameters == nil {
+			parameters = []RuleParameter{}
+		}
+		schemas = append(schemas, RuleSchema{
+			Name:        name,
+			Description: rule.GetDescription(),
+			Parameters:  parameters,
+		})
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+	return schemas
+}
+
+// ValidateRuleConfig 校验规则存在且参数符合规则的参数定义
+func (e *AnalysisEngine) ValidateRuleConfig(config TaskRuleConfig) error {
+	rule, exists := e.GetRule(config.Name)
+	if !exists {
+		return fmt.Errorf("unknown rule: %s", config.Name)
+	}
+	if _, err := ValidateRuleParams(rule.GetParameters(), config.Params); err != nil {
+		return fmt.Errorf("rule %s: %w", config.Name, err)
+	}
+	return nil
+}
+
+// plannedRule 参数校验通过、待执行的规则
+type plannedRule struct {
+	name    string
+	rule    AnalysisRule
+	params  RuleParams
+	ctx     context.Context // 不支持采样的规则在采样分析中使用取消了采样的 context
+	sampled bool
+}
+
+// executeFused 将能以聚合表达式计算的规则合并为尽量少的表扫描执行
+// 返回合并执行成功的规则输出，其余规则（含合并查询失败的规则）由调用方单独执行
+func (e *AnalysisEngine) executeFused(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, columns []ColumnMetadata, sampling bool, planned []*plannedRule) map[string]interface{} {
+	var names []string
+	plans := make(map[string]*aggregatePlan)
+	for _, item := range planned {
+		aggregator, ok := item.rule.(aggregateRule)
+		// 合并查询使用同一个表引用，采样分析中只合并在样本上执行的规则
+		if !ok || (sampling && !item.sampled) {
+			continue
+		}
+		if plan := aggregator.PlanAggregates(item.ctx, provider, columns, item.params); plan != nil {
+			names = append(names, item.name)
+			plans[item.name] = plan
+		}
+	}
+	// 只有一个规则时合并没有收益，按原方式执行
+	if len(names) < 2 {
+		return nil
+	}
+
+	logger := GetLogger()
+	logger.LogInfo("EXECUTE_FUSED", fmt.Sprintf("合并执行规则 - 表: %s, 规则: %s", tableName, strings.Join(names, ", ")))
+	outputs, failed := executeAggregatePlans(ctx, db, config, provider, tableName, names, plans)
+	for name, err := range failed {
+		logger.LogError("EXECUTE_FUSED", fmt.Sprintf("合并查询失败，改为单独执行 - %s.%s: %s", tableName, name, err.Error()))
+	}
+	return outputs
+}
+
+// statisticsRule 能由全表统计直接得出结果的规则，统计不足时返回 false
+type statisticsRule interface {
+	FromStatistics(stats *TableStatistics, columns []ColumnMetadata) (interface{}, bool)
+}
+
+// executeFromStatistics 提供者带有全表统计时，由统计得出全表执行（未采样、无行过滤）的规则结果
+// 返回仍需扫描数据执行的规则
+func (e *AnalysisEngine) executeFromStatistics(ctx context.Context, tableName string, config *DatabaseConfig, provider DatabaseProvider, columns []ColumnMetadata, planned []*plannedRule, result *AnalysisResultSet) []*plannedRule {
+	statisticsProvider, ok := provider.(tableStatisticsProvider)
+	if !ok {
+		return planned
+	}
+
+	var stats *TableStatistics
+	var remaining []*plannedRule
+	for _, item := range planned {
+		rule, ok := item.rule.(statisticsRule)
+		if !ok || samplingFromContext(item.ctx) != nil || rowFilterFromContext(item.ctx) != nil {
+			remaining = append(remaining, item)
+			continue
+		}
+		if stats == nil {
+			var err error
+			if stats, err = statisticsProvider.TableStatistics(ctx, config, tableName); err != nil {
+				GetLogger().LogError("EXECUTE_RULE", fmt.Sprintf("读取统计信息失败 - %s: %s", tableName, err.Error()))
+				return planned
+			}
+		}
+		output, ok := rule.FromStatistics(stats, columns)
+		if !ok {
+			remaining = append(remaining, item)
+			continue
+		}
+		GetLogger().LogInfo("EXECUTE_RULE", fmt.Sprintf("规则由统计信息得出 - %s.%s", tableName, item.name))
+		result.SetRuleOutput(item.name, output)
+	}
+	return remaining
+}
+
 // ExecuteAnalysis 执行分析
-func (e *AnalysisEngine) ExecuteAnalysis(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, ruleNames []string) (map[string]interface{}, error) {
+func (e *AnalysisEngine) ExecuteAnalysis(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, rules []TaskRuleConfig) (*AnalysisResultSet, error) {
 	logger := GetLogger()
 	logger.SetModuleName("ANALYSIS")
-	logger.LogInfo("EXECUTE", fmt.Sprintf("开始执行表分析 - 表: %s, 规则数: %d", tableName, len(ruleNames)))
+	logger.LogInfo("EXECUTE", fmt.Sprintf("开始执行表分析 - 表: %s, 规则数: %d", tableName, len(rules)))
 
 	if provider == nil {
 		return nil, fmt.Errorf("database provider not available")
 	}
 
-	result := make(map[string]interface{})
+	result := NewAnalysisResultSet()
+	sampling := samplingFromContext(ctx)
+
+	// 列信息只查询一次，供本次分析的各规则共享
+	columns, columnsErr := provider.GetTableColumns(ctx, db, config, tableName)
+	if columnsErr != nil {
+		logger.LogError("EXECUTE", fmt.Sprintf("获取列信息失败 - %s: %s", tableName, columnsErr.Error()))
+	} else {
+		ctx = withTableColumns(ctx, tableName, columns)
+	}
 
-	for _, ruleName := range ruleNames {
-		rule, exists := e.rules[ruleName]
+	var planned []*plannedRule
+	for _, ruleConfig := range rules {
+		ruleName := ruleConfig.Name
+		rule, exists := e.GetRule(ruleName)
 		if !exists {
 			logger.LogError("EXECUTE", fmt.Sprintf("规则不存在 - %s", ruleName))
+			result.SetRuleError(ruleName, RuleStatusSkipped, fmt.Errorf("unknown rule: %s", ruleName))
 			continue // 跳过不存在的规则
 		}
 
-		logger.LogInfo("EXECUTE_RULE", fmt.Sprintf("执行规则 - %s.%s", tableName, ruleName))
-		ruleResult, err := rule.Execute(ctx, db, tableName, config, provider)
+		params, err := ValidateRuleParams(rule.GetParameters(), ruleConfig.Params)
 		if err != nil {
-			logger.LogError("EXECUTE_RULE", fmt.Sprintf("规则执行失败 - %s.%s: %s", tableName, ruleName, err.Error()))
-			result[ruleName] = map[string]interface{}{
-				"error": err.Error(),
-			}
+			logger.LogError("EXECUTE_RULE", fmt.Sprintf("规则参数无效 - %s.%s: %s", tableName, ruleName, err.Error()))
+			result.SetRuleError(ruleName, RuleStatusFailed, err)
 			continue
 		}
 
-		logger.LogInfo("EXECUTE_RULE", fmt.Sprintf("规则执行成功 - %s.%s", tableName, ruleName))
-		result[ruleName] = ruleResult
+		// 不支持采样的规则全表执行
+		item := &plannedRule{name: ruleName, rule: rule, params: params, ctx: ctx}
+		if sampling != nil {
+			if r, ok := rule.(samplingRule); ok && r.SupportsSampling() {
+				item.sampled = true
+			} else {
+				item.ctx = WithSampling(ctx, nil)
+			}
+		}
+		planned = append(planned, item)
+	}
+
+	if columnsErr == nil {
+		planned = e.executeFromStatistics(ctx, tableName, config, provider, columns, planned, result)
+	}
+	if len(planned) > 0 || sampling != nil {
+		if err := prepareTable(provider, config, tableName, rules); err != nil {
+			return nil, fmt.Errorf("failed to prepare table: %w", err)
+		}
+	}
+
+	// 采样分析时先统计样本行数，用于标注结果的样本量和估计误差
+	if sampling != nil {
+		info, err := collectSamplingInfo(ctx, db, config, provider, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect sample: %w", err)
+		}
+		result.Sampling = info
+		logger.LogInfo("EXECUTE", fmt.Sprintf("采样分析 - 表: %s, 比例: %g%%, 样本行数: %d", tableName, info.Percent, info.SampleRows))
+	}
+
+	// 列信息获取失败时各规则单独执行并各自报告错误
+	var fused map[string]interface{}
+	if columnsErr == nil {
+		fused = e.executeFused(ctx, db, tableName, config, provider, columns, sampling != nil, planned)
+	}
+
+	for _, item := range planned {
+		ruleResult, ok := fused[item.name]
+		if ok {
+			logger.LogInfo("EXECUTE_RULE", fmt.Sprintf("规则合并执行成功 - %s.%s", tableName, item.name))
+		} else {
+			logger.LogInfo("EXECUTE_RULE", fmt.Sprintf("执行规则 - %s.%s", tableName, item.name))
+			var err error
+			ruleResult, err = item.rule.Execute(item.ctx, db, tableName, config, provider, item.params)
+			if err != nil {
+				logger.LogError("EXECUTE_RULE", fmt.Sprintf("规则执行失败 - %s.%s: %s", tableName, item.name, err.Error()))
+				result.SetRuleError(item.name, RuleStatusFailed, err)
+				continue
+			}
+			logger.LogInfo("EXECUTE_RULE", fmt.Sprintf("规则执行成功 - %s.%s", tableName, item.name))
+		}
+
+		result.SetRuleOutput(item.name, ruleResult)
+		result.Rules[item.name].Sampled = item.sampled
 	}
 
 	return result, nil
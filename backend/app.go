@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -58,6 +59,8 @@ func (a *App) Startup(ctx context.Context) {
 		if logger := GetLogger(); logger != nil {
 			logger.LogInfo("STARTUP", "存储管理器初始化 - 存储管理器初始化成功")
 		}
+		a.loadCustomPatterns()
+		a.loadCustomRules()
 	}
 
 	// 初始化任务管理器
@@ -221,12 +224,12 @@ func (a *App) DeleteAnalysisResult(resultID string) error {
 	return a.storageManager.DeleteAnalysisResult(resultID)
 }
 
-// GetAvailableRules 获取可用规则列表
-func (a *App) GetAvailableRules() []string {
+// GetAvailableRules 获取可用规则及其参数定义，供前端渲染参数表单
+func (a *App) GetAvailableRules() []RuleSchema {
 	if a.analysisEngine != nil {
-		return a.analysisEngine.GetAvailableRules()
+		return a.analysisEngine.GetRuleSchemas()
 	}
-	return []string{}
+	return []RuleSchema{}
 }
 
 // GetTaskStatus 获取任务状态
@@ -250,6 +253,7 @@ func (a *App) GetTaskStatus(taskID string) (map[string]interface{}, error) {
 		"startedAt":    task.StartedAt,
 		"completedAt":  task.CompletedAt,
 		"duration":     task.Duration.Milliseconds(),
+		"partitions":   a.taskManager.GetTaskPartitions(taskID),
 	}, nil
 }
 
@@ -288,6 +292,15 @@ func (a *App) CancelTask(taskID string) error {
 	return a.taskManager.CancelTask(taskID)
 }
 
+// RetryTaskPartition 重新执行分区分析中失败的分区，index 为 GetTaskStatus 返回的分区序号
+func (a *App) RetryTaskPartition(taskID string, index int) error {
+	if a.taskManager == nil {
+		return fmt.Errorf("task manager not initialized")
+	}
+
+	return a.taskManager.RetryTaskPartition(taskID, index)
+}
+
 // SaveDatabaseConnection 保存数据库连接配置
 func (a *App) SaveDatabaseConnection(config DatabaseConfig) error {
 	if a.storageManager == nil {
@@ -483,18 +496,110 @@ func (a *App) GetMetadataColumns(tableID string) ([]map[string]interface{}, erro
 	var result []map[string]interface{}
 	for _, column := range columns {
 		result = append(result, map[string]interface{}{
-			"id":            column.ID,
-			"tableId":       column.TableID,
-			"columnName":    column.ColumnName,
-			"columnComment": column.ColumnComment,
-			"columnOrdinal": column.ColumnOrdinal,
-			"columnType":    column.ColumnType,
+			"id":               column.ID,
+			"tableId":          column.TableID,
+			"columnName":       column.ColumnName,
+			"columnComment":    column.ColumnComment,
+			"columnOrdinal":    column.ColumnOrdinal,
+			"columnType":       column.ColumnType,
+			"nullable":         column.Nullable,
+			"defaultValue":     column.DefaultValue,
+			"charLength":       column.CharLength,
+			"numericPrecision": column.NumericPrecision,
+			"numericScale":     column.NumericScale,
 		})
 	}
 
 	return result, nil
 }
 
+// GetMetadataConstraints 获取指定表的主键、唯一约束和外键
+func (a *App) GetMetadataConstraints(tableID string) ([]map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return []map[string]interface{}{}, nil
+	}
+
+	constraints, err := a.storageManager.GetMetadataConstraints(tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+	for _, constraint := range constraints {
+		result = append(result, map[string]interface{}{
+			"id":                constraint.ID,
+			"tableId":           constraint.TableID,
+			"constraintName":    constraint.ConstraintName,
+			"constraintType":    constraint.ConstraintType,
+			"columns":           constraint.Columns,
+			"referencedTable":   constraint.ReferencedTable,
+			"referencedColumns": constraint.ReferencedColumns,
+		})
+	}
+
+	return result, nil
+}
+
+// GetMetadataIndexes 获取指定表的索引及冗余/缺失索引汇总
+func (a *App) GetMetadataIndexes(tableID string) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	indexes, summary, err := a.loadIndexSummary(tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexList := make([]map[string]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		indexList = append(indexList, map[string]interface{}{
+			"id":        index.ID,
+			"tableId":   index.TableID,
+			"indexName": index.IndexName,
+			"indexType": index.IndexType,
+			"columns":   index.Columns,
+			"unique":    index.Unique,
+			"primary":   index.Primary,
+			"partial":   index.Partial,
+		})
+	}
+
+	return map[string]interface{}{
+		"indexes": indexList,
+		"summary": summary,
+	}, nil
+}
+
+// loadIndexSummary 读取存储的索引与约束并生成索引使用情况汇总
+func (a *App) loadIndexSummary(tableID string) ([]*MetadataIndexInfo, *IndexSummary, error) {
+	indexes, err := a.storageManager.GetMetadataIndexes(tableID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get indexes: %w", err)
+	}
+	constraints, err := a.storageManager.GetMetadataConstraints(tableID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get constraints: %w", err)
+	}
+
+	tableIndexes := make([]TableIndex, 0, len(indexes))
+	for _, index := range indexes {
+		tableIndexes = append(tableIndexes, index.TableIndex)
+	}
+	tableConstraints := make([]TableConstraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		tableConstraints = append(tableConstraints, TableConstraint{
+			ConstraintName:    constraint.ConstraintName,
+			ConstraintType:    ConstraintType(constraint.ConstraintType),
+			Columns:           constraint.Columns,
+			ReferencedTable:   constraint.ReferencedTable,
+			ReferencedColumns: constraint.ReferencedColumns,
+		})
+	}
+
+	return indexes, summarizeIndexes(tableIndexes, tableConstraints), nil
+}
+
 // CreateTask 创建任务
 func (a *App) CreateTask(name, description string) (map[string]interface{}, error) {
 	if a.storageManager == nil {
@@ -535,12 +640,16 @@ func (a *App) GetAllTasks() ([]map[string]interface{}, error) {
 	var result []map[string]interface{}
 	for _, task := range tasks {
 		result = append(result, map[string]interface{}{
-			"id":          task.ID,
-			"name":        task.Name,
-			"description": task.Description,
-			"status":      task.Status,
-			"createdAt":   task.CreatedAt,
-			"updatedAt":   task.UpdatedAt,
+			"id":           task.ID,
+			"name":         task.Name,
+			"description":  task.Description,
+			"status":       task.Status,
+			"rules":        task.Rules,
+			"sampling":     task.Sampling,
+			"partitioning": task.Partitioning,
+			"watermark":    task.Watermark,
+			"createdAt":    task.CreatedAt,
+			"updatedAt":    task.UpdatedAt,
 		})
 	}
 
@@ -634,12 +743,182 @@ func (a *App) GetTaskTables(taskID string) ([]map[string]interface{}, error) {
 			"rowCount":       table.RowCount,
 			"tableSize":      table.TableSize,
 			"columnCount":    table.ColumnCount,
+			"rules":          table.Rules,
+			"qualityStatus":  table.QualityStatus,
+			"qualityScore":   table.QualityScore,
 		})
 	}
 
 	return result, nil
 }
 
+// UpdateTaskRules 设置任务要执行的规则及参数，rules 为空时执行全部规则
+func (a *App) UpdateTaskRules(taskID string, rules []TaskRuleConfig) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if err := a.validateRuleConfigs(rules); err != nil {
+		return nil, err
+	}
+
+	err := a.storageManager.UpdateTaskRules(taskID, rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task rules: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "任务规则更新成功",
+	}, nil
+}
+
+// UpdateTaskSampling 设置任务的采样配置，sampling 为空时全表分析
+func (a *App) UpdateTaskSampling(taskID string, sampling *SamplingConfig) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if sampling != nil {
+		if err := sampling.Validate(); err != nil {
+			return nil, err
+		}
+		if err := a.ensureExclusiveScanMode(taskID, "sampling"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := a.storageManager.UpdateTaskSampling(taskID, sampling)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task sampling: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "任务采样配置更新成功",
+	}, nil
+}
+
+// UpdateTaskPartitioning 设置任务的分区扫描配置，partitioning 为空时整表分析
+// 分区扫描用于在超时限制内完成大表的全量分析
+func (a *App) UpdateTaskPartitioning(taskID string, partitioning *PartitionConfig) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if partitioning != nil {
+		if err := partitioning.Validate(); err != nil {
+			return nil, err
+		}
+		if err := a.ensureExclusiveScanMode(taskID, "partitioning"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := a.storageManager.UpdateTaskPartitioning(taskID, partitioning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task partitioning: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "任务分区配置更新成功",
+	}, nil
+}
+
+// UpdateTaskWatermark 设置任务的增量分析水位列，watermark 为空时每次分析全表
+func (a *App) UpdateTaskWatermark(taskID string, watermark *WatermarkConfig) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if watermark != nil {
+		if err := watermark.Validate(); err != nil {
+			return nil, err
+		}
+		if err := a.ensureExclusiveScanMode(taskID, "watermark"); err != nil {
+			return nil, err
+		}
+	}
+
+	err := a.storageManager.UpdateTaskWatermark(taskID, watermark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task watermark: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "任务增量分析配置更新成功",
+	}, nil
+}
+
+// ResetAnalysisWatermark 清除任务中表的增量分析水位和累计值，下次分析从头累计
+func (a *App) ResetAnalysisWatermark(taskID, tableID string) error {
+	if a.storageManager == nil {
+		return fmt.Errorf("storage manager not initialized")
+	}
+	return a.storageManager.DeleteAnalysisWatermark(taskID, tableID)
+}
+
+// ensureExclusiveScanMode 采样、分区扫描和增量分析互不兼容，启用 mode 前检查任务未启用其他方式
+func (a *App) ensureExclusiveScanMode(taskID, mode string) error {
+	taskInfo, err := a.storageManager.GetTask(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	enabled := []struct {
+		mode string
+		on   bool
+	}{
+		{"sampling", taskInfo.Sampling != nil},
+		{"partitioning", taskInfo.Partitioning != nil},
+		{"watermark", taskInfo.Watermark != nil},
+	}
+	for _, other := range enabled {
+		if other.on && other.mode != mode {
+			return fmt.Errorf("%s cannot be combined with %s", mode, other.mode)
+		}
+	}
+	return nil
+}
+
+// UpdateTaskTableRules 设置任务中单张表要执行的规则及参数，rules 为空时沿用任务的规则
+func (a *App) UpdateTaskTableRules(taskID, taskTableID string, rules []TaskRuleConfig) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if err := a.validateRuleConfigs(rules); err != nil {
+		return nil, err
+	}
+
+	err := a.storageManager.UpdateTaskTableRules(taskID, taskTableID, rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task table rules: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "表规则更新成功",
+	}, nil
+}
+
+// validateRuleConfigs 校验规则选择中的规则均已注册、不重复且参数有效
+func (a *App) validateRuleConfigs(rules []TaskRuleConfig) error {
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := a.analysisEngine.ValidateRuleConfig(rule); err != nil {
+			return err
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("duplicate rule: %s", rule.Name)
+		}
+		seen[rule.Name] = true
+	}
+	return nil
+}
+
 // RemoveTableFromTask 从任务中移除表
 func (a *App) RemoveTableFromTask(taskID, tableID string) (map[string]interface{}, error) {
 	if a.storageManager == nil {
@@ -684,6 +963,16 @@ func (a *App) StartTaskAnalysis(taskID string) (map[string]interface{}, error) {
 		}, fmt.Errorf("storage manager not available")
 	}
 
+	// 获取任务的规则选择
+	taskInfo, err := a.storageManager.GetTask(taskID)
+	if err != nil {
+		logger.LogError("START_ANALYSIS", fmt.Sprintf("获取任务失败 - %s: %s", taskID, err.Error()))
+		return map[string]interface{}{
+			"status":  "error",
+			"message": "获取任务失败",
+		}, fmt.Errorf("failed to get task: %w", err)
+	}
+
 	// 获取任务下的所有表
 	taskTables, err := a.storageManager.GetTaskTables(taskID)
 	if err != nil {
@@ -747,6 +1036,12 @@ func (a *App) StartTaskAnalysis(taskID string) (map[string]interface{}, error) {
 
 		logger.LogInfo("START_ANALYSIS", fmt.Sprintf("找到数据库配置 - %s, 将创建分析任务", dbConfig.Name))
 
+		// 表级规则选择优先于任务级规则选择
+		rules := table.Rules
+		if len(rules) == 0 {
+			rules = taskInfo.Rules
+		}
+
 		// 创建分析任务
 		err = a.taskManager.CreateAnalysisTasksForTable(
 			taskID,
@@ -755,6 +1050,10 @@ func (a *App) StartTaskAnalysis(taskID string) (map[string]interface{}, error) {
 			table.TableName,
 			table.ConnectionID,
 			dbConfig,
+			rules,
+			taskInfo.Sampling,
+			taskInfo.Partitioning,
+			taskInfo.Watermark,
 		)
 
 		if err != nil {
@@ -911,7 +1210,7 @@ func (a *App) GetTableAnalysisResult(taskID, taskTableID string) (map[string]int
 		}, fmt.Errorf("failed to get analysis result: %w", err)
 	}
 
-	logger.LogInfo("GET_RESULT", fmt.Sprintf("成功获取分析结果: ID=%s, TableName=%s, RowCount=%d", result.ID, result.TableName, result.Results["row_count"]))
+	logger.LogInfo("GET_RESULT", fmt.Sprintf("成功获取分析结果: ID=%s, TableName=%s, Version=%d", result.ID, result.TableName, result.Results.Version))
 
 	// 添加连接信息
 	response := map[string]interface{}{
@@ -925,6 +1224,9 @@ func (a *App) GetTableAnalysisResult(taskID, taskTableID string) (map[string]int
 		"columnCount":    targetTable.ColumnCount,
 		"resultId":       result.ID, // 添加结果ID用于获取增强数据
 	}
+	if quality, err := a.storageManager.GetQualityReport(result.ID); err == nil && quality != nil {
+		response["quality"] = quality
+	}
 
 	logger.LogInfo("GET_RESULT", fmt.Sprintf("返回响应 - status=%s, rowCount=%d, columnCount=%d", response["status"], response["rowCount"], response["columnCount"]))
 	return response, nil
@@ -1019,6 +1321,20 @@ func (a *App) GetEnhancedAnalysisResult(taskID, taskTableID string) (map[string]
 		"duration":       enhancedResult.Duration.Seconds(),
 		"rules":          enhancedResult.Rules,
 	}
+
+	// 附带质量判定
+	if quality, err := a.storageManager.GetQualityReport(enhancedResult.ID); err == nil && quality != nil {
+		response["quality"] = quality
+	} else if err != nil {
+		logger.LogError("GET_ENHANCED_RESULT", fmt.Sprintf("获取质量判定失败 - %s", err.Error()))
+	}
+
+	// 附带索引汇总，便于在分析结果旁查看冗余和缺失索引
+	if _, indexSummary, err := a.loadIndexSummary(targetTable.TableID); err == nil {
+		response["indexSummary"] = indexSummary
+	} else {
+		logger.LogError("GET_ENHANCED_RESULT", fmt.Sprintf("获取索引汇总失败 - %s", err.Error()))
+	}
 	logger.LogInfo("GET_ENHANCED_RESPONSE", fmt.Sprintf("Response is %s", response))
 	logger.LogInfo("GET_ENHANCED_RESULT", fmt.Sprintf("返回增强响应 - 表: %s, 列数: %d", response["tableName"], len(columnsResponse)))
 
@@ -1029,6 +1345,270 @@ func (a *App) GetEnhancedAnalysisResult(taskID, taskTableID string) (map[string]
 	return response, nil
 }
 
+// loadCustomPatterns 将存储中的自定义格式模式注册到分析引擎
+func (a *App) loadCustomPatterns() {
+	patterns, err := a.storageManager.GetPatterns()
+	if err != nil {
+		if logger := GetLogger(); logger != nil {
+			logger.LogError("STARTUP", fmt.Sprintf("加载自定义格式模式失败 - %s", err.Error()))
+		}
+		return
+	}
+
+	for _, pattern := range patterns {
+		if err := a.analysisEngine.Patterns().Register(*pattern); err != nil {
+			if logger := GetLogger(); logger != nil {
+				logger.LogError("STARTUP", fmt.Sprintf("注册自定义格式模式失败 - %s: %s", pattern.Name, err.Error()))
+			}
+		}
+	}
+}
+
+// GetPatterns 获取所有格式模式（内置和自定义）
+func (a *App) GetPatterns() []PatternDefinition {
+	return a.analysisEngine.Patterns().List()
+}
+
+// SavePattern 新建或更新自定义格式模式，patternID 为空时新建
+func (a *App) SavePattern(patternID, name, description, expression string) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	pattern := PatternDefinition{
+		ID:          patternID,
+		Name:        name,
+		Description: description,
+		Expression:  expression,
+	}
+	if pattern.ID == "" {
+		pattern.ID = uuid.New().String()
+	}
+
+	registry := a.analysisEngine.Patterns()
+	if err := registry.ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+
+	var previousName string
+	if patternID != "" {
+		if existing, err := a.storageManager.GetPattern(patternID); err == nil {
+			previousName = existing.Name
+		}
+	}
+
+	if err := a.storageManager.SavePattern(&pattern); err != nil {
+		return nil, fmt.Errorf("failed to save pattern: %w", err)
+	}
+	// 保存成功后再移除重命名前的旧名称
+	if previousName != "" && previousName != pattern.Name {
+		registry.Remove(previousName)
+	}
+	if err := registry.Register(pattern); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":      pattern.ID,
+		"name":    pattern.Name,
+		"status":  "success",
+		"message": "格式模式保存成功",
+	}, nil
+}
+
+// DeletePattern 删除自定义格式模式
+func (a *App) DeletePattern(patternID string) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	pattern, err := a.storageManager.GetPattern(patternID)
+	if err != nil {
+		return nil, fmt.Errorf("pattern not found: %w", err)
+	}
+
+	if err := a.storageManager.DeletePattern(patternID); err != nil {
+		return nil, fmt.Errorf("failed to delete pattern: %w", err)
+	}
+	a.analysisEngine.Patterns().Remove(pattern.Name)
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "格式模式删除成功",
+	}, nil
+}
+
+// GetExpectations 获取表的数据质量期望
+func (a *App) GetExpectations(tableID string) ([]*Expectation, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+	expectations, err := a.storageManager.GetExpectations(tableID)
+	if err != nil {
+		return nil, err
+	}
+	if expectations == nil {
+		expectations = []*Expectation{}
+	}
+	return expectations, nil
+}
+
+// SaveExpectation 新建或更新数据质量期望，expectation.ID 为空时新建
+func (a *App) SaveExpectation(expectation Expectation) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+	if expectation.TableID == "" {
+		return nil, fmt.Errorf("table id is required")
+	}
+	if expectation.Severity == "" {
+		expectation.Severity = SeverityError
+	}
+	if err := expectation.Validate(); err != nil {
+		return nil, err
+	}
+	if expectation.ID == "" {
+		expectation.ID = uuid.New().String()
+	}
+
+	if err := a.storageManager.SaveExpectation(&expectation); err != nil {
+		return nil, fmt.Errorf("failed to save expectation: %w", err)
+	}
+
+	return map[string]interface{}{
+		"id":      expectation.ID,
+		"status":  "success",
+		"message": "质量期望保存成功",
+	}, nil
+}
+
+// DeleteExpectation 删除数据质量期望
+func (a *App) DeleteExpectation(expectationID string) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	if err := a.storageManager.DeleteExpectation(expectationID); err != nil {
+		return nil, fmt.Errorf("failed to delete expectation: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "质量期望删除成功",
+	}, nil
+}
+
+// loadCustomRules 将存储中的自定义 SQL 断言规则注册到分析引擎
+func (a *App) loadCustomRules() {
+	rules, err := a.storageManager.GetCustomRules()
+	if err != nil {
+		if logger := GetLogger(); logger != nil {
+			logger.LogError("STARTUP", fmt.Sprintf("加载自定义规则失败 - %s", err.Error()))
+		}
+		return
+	}
+
+	for _, rule := range rules {
+		if err := a.analysisEngine.RegisterCustomRule(*rule); err != nil {
+			if logger := GetLogger(); logger != nil {
+				logger.LogError("STARTUP", fmt.Sprintf("注册自定义规则失败 - %s: %s", rule.Name, err.Error()))
+			}
+		}
+	}
+}
+
+// GetCustomRules 获取所有自定义 SQL 断言规则
+func (a *App) GetCustomRules() ([]*CustomRuleDefinition, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+	rules, err := a.storageManager.GetCustomRules()
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []*CustomRuleDefinition{}
+	}
+	return rules, nil
+}
+
+// SaveCustomRule 新建或更新自定义 SQL 断言规则，rule.ID 为空时新建
+func (a *App) SaveCustomRule(rule CustomRuleDefinition) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	isNew := rule.ID == ""
+	if isNew {
+		rule.ID = uuid.New().String()
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if existing, exists := a.analysisEngine.GetRule(rule.Name); exists {
+		if _, custom := existing.(*CustomSQLRule); !custom {
+			return nil, fmt.Errorf("rule %s conflicts with a builtin rule", rule.Name)
+		}
+	}
+
+	var previousName string
+	if !isNew {
+		if existing, err := a.storageManager.GetCustomRule(rule.ID); err == nil {
+			previousName = existing.Name
+		}
+	}
+	// 任务的规则选择和质量期望按名称引用规则，重命名后将无法解析
+	if previousName != "" && previousName != rule.Name {
+		references, err := a.storageManager.RuleReferences(previousName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rule references: %w", err)
+		}
+		if len(references) > 0 {
+			return nil, fmt.Errorf("rule %s is in use by %s, remove it from these before renaming", previousName, strings.Join(references, ", "))
+		}
+	}
+
+	if err := a.storageManager.SaveCustomRule(&rule); err != nil {
+		return nil, fmt.Errorf("failed to save custom rule: %w", err)
+	}
+	// 保存成功后再移除重命名前的旧名称
+	if previousName != "" && previousName != rule.Name {
+		a.analysisEngine.RemoveCustomRule(previousName)
+	}
+	if err := a.analysisEngine.RegisterCustomRule(rule); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":      rule.ID,
+		"name":    rule.Name,
+		"status":  "success",
+		"message": "自定义规则保存成功",
+	}, nil
+}
+
+// DeleteCustomRule 删除自定义 SQL 断言规则
+func (a *App) DeleteCustomRule(ruleID string) (map[string]interface{}, error) {
+	if a.storageManager == nil {
+		return nil, fmt.Errorf("storage manager not initialized")
+	}
+
+	rule, err := a.storageManager.GetCustomRule(ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("custom rule not found: %w", err)
+	}
+
+	if err := a.storageManager.DeleteCustomRule(ruleID); err != nil {
+		return nil, fmt.Errorf("failed to delete custom rule: %w", err)
+	}
+	a.analysisEngine.RemoveCustomRule(rule.Name)
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "自定义规则删除成功",
+	}, nil
+}
+
 // Greet returns a greeting for the given name
 func (a *App) Greet(name string) string {
 	return fmt.Sprintf("Hello %s, It's show time!", name)
@@ -0,0 +1,223 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 自定义规则 SQL 模板中的占位符
+const (
+	tablePlaceholder  = "{{table}}"
+	columnPlaceholder = "{{column}}"
+)
+
+// AssertionExpectation 自定义规则的期望结果类型
+type AssertionExpectation string
+
+const (
+	ExpectZeroRows        AssertionExpectation = "zero_rows"         // 查询不返回任何行
+	ExpectRowCountBetween AssertionExpectation = "row_count_between" // 返回行数在 [Min, Max] 内
+	ExpectScalarBetween   AssertionExpectation = "scalar_between"    // 首行首列的值在 [Min, Max] 内
+)
+
+// CustomRuleDefinition 用户定义的 SQL 断言规则
+type CustomRuleDefinition struct {
+	ID          string               `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	SQL         string               `json:"sql"` // SELECT 模板，{{table}} 替换为表名，{{column}} 替换为列名
+	Expectation AssertionExpectation `json:"expectation"`
+	Min         *float64             `json:"min,omitempty"`
+	Max         *float64             `json:"max,omitempty"`
+	Columns     []string             `json:"columns,omitempty"` // 模板包含 {{column}} 时默认检查的列
+}
+
+// AssertionResult 断言执行结果
+type AssertionResult struct {
+	Passed   bool     `json:"passed"`
+	Observed *float64 `json:"observed"` // 返回行数或标量值，标量为 NULL 时为空
+	Query    string   `json:"query"`
+}
+
+// customRuleNamePattern 自定义规则名与内置规则一致使用小写下划线风格
+var customRuleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Validate 校验规则定义
+func (d *CustomRuleDefinition) Validate() error {
+	if !customRuleNamePattern.MatchString(d.Name) {
+		return fmt.Errorf("rule name must match %s", customRuleNamePattern.String())
+	}
+
+	template := strings.TrimSpace(d.SQL)
+	upper := strings.ToUpper(template)
+	if !strings.HasPrefix(upper, "SELECT") && !strings.HasPrefix(upper, "WITH") {
+		return fmt.Errorf("rule sql must be a SELECT query")
+	}
+	if strings.Contains(strings.TrimRight(template, "; \t\r\n"), ";") {
+		return fmt.Errorf("rule sql must be a single statement")
+	}
+	if !strings.Contains(template, tablePlaceholder) {
+		return fmt.Errorf("rule sql must reference %s", tablePlaceholder)
+	}
+	if d.usesColumn() && len(d.Columns) == 0 {
+		return fmt.Errorf("rule sql references %s but no columns are configured", columnPlaceholder)
+	}
+
+	switch d.Expectation {
+	case ExpectZeroRows:
+	case ExpectRowCountBetween, ExpectScalarBetween:
+		if d.Min == nil && d.Max == nil {
+			return fmt.Errorf("expectation %s requires min or max", d.Expectation)
+		}
+		if d.Min != nil && d.Max != nil && *d.Min > *d.Max {
+			return fmt.Errorf("min must not be greater than max")
+		}
+	default:
+		return fmt.Errorf("unsupported expectation: %s", d.Expectation)
+	}
+	return nil
+}
+
+// usesColumn 模板是否按列执行
+func (d *CustomRuleDefinition) usesColumn() bool {
+	return strings.Contains(d.SQL, columnPlaceholder)
+}
+
+// CustomSQLRule 将自定义规则定义适配为分析规则
+// 模板包含 {{column}} 时按列执行，结果为 列名 -> 断言结果；否则结果为单个断言结果
+type CustomSQLRule struct {
+	Definition CustomRuleDefinition
+}
+
+func (r *CustomSQLRule) GetName() string {
+	return r.Definition.Name
+}
+
+func (r *CustomSQLRule) GetDescription() string {
+	return r.Definition.Description
+}
+
+func (r *CustomSQLRule) GetParameters() []RuleParameter {
+	var parameters []RuleParameter
+	if r.Definition.usesColumn() {
+		parameters = append(parameters, RuleParameter{Name: "columns", Type: ParamStringList, Description: "要检查的列，表中不存在的列会被忽略", Default: r.Definition.Columns})
+	}
+	if r.Definition.Expectation != ExpectZeroRows {
+		parameters = append(parameters,
+			RuleParameter{Name: "min", Type: ParamNumber, Description: "期望下限", Default: derefFloat(r.Definition.Min)},
+			RuleParameter{Name: "max", Type: ParamNumber, Description: "期望上限", Default: derefFloat(r.Definition.Max)},
+		)
+	}
+	return parameters
+}
+
+// derefFloat 将可空数值转换为参数默认值，nil 保持为 nil
+func derefFloat(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func (r *CustomSQLRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+
+	query := strings.ReplaceAll(strings.TrimRight(strings.TrimSpace(r.Definition.SQL), "; \t\r\n"), tablePlaceholder, provider.QuoteTableName(config, tableName))
+	lower, upper := r.Definition.Min, r.Definition.Max
+	if value, ok := params["min"].(float64); ok {
+		lower = &value
+	}
+	if value, ok := params["max"].(float64); ok {
+		upper = &value
+	}
+
+	if !r.Definition.usesColumn() {
+		return r.evaluate(ctx, db, query, lower, upper)
+	}
+
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns = selectColumns(columns, params.StringList("columns", r.Definition.Columns))
+
+	result := make(map[string]*AssertionResult, len(columns))
+	for _, column := range columns {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		columnQuery := strings.ReplaceAll(query, columnPlaceholder, provider.QuoteIdentifier(column.ColumnName))
+		assertion, err := r.evaluate(ctx, db, columnQuery, lower, upper)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.ColumnName, err)
+		}
+		result[column.ColumnName] = assertion
+	}
+	return result, nil
+}
+
+// evaluate 执行单条断言查询并按期望判定
+func (r *CustomSQLRule) evaluate(ctx context.Context, db *sql.DB, query string, lower, upper *float64) (*AssertionResult, error) {
+	result := &AssertionResult{Query: query}
+
+	switch r.Definition.Expectation {
+	case ExpectZeroRows, ExpectRowCountBetween:
+		count, err := countQueryRows(ctx, db, query)
+		if err != nil {
+			return nil, err
+		}
+		observed := float64(count)
+		result.Observed = &observed
+		if r.Definition.Expectation == ExpectZeroRows {
+			result.Passed = count == 0
+		} else {
+			result.Passed = withinBounds(observed, lower, upper)
+		}
+	case ExpectScalarBetween:
+		var value sql.NullFloat64
+		err := db.QueryRowContext(ctx, query).Scan(&value)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if value.Valid {
+			result.Observed = &value.Float64
+			result.Passed = withinBounds(value.Float64, lower, upper)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported expectation: %s", r.Definition.Expectation)
+	}
+
+	return result, nil
+}
+
+// countQueryRows 在客户端统计查询返回的行数
+// 不包装为 SELECT COUNT(*) FROM (...)：SQL Server 不允许派生表中出现 CTE 或 ORDER BY
+func countQueryRows(ctx context.Context, db *sql.DB, query string) (int64, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		count++
+	}
+	return count, rows.Err()
+}
+
+// withinBounds 判断数值是否在可选的上下限内
+func withinBounds(value float64, lower, upper *float64) bool {
+	if lower != nil && value < *lower {
+		return false
+	}
+	if upper != nil && value > *upper {
+		return false
+	}
+	return true
+}
@@ -16,7 +16,16 @@ type DatabaseConfig struct {
 	Username    string `json:"username"`
 	Password    string `json:"password"`
 	Database    string `json:"database"`
-	Concurrency int    `json:"concurrency"` // 并发度配置，默认5
+	FilePath    string `json:"filePath,omitempty"` // 文件类数据源（SQLite、CSV/TSV）的文件或目录路径
+	Concurrency int    `json:"concurrency"`        // 并发度配置，默认5
+}
+
+// connectionTarget 日志中展示的连接目标，文件类数据源为文件路径
+func connectionTarget(config *DatabaseConfig) string {
+	if config.FilePath != "" {
+		return config.FilePath
+	}
+	return fmt.Sprintf("%s@%s:%d/%s", config.Username, config.Host, config.Port, config.Database)
 }
 
 // DatabaseManager 数据库管理器
@@ -35,7 +44,7 @@ func NewDatabaseManager() *DatabaseManager {
 func (dm *DatabaseManager) Connect(config *DatabaseConfig) error {
 	logger := GetLogger()
 	logger.SetModuleName("DATABASE")
-	logger.LogInfo("CONNECT", fmt.Sprintf("开始连接数据库 %s@%s:%d/%s", config.Username, config.Host, config.Port, config.Database))
+	logger.LogInfo("CONNECT", fmt.Sprintf("开始连接数据库 %s", connectionTarget(config)))
 
 	provider, err := resolveProvider(config.Type)
 	if err != nil {
@@ -67,6 +76,16 @@ func (dm *DatabaseManager) Connect(config *DatabaseConfig) error {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if resolver, ok := provider.(providerResolver); ok {
+		resolved, err := resolver.Resolve(context.Background(), db)
+		if err != nil {
+			logger.LogWarn("CONNECT", fmt.Sprintf("识别数据库类型失败，按 %s 处理 - %s", provider.Name(), err.Error()))
+		} else if resolved != provider {
+			logger.LogInfo("CONNECT", fmt.Sprintf("识别为 %s", resolved.Name()))
+			provider = resolved
+		}
+	}
+
 	dm.config = config
 	dm.db = db
 	dm.provider = provider
@@ -78,7 +97,7 @@ func (dm *DatabaseManager) Connect(config *DatabaseConfig) error {
 func (dm *DatabaseManager) TestConnection(config *DatabaseConfig) error {
 	logger := GetLogger()
 	logger.SetModuleName("DATABASE")
-	logger.LogInfo("TEST", fmt.Sprintf("开始测试数据库连接 %s@%s:%d/%s", config.Username, config.Host, config.Port, config.Database))
+	logger.LogInfo("TEST", fmt.Sprintf("开始测试数据库连接 %s", connectionTarget(config)))
 
 	provider, err := resolveProvider(config.Type)
 	if err != nil {
@@ -179,20 +198,50 @@ func (dm *DatabaseManager) GetTablesMetadata(tableNames []string) (map[string]ma
 
 // TableMetadata 表元数据结构
 type TableMetadata struct {
-	TableName   string           `json:"tableName"`
-	Comment     string           `json:"comment"`
-	DataSize    int64            `json:"dataSize"`
-	RowCount    int64            `json:"rowCount"`
-	ColumnCount int              `json:"columnCount"`
-	Columns     []ColumnMetadata `json:"columns"`
+	TableName   string            `json:"tableName"`
+	Comment     string            `json:"comment"`
+	DataSize    int64             `json:"dataSize"`
+	RowCount    int64             `json:"rowCount"`
+	ColumnCount int               `json:"columnCount"`
+	Columns     []ColumnMetadata  `json:"columns"`
+	Constraints []TableConstraint `json:"constraints"`
+	Indexes     []TableIndex      `json:"indexes"`
+
+	// ConstraintsUnknown 获取约束失败，保存时保留已有的约束记录
+	ConstraintsUnknown bool `json:"-"`
+	// IndexesUnknown 获取索引失败，保存时保留已有的索引记录
+	IndexesUnknown bool `json:"-"`
 }
 
 // ColumnMetadata 列元数据结构
 type ColumnMetadata struct {
-	ColumnName    string `json:"columnName"`
-	ColumnComment string `json:"columnComment"`
-	ColumnOrdinal int    `json:"columnOrdinal"`
-	ColumnType    string `json:"columnType"`
+	ColumnName       string  `json:"columnName"`
+	ColumnComment    string  `json:"columnComment"`
+	ColumnOrdinal    int     `json:"columnOrdinal"`
+	ColumnType       string  `json:"columnType"`
+	Nullable         bool    `json:"nullable"`
+	DefaultValue     *string `json:"defaultValue"`
+	CharLength       *int64  `json:"charLength"` // 字符类型的最大长度，-1 表示不限（如 varchar(max)）
+	NumericPrecision *int64  `json:"numericPrecision"`
+	NumericScale     *int64  `json:"numericScale"`
+}
+
+// ConstraintType 约束类型
+type ConstraintType string
+
+const (
+	ConstraintPrimaryKey ConstraintType = "PRIMARY KEY"
+	ConstraintUnique     ConstraintType = "UNIQUE"
+	ConstraintForeignKey ConstraintType = "FOREIGN KEY"
+)
+
+// TableConstraint 表约束（主键、唯一约束、外键）
+type TableConstraint struct {
+	ConstraintName    string         `json:"constraintName"`
+	ConstraintType    ConstraintType `json:"constraintType"`
+	Columns           []string       `json:"columns"`
+	ReferencedTable   string         `json:"referencedTable,omitempty"` // 外键引用的表，格式与 GetTables 返回的表名一致
+	ReferencedColumns []string       `json:"referencedColumns,omitempty"`
 }
 
 // GetTableColumns 获取表的列信息
@@ -207,6 +256,30 @@ func (dm *DatabaseManager) GetTableColumns(tableName string) ([]ColumnMetadata,
 	return dm.provider.GetTableColumns(context.Background(), dm.db, dm.config, tableName)
 }
 
+// GetTableConstraints 获取表的主键、唯一约束和外键
+func (dm *DatabaseManager) GetTableConstraints(tableName string) ([]TableConstraint, error) {
+	if dm.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if dm.provider == nil {
+		return nil, fmt.Errorf("database provider not initialized")
+	}
+
+	return dm.provider.GetTableConstraints(context.Background(), dm.db, dm.config, tableName)
+}
+
+// GetTableIndexes 获取表的索引定义
+func (dm *DatabaseManager) GetTableIndexes(tableName string) ([]TableIndex, error) {
+	if dm.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if dm.provider == nil {
+		return nil, fmt.Errorf("database provider not initialized")
+	}
+
+	return dm.provider.GetTableIndexes(context.Background(), dm.db, dm.config, tableName)
+}
+
 // GetTableFullMetadata 获取表的完整元数据信息（包括列信息）
 func (dm *DatabaseManager) GetTableFullMetadata(tableName string) (*TableMetadata, error) {
 	if dm.db == nil {
@@ -225,11 +298,30 @@ func (dm *DatabaseManager) GetTableFullMetadata(tableName string) (*TableMetadat
 		return nil, err
 	}
 
+	// 获取约束信息，失败时不影响表和列元数据
+	constraints, err := dm.GetTableConstraints(tableName)
+	constraintsUnknown := err != nil
+	if err != nil {
+		GetLogger().LogError("METADATA", fmt.Sprintf("获取约束信息失败 - %s: %s", tableName, err.Error()))
+	}
+
+	// 获取索引信息，失败时不影响表和列元数据
+	indexes, err := dm.GetTableIndexes(tableName)
+	indexesUnknown := err != nil
+	if err != nil {
+		GetLogger().LogError("METADATA", fmt.Sprintf("获取索引信息失败 - %s: %s", tableName, err.Error()))
+	}
+
 	// 构建完整元数据
 	metadata := &TableMetadata{
 		TableName:   tableName,
 		Columns:     columns,
 		ColumnCount: len(columns),
+		Constraints: constraints,
+		Indexes:     indexes,
+
+		ConstraintsUnknown: constraintsUnknown,
+		IndexesUnknown:     indexesUnknown,
 	}
 
 	// 从基本元数据中提取其他信息
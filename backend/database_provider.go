@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
@@ -22,10 +24,103 @@ type DatabaseProvider interface {
 	GetTables(ctx context.Context, db *sql.DB, config *DatabaseConfig) ([]string, error)
 	GetTableMetadata(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]interface{}, error)
 	GetTableColumns(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]ColumnMetadata, error)
+	GetTableConstraints(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableConstraint, error)
+	GetTableIndexes(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableIndex, error)
 	ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error)
+	// EstimateRowCount 从系统目录的统计信息读取估算行数，没有统计信息时返回 -1
+	EstimateRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error)
 	ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error)
+	ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error)
+	ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error)
+	ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error)
+	ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error)
+	// AggregateExpressions 返回统计项在列上的聚合表达式，供合并扫描使用；无法以聚合表达式计算时返回 false
+	AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool)
 	QuoteIdentifier(name string) string
 	QuoteTableName(config *DatabaseConfig, tableName string) string
+	// TableSource 返回按 sampling 采样读取表的 FROM 表达式
+	TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string
+	// TimestampLiteral 返回时间的 SQL 字面量，用于分区边界等场景
+	TimestampLiteral(t time.Time) string
+	LimitQuery(query string, limit int) string
+	Capabilities() ProviderCapabilities
+}
+
+// ProviderCapabilities 数据库方言的能力与限制
+type ProviderCapabilities struct {
+	// MaxSelectExpressions 单条 SELECT 的最大表达式数量，按列统计的查询据此拆分为多条
+	MaxSelectExpressions int
+	// UntypedTemporalColumns 日期时间列没有固定的存储格式（文本、整数时间戳均可），不能与 TimestampLiteral 比较，
+	// 这类列不能用作分区列或增量水位列
+	UntypedTemporalColumns bool
+	// LOBColumnTypes 无法（或不适合）参与 DISTINCT / GROUP BY 的大对象类型，键为 normalizeColumnType 的结果
+	// 同名类型在各方言中含义不同，如 SQL Server 旧式的 text 不能分组，MySQL、PostgreSQL 的 text 可以
+	LOBColumnTypes map[string]bool
+}
+
+// columnChunks 按单条 SELECT 的表达式上限将列划分为若干区间 [start, end)，每列占 width 个表达式
+func columnChunks(columnCount, width, maxExpressions int) [][2]int {
+	perChunk := 1
+	if width > 0 && maxExpressions > width {
+		perChunk = maxExpressions / width
+	}
+
+	chunks := make([][2]int, 0, (columnCount+perChunk-1)/perChunk)
+	for start := 0; start < columnCount; start += perChunk {
+		end := start + perChunk
+		if end > columnCount {
+			end = columnCount
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// estimateRowCount 优先使用统计信息估算行数，统计信息缺失或不可读时回退到 COUNT(*)
+// 返回的布尔值表示行数是否为估算值
+func estimateRowCount(ctx context.Context, db *sql.DB, provider DatabaseProvider, config *DatabaseConfig, tableName string) (int64, bool, error) {
+	rowCount, err := provider.EstimateRowCount(ctx, db, config, tableName)
+	if err == nil && rowCount >= 0 {
+		return rowCount, true, nil
+	}
+
+	rowCount, err = provider.ExecuteRowCount(ctx, db, config, tableName)
+	if err != nil {
+		return 0, false, err
+	}
+	return rowCount, false, nil
+}
+
+// providerResolver 由兼容多种数据库的提供者实现，连接后按服务端信息返回实际方言的提供者
+type providerResolver interface {
+	Resolve(ctx context.Context, db *sql.DB) (DatabaseProvider, error)
+}
+
+// tablePreparer 由扫描表数据前需要准备的提供者实现，如按需将文件导入缓存库
+// rules 为将要执行的规则，引用其他表的规则（如外键孤儿检查）需要同时准备被引用的表
+type tablePreparer interface {
+	PrepareTable(config *DatabaseConfig, tableName string, rules []TaskRuleConfig) error
+}
+
+// prepareTable 在扫描表数据前执行提供者的准备步骤，提供者不需要准备时直接返回
+func prepareTable(provider DatabaseProvider, config *DatabaseConfig, tableName string, rules []TaskRuleConfig) error {
+	preparer, ok := provider.(tablePreparer)
+	if !ok {
+		return nil
+	}
+	return preparer.PrepareTable(config, tableName, rules)
+}
+
+// TableStatistics 不扫描数据即可得到的全表统计
+type TableStatistics struct {
+	RowCount   int64
+	NullCounts map[string]int64 // 统计信息完整的列的空值数
+}
+
+// tableStatisticsProvider 由元数据中带有精确统计的提供者实现，如 Parquet 文件尾部的行组统计
+// 全表分析时行数、非空率等规则直接由统计计算，不扫描数据
+type tableStatisticsProvider interface {
+	TableStatistics(ctx context.Context, config *DatabaseConfig, tableName string) (*TableStatistics, error)
 }
 
 // baseProvider 为各方言提供默认实现
@@ -57,7 +152,293 @@ func resolveProvider(dbType string) (DatabaseProvider, error) {
 
 func init() {
 	registerProvider(&mysqlProvider{})
+	registerProvider(&mysqlProvider{flavor: mysqlFlavorMariaDB})
+	registerProvider(&mysqlProvider{flavor: mysqlFlavorTiDB})
+	registerProvider(&mysqlProvider{flavor: mysqlFlavorDoris})
+	registerProvider(&mysqlProvider{flavor: mysqlFlavorStarRocks})
 	registerProvider(&sqlServerProvider{}, "mssql", "sqlserver")
 	registerProvider(&oracleProvider{})
 	registerProvider(&postgresProvider{}, "postgres", "postgresql")
+	registerProvider(&sqliteProvider{}, "sqlite3")
+	registerProvider(&fileProvider{format: &csvFormat{}}, "tsv")
+	registerProvider(&parquetProvider{fileProvider{format: &parquetFormat{}}})
+}
+
+// typeModifierPattern 匹配列类型中的长度/精度修饰，如 varchar(255)、timestamp(6)
+var typeModifierPattern = regexp.MustCompile(`\([^)]*\)`)
+
+// normalizeColumnType 去除长度、精度及 unsigned 等修饰，返回小写的基础类型名
+func normalizeColumnType(columnType string) string {
+	normalized := strings.ToLower(strings.TrimSpace(columnType))
+	normalized = typeModifierPattern.ReplaceAllString(normalized, "")
+	normalized = strings.ReplaceAll(normalized, " unsigned", "")
+	normalized = strings.ReplaceAll(normalized, " zerofill", "")
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+// isLOBColumn 按提供者的方言判断列是否为大对象类型
+func isLOBColumn(provider DatabaseProvider, column ColumnMetadata) bool {
+	return provider.Capabilities().LOBColumnTypes[normalizeColumnType(column.ColumnType)]
+}
+
+// numericColumnTypes 可进行数值统计的列类型
+var numericColumnTypes = map[string]bool{
+	"tinyint":          true,
+	"smallint":         true,
+	"mediumint":        true,
+	"int":              true,
+	"integer":          true,
+	"bigint":           true,
+	"decimal":          true,
+	"numeric":          true,
+	"number":           true,
+	"float":            true,
+	"double":           true,
+	"double precision": true,
+	"real":             true,
+	"binary_float":     true,
+	"binary_double":    true,
+}
+
+// isNumericColumnType 判断列是否为数值类型
+func isNumericColumnType(columnType string) bool {
+	return numericColumnTypes[normalizeColumnType(columnType)]
+}
+
+// temporalColumnTypes 日期时间列类型
+var temporalColumnTypes = map[string]bool{
+	"date":                           true,
+	"datetime":                       true,
+	"datetime2":                      true,
+	"smalldatetime":                  true,
+	"datetimeoffset":                 true,
+	"timestamp":                      true,
+	"timestamp with time zone":       true,
+	"timestamp without time zone":    true,
+	"timestamp with local time zone": true,
+}
+
+// isTemporalColumnType 判断列是否为日期时间类型
+func isTemporalColumnType(columnType string) bool {
+	return temporalColumnTypes[normalizeColumnType(columnType)]
+}
+
+// stringColumnTypes 字符类型
+var stringColumnTypes = map[string]bool{
+	"char":              true,
+	"nchar":             true,
+	"character":         true,
+	"bpchar":            true,
+	"varchar":           true,
+	"nvarchar":          true,
+	"varchar2":          true,
+	"nvarchar2":         true,
+	"character varying": true,
+	"text":              true,
+	"tinytext":          true,
+	"citext":            true,
+}
+
+// fixedCharColumnTypes 定长字符类型，数据库会以空格补齐
+var fixedCharColumnTypes = map[string]bool{
+	"char":      true,
+	"nchar":     true,
+	"character": true,
+	"bpchar":    true,
+}
+
+// isStringColumnType 判断列是否为（非大对象的）字符类型
+func isStringColumnType(columnType string) bool {
+	return stringColumnTypes[normalizeColumnType(columnType)]
+}
+
+// isFixedCharColumnType 判断列是否为定长字符类型
+func isFixedCharColumnType(columnType string) bool {
+	return fixedCharColumnTypes[normalizeColumnType(columnType)]
+}
+
+// filterColumns 按条件筛选列
+func filterColumns(columns []ColumnMetadata, keep func(ColumnMetadata) bool) []ColumnMetadata {
+	filtered := make([]ColumnMetadata, 0, len(columns))
+	for _, column := range columns {
+		if keep(column) {
+			filtered = append(filtered, column)
+		}
+	}
+	return filtered
+}
+
+// selectColumns 按列名（忽略大小写）选取列，names 为空时返回全部列
+func selectColumns(columns []ColumnMetadata, names []string) []ColumnMetadata {
+	if len(names) == 0 {
+		return columns
+	}
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[strings.ToLower(name)] = true
+	}
+	return filterColumns(columns, func(column ColumnMetadata) bool {
+		return selected[strings.ToLower(column.ColumnName)]
+	})
+}
+
+// buildDistinctStats 根据总行数与各列去重计数构建去重统计结果
+func buildDistinctStats(columns []ColumnMetadata, rowCount sql.NullFloat64, counts []sql.NullFloat64) map[string]ColumnDistinctStats {
+	result := make(map[string]ColumnDistinctStats, len(columns))
+	for i, column := range columns {
+		stats := ColumnDistinctStats{}
+		if counts[i].Valid {
+			stats.DistinctCount = int64(counts[i].Float64)
+		}
+		if rowCount.Valid && rowCount.Float64 > 0 {
+			stats.DistinctRatio = clampRatio(float64(stats.DistinctCount) / rowCount.Float64)
+		}
+		result[column.ColumnName] = stats
+	}
+	return result
+}
+
+// nullableFloat 将可空浮点数转换为指针，NULL 返回 nil
+func nullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Float64
+	return &v
+}
+
+// numericStatsWidth 每个数值列对应的统计值个数：最小值、最大值、均值、标准差及各分位数
+func numericStatsWidth() int {
+	return 4 + len(numericPercentiles)
+}
+
+// buildNumericStats 按每列 [min, max, mean, stddev, p25, p50, p75, p95] 的布局解析统计值
+func buildNumericStats(columns []ColumnMetadata, values []sql.NullFloat64, approximate bool) map[string]NumericColumnStats {
+	width := numericStatsWidth()
+	result := make(map[string]NumericColumnStats, len(columns))
+	for i, column := range columns {
+		v := values[i*width : (i+1)*width]
+		result[column.ColumnName] = NumericColumnStats{
+			Min:         nullableFloat(v[0]),
+			Max:         nullableFloat(v[1]),
+			Mean:        nullableFloat(v[2]),
+			StdDev:      nullableFloat(v[3]),
+			P25:         nullableFloat(v[4]),
+			P50:         nullableFloat(v[5]),
+			P75:         nullableFloat(v[6]),
+			P95:         nullableFloat(v[7]),
+			Approximate: approximate,
+		}
+	}
+	return result
+}
+
+// stringStatsWidth 每个字符列对应的统计值个数
+const stringStatsWidth = 8
+
+// buildStringStats 按每列 [非空数, 最小长度, 平均长度, 最大长度, 空串数, 首部空白数, 尾部空白数, 全空白数] 的布局解析统计值
+func buildStringStats(columns []ColumnMetadata, values []sql.NullFloat64) map[string]StringColumnStats {
+	result := make(map[string]StringColumnStats, len(columns))
+	for i, column := range columns {
+		v := values[i*stringStatsWidth : (i+1)*stringStatsWidth]
+		stats := StringColumnStats{
+			NonNullCount:            int64(v[0].Float64),
+			MinLength:               nullableFloat(v[1]),
+			AvgLength:               nullableFloat(v[2]),
+			MaxLength:               nullableFloat(v[3]),
+			EmptyCount:              int64(v[4].Float64),
+			LeadingWhitespaceCount:  int64(v[5].Float64),
+			TrailingWhitespaceCount: int64(v[6].Float64),
+			WhitespaceOnlyCount:     int64(v[7].Float64),
+		}
+		if stats.NonNullCount > 0 {
+			stats.EmptyRate = clampRatio(float64(stats.EmptyCount) / float64(stats.NonNullCount))
+			stats.WhitespaceOnlyRate = clampRatio(float64(stats.WhitespaceOnlyCount) / float64(stats.NonNullCount))
+		}
+		result[column.ColumnName] = stats
+	}
+	return result
+}
+
+// columnDetails 列的可空性、默认值和长度/精度信息，各方言查询后统一转换
+type columnDetails struct {
+	nullable         sql.NullString
+	defaultValue     sql.NullString
+	charLength       sql.NullInt64
+	numericPrecision sql.NullInt64
+	numericScale     sql.NullInt64
+}
+
+// scanArgs 返回用于 rows.Scan 的字段指针，顺序为 可空标记、默认值、字符长度、精度、小数位
+func (d *columnDetails) scanArgs() []interface{} {
+	return []interface{}{&d.nullable, &d.defaultValue, &d.charLength, &d.numericPrecision, &d.numericScale}
+}
+
+// apply 将扫描结果写入列元数据，可空标记兼容 YES/NO 与 Y/N
+func (d *columnDetails) apply(column *ColumnMetadata) {
+	flag := strings.ToUpper(strings.TrimSpace(d.nullable.String))
+	column.Nullable = flag == "YES" || flag == "Y"
+	if d.defaultValue.Valid {
+		value := strings.TrimSpace(d.defaultValue.String)
+		column.DefaultValue = &value
+	}
+	column.CharLength = nullableInt(d.charLength)
+	column.NumericPrecision = nullableInt(d.numericPrecision)
+	column.NumericScale = nullableInt(d.numericScale)
+}
+
+// nullableInt 将可空整数转换为指针，NULL 返回 nil
+func nullableInt(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Int64
+	return &v
+}
+
+// normalizeConstraintType 将各方言的约束类型编码统一为 ConstraintType
+func normalizeConstraintType(code string) (ConstraintType, bool) {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "PRIMARY KEY", "P", "PK":
+		return ConstraintPrimaryKey, true
+	case "UNIQUE", "U", "UQ":
+		return ConstraintUnique, true
+	case "FOREIGN KEY", "F", "R":
+		return ConstraintForeignKey, true
+	}
+	return "", false
+}
+
+// scanTableConstraints 读取按约束名和列顺序排列的结果集
+// 每行依次为 约束名、约束类型、列名、引用表、引用列，同一约束的多列合并为一个 TableConstraint
+func scanTableConstraints(rows *sql.Rows) ([]TableConstraint, error) {
+	var constraints []TableConstraint
+	var current *TableConstraint
+
+	for rows.Next() {
+		var name, code, column, referencedTable, referencedColumn sql.NullString
+		if err := rows.Scan(&name, &code, &column, &referencedTable, &referencedColumn); err != nil {
+			return nil, err
+		}
+		constraintType, ok := normalizeConstraintType(code.String)
+		if !ok {
+			continue
+		}
+
+		if current == nil || current.ConstraintName != name.String || current.ConstraintType != constraintType {
+			constraints = append(constraints, TableConstraint{
+				ConstraintName: name.String,
+				ConstraintType: constraintType,
+			})
+			current = &constraints[len(constraints)-1]
+		}
+
+		current.Columns = append(current.Columns, column.String)
+		if constraintType == ConstraintForeignKey {
+			current.ReferencedTable = referencedTable.String
+			current.ReferencedColumns = append(current.ReferencedColumns, referencedColumn.String)
+		}
+	}
+
+	return constraints, rows.Err()
 }
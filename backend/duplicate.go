@@ -0,0 +1,225 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// defaultDuplicateSampleLimit 默认返回的重复键样本数量
+const defaultDuplicateSampleLimit = 10
+
+// KeyValueSample 键值样本（重复键、孤儿外键值等）
+type KeyValueSample struct {
+	Values map[string]*string `json:"values"` // 列名到键值，nil 表示 NULL
+	Count  int64              `json:"count"`
+}
+
+// DuplicateStats 指定列组合上的重复情况
+type DuplicateStats struct {
+	Columns         []string         `json:"columns"`
+	DuplicateGroups int64            `json:"duplicate_groups"` // 出现超过一次的组合数
+	DuplicateRows   int64            `json:"duplicate_rows"`   // 多余的重复行数（每组保留一行之外的行）
+	DuplicateRate   float64          `json:"duplicate_rate"`   // 多余重复行占总行数的比例
+	Samples         []KeyValueSample `json:"samples,omitempty"`
+}
+
+// DuplicateResult 重复行和重复键检测结果
+type DuplicateResult struct {
+	RowCount       int64           `json:"row_count"`
+	FullRow        *DuplicateStats `json:"full_row"`
+	SkippedColumns []string        `json:"skipped_columns,omitempty"` // 无法参与 GROUP BY 的大对象列
+	Key            *DuplicateStats `json:"key,omitempty"`
+}
+
+// DuplicateRule 重复行和重复键检测规则
+// KeyColumns 为候选业务键，表中缺少任一列或其中有大对象列时规则失败
+type DuplicateRule struct {
+	KeyColumns  []string
+	SampleLimit int
+}
+
+func (r *DuplicateRule) GetName() string {
+	return "duplicates"
+}
+
+func (r *DuplicateRule) GetDescription() string {
+	return "统计完全重复的行，并检测候选键列组合上的重复键及样本"
+}
+
+func (r *DuplicateRule) GetParameters() []RuleParameter {
+	return []RuleParameter{
+		{Name: "key_columns", Type: ParamStringList, Description: "候选业务键列，为空时只检测整行重复", Default: r.KeyColumns},
+		{Name: "sample_limit", Type: ParamInteger, Description: "返回的重复键样本数量", Default: r.SampleLimit, Min: paramBound(1), Max: paramBound(1000)},
+	}
+}
+
+func (r *DuplicateRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	rowCount, err := provider.ExecuteRowCount(ctx, db, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	keyColumns, err := resolveKeyColumns(provider, columns, params.StringList("key_columns", r.KeyColumns))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DuplicateResult{RowCount: rowCount}
+
+	var groupColumns []string
+	for _, column := range columns {
+		if isLOBColumn(provider, column) {
+			result.SkippedColumns = append(result.SkippedColumns, column.ColumnName)
+			continue
+		}
+		groupColumns = append(groupColumns, column.ColumnName)
+	}
+
+	if len(groupColumns) > 0 {
+		result.FullRow, err = queryDuplicateStats(ctx, db, config, provider, tableName, groupColumns, rowCount)
+		if err != nil {
+			return nil, fmt.Errorf("full row duplicates: %w", err)
+		}
+	}
+
+	if len(keyColumns) == 0 {
+		return result, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.Key, err = queryDuplicateStats(ctx, db, config, provider, tableName, keyColumns, rowCount)
+	if err != nil {
+		return nil, fmt.Errorf("key duplicates: %w", err)
+	}
+	if result.Key.DuplicateGroups > 0 {
+		limit := params.Int("sample_limit", r.SampleLimit)
+		if limit <= 0 {
+			limit = defaultDuplicateSampleLimit
+		}
+		result.Key.Samples, err = queryDuplicateSamples(ctx, db, config, provider, tableName, keyColumns, limit)
+		if err != nil {
+			return nil, fmt.Errorf("key duplicate samples: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// resolveKeyColumns 按表中实际列名（忽略大小写）解析候选键，缺列或包含大对象列时返回错误
+func resolveKeyColumns(provider DatabaseProvider, columns []ColumnMetadata, keyColumns []string) ([]string, error) {
+	if len(keyColumns) == 0 {
+		return nil, nil
+	}
+
+	byName := make(map[string]ColumnMetadata, len(columns))
+	for _, column := range columns {
+		byName[strings.ToLower(column.ColumnName)] = column
+	}
+
+	resolved := make([]string, 0, len(keyColumns))
+	for _, name := range keyColumns {
+		column, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("key column %s not found", name)
+		}
+		if isLOBColumn(provider, column) {
+			return nil, fmt.Errorf("key column %s is a large object column (%s) and cannot be grouped", column.ColumnName, column.ColumnType)
+		}
+		resolved = append(resolved, column.ColumnName)
+	}
+	return resolved, nil
+}
+
+// quoteColumnList 引用列名并以逗号连接
+func quoteColumnList(provider DatabaseProvider, columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, provider.QuoteIdentifier(column))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// queryDuplicateStats 统计指定列组合上的重复组数和重复行数
+func queryDuplicateStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, columns []string, rowCount int64) (*DuplicateStats, error) {
+	query := fmt.Sprintf(
+		"SELECT COUNT(*), SUM(cnt) FROM (SELECT COUNT(*) AS cnt FROM %s GROUP BY %s HAVING COUNT(*) > 1) dup",
+		provider.QuoteTableName(config, tableName), quoteColumnList(provider, columns),
+	)
+
+	var groups int64
+	var rows sql.NullInt64
+	if err := db.QueryRowContext(ctx, query).Scan(&groups, &rows); err != nil {
+		return nil, err
+	}
+
+	stats := &DuplicateStats{
+		Columns:         columns,
+		DuplicateGroups: groups,
+	}
+	if rows.Valid {
+		stats.DuplicateRows = rows.Int64 - groups
+	}
+	if rowCount > 0 {
+		stats.DuplicateRate = float64(stats.DuplicateRows) / float64(rowCount)
+	}
+	return stats, nil
+}
+
+// queryDuplicateSamples 按重复次数降序返回重复键样本
+func queryDuplicateSamples(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, columns []string, limit int) ([]KeyValueSample, error) {
+	columnList := quoteColumnList(provider, columns)
+	query := fmt.Sprintf(
+		"SELECT %s, COUNT(*) AS cnt FROM %s GROUP BY %s HAVING COUNT(*) > 1 ORDER BY COUNT(*) DESC",
+		columnList, provider.QuoteTableName(config, tableName), columnList,
+	)
+
+	return queryKeyValueSamples(ctx, db, provider.LimitQuery(query, limit), columns)
+}
+
+// queryKeyValueSamples 执行返回 键列..., 计数 的查询并解析为样本
+func queryKeyValueSamples(ctx context.Context, db *sql.DB, query string, columns []string) ([]KeyValueSample, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, 0, len(columns)+1)
+	for i := range values {
+		scanArgs = append(scanArgs, &values[i])
+	}
+
+	var samples []KeyValueSample
+	for rows.Next() {
+		var sample KeyValueSample
+		if err := rows.Scan(append(scanArgs, &sample.Count)...); err != nil {
+			return nil, err
+		}
+		sample.Values = make(map[string]*string, len(columns))
+		for i, column := range columns {
+			if values[i].Valid {
+				value := values[i].String
+				sample.Values[column] = &value
+			} else {
+				sample.Values[column] = nil
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
@@ -0,0 +1,467 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stagedFilesTable 缓存库中记录已导入文件的表
+const stagedFilesTable = "mole_staged_files"
+
+// fileStagingLocks 按缓存库路径串行化导入，避免并发任务重复导入同一文件
+var fileStagingLocks sync.Map
+
+// stagedFile 文件类数据源中作为一张表的文件或数据集目录
+type stagedFile struct {
+	table   string
+	path    string
+	parts   []string // 组成该表的数据文件，单个文件时为其自身
+	size    int64
+	modTime int64
+}
+
+// fileFormat 文件类数据源的格式
+type fileFormat interface {
+	// name 格式名称，同时作为数据库类型和缓存目录名
+	name() string
+	// version 导入方式的版本，变化后已缓存的文件会重新导入
+	version() int
+	// extensions 目录中作为表读取的文件扩展名（小写，含点）
+	extensions() []string
+	// datasets 子目录中的多个文件是否合并为一张表（如 Spark、Hive 输出的分片文件）
+	datasets() bool
+	// importFile 在事务中重建文件对应的表并写入全部数据，返回行数
+	importFile(tx *sql.Tx, file stagedFile) (int64, error)
+}
+
+// fileProvider 将目录中的数据文件作为表
+// 连接时读取新增或变更的文件并导入本地 SQLite 缓存库，之后按 SQLite 方言分析；文件大小和修改时间不变时复用缓存
+type fileProvider struct {
+	sqliteProvider
+	format fileFormat
+}
+
+func (p *fileProvider) Name() string {
+	return p.format.name()
+}
+
+// BuildDSN 返回缓存库的只读连接，导入在 Configure（Parquet 为 PrepareTable）中完成
+func (p *fileProvider) BuildDSN(config *DatabaseConfig) (string, error) {
+	cachePath, err := p.cachePath(config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?_query_only=1&_busy_timeout=5000", cachePath), nil
+}
+
+// Configure 在首次查询前将新增或变更的文件导入缓存库，并删除已不存在的文件对应的表
+// 导入失败的文件不作为表列出，不影响其他文件
+func (p *fileProvider) Configure(_ *sql.DB, config *DatabaseConfig) error {
+	files, err := p.configuredFiles(config)
+	if err != nil {
+		return err
+	}
+	_, err = p.stageFiles(config, files, nil)
+	return err
+}
+
+func (p *fileProvider) GetTables(ctx context.Context, db *sql.DB, _ *DatabaseConfig) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT table_name FROM %s ORDER BY table_name", stagedFilesTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		tables = append(tables, tableName)
+	}
+	return tables, rows.Err()
+}
+
+// GetTableMetadata 行数在导入时统计，数据大小为文件大小，注释为文件路径
+func (p *fileProvider) GetTableMetadata(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+
+	var path string
+	var size, rowCount int64
+	err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT path, size, row_count FROM %s WHERE table_name = ?", stagedFilesTable), tableName).Scan(&path, &size, &rowCount)
+	if err != nil {
+		return nil, err
+	}
+	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = false
+
+	var columnCount int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?)", tableName).Scan(&columnCount)
+	if err != nil {
+		return nil, err
+	}
+	metadata["column_count"] = columnCount
+	metadata["data_size"] = size
+	metadata["comment"] = path
+
+	return metadata, nil
+}
+
+// EstimateRowCount 返回导入时统计的行数
+func (p *fileProvider) EstimateRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
+	var rowCount int64
+	err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT row_count FROM %s WHERE table_name = ?", stagedFilesTable), tableName).Scan(&rowCount)
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+// sourcePath 返回连接配置的文件或目录的绝对路径
+func (p *fileProvider) sourcePath(config *DatabaseConfig) (string, error) {
+	if strings.TrimSpace(config.FilePath) == "" {
+		return "", fmt.Errorf("%s file or directory path is required", p.format.name())
+	}
+	return filepath.Abs(strings.TrimSpace(config.FilePath))
+}
+
+// cachePath 返回连接对应的缓存库路径，按格式和数据文件或目录的绝对路径区分
+func (p *fileProvider) cachePath(config *DatabaseConfig) (string, error) {
+	source, err := p.sourcePath(config)
+	if err != nil {
+		return "", err
+	}
+	dataDir, err := getAppDataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get app data directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(dataDir, "cache", p.format.name(), hex.EncodeToString(sum[:8])+".db"), nil
+}
+
+// configuredFiles 列出连接配置的文件或目录中作为表的文件
+func (p *fileProvider) configuredFiles(config *DatabaseConfig) ([]stagedFile, error) {
+	source, err := p.sourcePath(config)
+	if err != nil {
+		return nil, err
+	}
+	return p.listFiles(source)
+}
+
+// findFile 返回表对应的文件
+func (p *fileProvider) findFile(config *DatabaseConfig, tableName string) (stagedFile, error) {
+	files, err := p.configuredFiles(config)
+	if err != nil {
+		return stagedFile{}, err
+	}
+	for _, file := range files {
+		if file.table == tableName {
+			return file, nil
+		}
+	}
+	return stagedFile{}, fmt.Errorf("table %s not found", tableName)
+}
+
+// listFiles 列出路径下扩展名匹配的文件；路径为单个文件时只包含该文件
+// 格式支持数据集时，包含匹配文件的子目录作为一张表，表名为目录名
+// 文件的表名为去掉扩展名的文件名，重名时使用完整文件名；目录中无法读取的文件记录日志后跳过
+func (p *fileProvider) listFiles(path string) ([]stagedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s path not accessible: %w", p.format.name(), err)
+	}
+	if !info.IsDir() {
+		file, err := newStagedFile(path, []string{path})
+		if err != nil {
+			return nil, err
+		}
+		file.table = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if file.table == "" || strings.EqualFold(file.table, stagedFilesTable) {
+			file.table = filepath.Base(path)
+		}
+		return []stagedFile{*file}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []stagedFile
+	used := map[string]bool{stagedFilesTable: true}
+	for _, entry := range entries {
+		entryPath := filepath.Join(path, entry.Name())
+		var table string
+		var parts []string
+		switch {
+		case entry.IsDir() && p.format.datasets():
+			if parts, err = p.listDataFiles(entryPath); err != nil {
+				GetLogger().LogError("STAGE", fmt.Sprintf("跳过无法读取的目录 - %s: %s", entryPath, err.Error()))
+				continue
+			}
+			table = entry.Name()
+		case !entry.IsDir() && p.isDataFile(entry.Name()):
+			parts = []string{entryPath}
+			table = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		if table == "" || used[strings.ToLower(table)] {
+			table = entry.Name()
+		}
+		used[strings.ToLower(table)] = true
+
+		file, err := newStagedFile(entryPath, parts)
+		if err != nil {
+			GetLogger().LogError("STAGE", fmt.Sprintf("跳过无法读取的文件 - %s: %s", entryPath, err.Error()))
+			continue
+		}
+		file.table = table
+		files = append(files, *file)
+	}
+	return files, nil
+}
+
+// listDataFiles 列出数据集目录中的数据文件，跳过隐藏文件和 _SUCCESS 等以下划线开头的标记文件
+func (p *fileProvider) listDataFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var parts []string
+	for _, entry := range entries {
+		if !entry.IsDir() && p.isDataFile(entry.Name()) {
+			parts = append(parts, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return parts, nil
+}
+
+// isDataFile 判断文件扩展名是否属于该格式
+func (p *fileProvider) isDataFile(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range p.format.extensions() {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// newStagedFile 汇总表对应文件的大小和修改时间；数据集的修改时间取目录及各文件的最大值，增删文件也会触发重新导入
+func newStagedFile(path string, parts []string) (*stagedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &stagedFile{path: path, parts: parts, modTime: info.ModTime().UnixNano()}
+	for _, part := range parts {
+		info, err := os.Stat(part)
+		if err != nil {
+			return nil, err
+		}
+		file.size += info.Size()
+		file.modTime = max(file.modTime, info.ModTime().UnixNano())
+	}
+	return file, nil
+}
+
+// stageFiles 将新增或变更的文件导入缓存库，并删除已不存在的文件对应的表
+// files 为全部文件，tables 不为 nil 时只导入其中的表
+// 单个文件导入失败时记录日志并删除其旧的缓存，其余文件照常导入；返回导入失败的表及错误
+func (p *fileProvider) stageFiles(config *DatabaseConfig, files []stagedFile, tables map[string]bool) (map[string]error, error) {
+	logger := GetLogger()
+	logger.SetModuleName(strings.ToUpper(p.format.name()))
+
+	cachePath, err := p.cachePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, _ := fileStagingLocks.LoadOrStore(cachePath, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s cache directory: %w", p.format.name(), err)
+	}
+	db, err := sql.Open(sqliteDriverName, cachePath+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			table_name TEXT PRIMARY KEY,
+			path TEXT NOT NULL,
+			size INTEGER NOT NULL,
+			mod_time INTEGER NOT NULL,
+			version INTEGER NOT NULL,
+			row_count INTEGER NOT NULL
+		)
+	`, stagedFilesTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s cache: %w", p.format.name(), err)
+	}
+
+	staged, err := loadStagedFiles(db)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]error)
+	current := make(map[string]bool, len(files))
+	for _, file := range files {
+		current[file.table] = true
+		if tables != nil && !tables[file.table] {
+			continue
+		}
+		if previous, ok := staged[file.table]; ok && previous.matches(file, p.format.version()) {
+			continue
+		}
+		started := time.Now()
+		rowCount, err := p.importFile(db, file)
+		if err != nil {
+			logger.LogError("STAGE", fmt.Sprintf("导入文件失败，跳过 - %s: %s", file.path, err.Error()))
+			failed[file.table] = fmt.Errorf("failed to import %s: %w", filepath.Base(file.path), err)
+			// 旧的缓存已与文件不一致，删除以免按过期数据分析
+			if _, ok := staged[file.table]; ok {
+				if err := dropStagedTable(db, file.table); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		logger.LogInfo("STAGE", fmt.Sprintf("导入文件 - %s, 行数: %d, 耗时: %v", file.path, rowCount, time.Since(started)))
+	}
+
+	for table := range staged {
+		if current[table] {
+			continue
+		}
+		if err := dropStagedTable(db, table); err != nil {
+			return nil, err
+		}
+	}
+	return failed, nil
+}
+
+// dropStagedTable 删除缓存库中的表及其导入记录
+func dropStagedTable(db *sql.DB, table string) error {
+	if _, err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteSQLiteIdentifier(table))); err != nil {
+		return err
+	}
+	_, err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE table_name = ?", stagedFilesTable), table)
+	return err
+}
+
+// importFile 在一个事务中导入文件并记录导入信息，返回行数
+func (p *fileProvider) importFile(db *sql.DB, file stagedFile) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rowCount, err := p.format.importFile(tx, file)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`
+		INSERT OR REPLACE INTO %s (table_name, path, size, mod_time, version, row_count)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, stagedFilesTable), file.table, file.path, file.size, file.modTime, p.format.version(), rowCount)
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, tx.Commit()
+}
+
+// previouslyStagedFile 缓存库中已导入的文件
+type previouslyStagedFile struct {
+	path    string
+	size    int64
+	modTime int64
+	version int
+}
+
+// matches 文件未变化且导入方式相同时可复用缓存
+func (s previouslyStagedFile) matches(file stagedFile, version int) bool {
+	return s.path == file.path && s.size == file.size && s.modTime == file.modTime && s.version == version
+}
+
+// loadStagedFiles 读取缓存库中已导入的文件
+func loadStagedFiles(db *sql.DB) (map[string]previouslyStagedFile, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT table_name, path, size, mod_time, version FROM %s", stagedFilesTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	staged := make(map[string]previouslyStagedFile)
+	for rows.Next() {
+		var table string
+		var file previouslyStagedFile
+		if err := rows.Scan(&table, &file.path, &file.size, &file.modTime, &file.version); err != nil {
+			return nil, err
+		}
+		staged[table] = file
+	}
+	return staged, rows.Err()
+}
+
+// uniqueColumnNames 空列名按位置命名，重名（不区分大小写）追加序号
+func uniqueColumnNames(names []string) []string {
+	unique := make([]string, len(names))
+	used := make(map[string]bool, len(names))
+	for i, name := range names {
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		candidate := name
+		for suffix := 2; used[strings.ToLower(candidate)]; suffix++ {
+			candidate = fmt.Sprintf("%s_%d", name, suffix)
+		}
+		used[strings.ToLower(candidate)] = true
+		unique[i] = candidate
+	}
+	return unique
+}
+
+// createStagedTable 重建文件对应的表，返回插入一行的预编译语句
+func createStagedTable(tx *sql.Tx, table string, definitions []string) (*sql.Stmt, error) {
+	quoted := quoteSQLiteIdentifier(table)
+	placeholders := make([]string, len(definitions))
+	for i := range placeholders {
+		placeholders[i] = "?"
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoted)); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoted, strings.Join(definitions, ", "))); err != nil {
+		return nil, err
+	}
+	return tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoted, strings.Join(placeholders, ", ")))
+}
+
+// quoteSQLiteIdentifier 引用缓存库中的表名、列名
+func quoteSQLiteIdentifier(name string) string {
+	return (&sqliteProvider{}).QuoteIdentifier(name)
+}
@@ -0,0 +1,332 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// AggregateMetric 能以纯聚合表达式计算、可合并到同一次表扫描的统计项
+type AggregateMetric string
+
+const (
+	AggregateRowCount       AggregateMetric = "row_count"       // 1 个表达式，不区分列
+	AggregateNonNullRate    AggregateMetric = "non_null_rate"   // 1 个表达式
+	AggregateDistinctCount  AggregateMetric = "distinct_count"  // 1 个表达式
+	AggregateNumericStats   AggregateMetric = "numeric_stats"   // numericStatsWidth() 个表达式
+	AggregateStringStats    AggregateMetric = "string_stats"    // stringStatsWidth 个表达式
+	AggregateNumericMoments AggregateMetric = "numeric_moments" // 5 个表达式 [min, max, mean, stddev, 非空数]，可跨分区合并
+)
+
+// aggregatePlan 规则在合并扫描中的聚合表达式
+// groups 中同一组的表达式（通常对应同一列）总在同一条查询中执行，build 按组接收查询结果
+type aggregatePlan struct {
+	groups [][]string
+	build  func(values [][]sql.NullFloat64) interface{}
+}
+
+// aggregateRule 能以聚合表达式参与合并扫描的规则，返回 nil 时规则单独执行
+type aggregateRule interface {
+	PlanAggregates(ctx context.Context, provider DatabaseProvider, columns []ColumnMetadata, params RuleParams) *aggregatePlan
+}
+
+// fusedSlot 表达式组在批次查询结果中的位置
+type fusedSlot struct {
+	rule   string
+	group  int
+	offset int
+}
+
+// fusedBatch 一条合并查询
+type fusedBatch struct {
+	expressions []string
+	slots       []fusedSlot
+}
+
+// executeAggregatePlans 将多个规则的聚合计划按列分批合并为尽量少的查询
+// 每条查询的表达式数量不超过方言的 MaxSelectExpressions；返回执行成功的规则输出；某条查询失败时，涉及的规则记录在 failed 中，由调用方决定是否单独执行
+func executeAggregatePlans(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, names []string, plans map[string]*aggregatePlan) (map[string]interface{}, map[string]error) {
+	maxExpressions := provider.Capabilities().MaxSelectExpressions
+	var batches []*fusedBatch
+	current := &fusedBatch{}
+	values := make(map[string][][]sql.NullFloat64, len(names))
+	for _, name := range names {
+		plan := plans[name]
+		values[name] = make([][]sql.NullFloat64, len(plan.groups))
+		for i, group := range plan.groups {
+			if len(current.expressions) > 0 && len(current.expressions)+len(group) > maxExpressions {
+				batches = append(batches, current)
+				current = &fusedBatch{}
+			}
+			current.slots = append(current.slots, fusedSlot{rule: name, group: i, offset: len(current.expressions)})
+			current.expressions = append(current.expressions, group...)
+		}
+	}
+	if len(current.expressions) > 0 {
+		batches = append(batches, current)
+	}
+
+	failed := make(map[string]error)
+	for _, batch := range batches {
+		row, err := queryAggregates(ctx, db, config, provider, tableName, batch.expressions)
+		if err != nil {
+			for _, slot := range batch.slots {
+				failed[slot.rule] = err
+			}
+			continue
+		}
+		for _, slot := range batch.slots {
+			size := len(plans[slot.rule].groups[slot.group])
+			values[slot.rule][slot.group] = row[slot.offset : slot.offset+size]
+		}
+	}
+
+	outputs := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if _, ok := failed[name]; ok {
+			continue
+		}
+		outputs[name] = plans[name].build(values[name])
+	}
+	return outputs, failed
+}
+
+// executeAggregatePlan 单独执行一个聚合计划
+func executeAggregatePlan(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, plan *aggregatePlan) (interface{}, error) {
+	outputs, failed := executeAggregatePlans(ctx, db, config, provider, tableName, []string{""}, map[string]*aggregatePlan{"": plan})
+	if err, ok := failed[""]; ok {
+		return nil, err
+	}
+	return outputs[""], nil
+}
+
+// queryAggregates 在一次表扫描中计算全部聚合表达式
+func queryAggregates(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, expressions []string) ([]sql.NullFloat64, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(expressions, ", "), sampledTableRef(ctx, provider, config, tableName))
+
+	values := make([]sql.NullFloat64, len(expressions))
+	scanArgs := make([]interface{}, len(expressions))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := db.QueryRowContext(ctx, query).Scan(scanArgs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// columnAggregatePlan 按列生成统计项的聚合计划，任一列无法用聚合表达式计算时返回 nil
+func columnAggregatePlan(provider DatabaseProvider, metric AggregateMetric, columns []ColumnMetadata, build func(values [][]sql.NullFloat64) interface{}) *aggregatePlan {
+	plan := &aggregatePlan{groups: make([][]string, 0, len(columns)), build: build}
+	for _, column := range columns {
+		expressions, ok := provider.AggregateExpressions(metric, column)
+		if !ok {
+			return nil
+		}
+		plan.groups = append(plan.groups, expressions)
+	}
+	return plan
+}
+
+// nonNullRatePlan 各列的非空率
+func nonNullRatePlan(provider DatabaseProvider, columns []ColumnMetadata) *aggregatePlan {
+	return columnAggregatePlan(provider, AggregateNonNullRate, columns, func(values [][]sql.NullFloat64) interface{} {
+		result := make(map[string]float64, len(columns))
+		for i, column := range columns {
+			val := 0.0
+			if values[i][0].Valid {
+				val = clampRatio(values[i][0].Float64)
+			}
+			result[column.ColumnName] = val
+		}
+		return result
+	})
+}
+
+// distinctCountPlan 各列的去重数量，首组为表行数；大对象类型无法参与 DISTINCT，跳过
+func distinctCountPlan(provider DatabaseProvider, columns []ColumnMetadata) *aggregatePlan {
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return !isLOBColumn(provider, column)
+	})
+	plan := columnAggregatePlan(provider, AggregateDistinctCount, columns, nil)
+	if plan == nil {
+		return nil
+	}
+	if len(columns) == 0 {
+		plan.build = func(_ [][]sql.NullFloat64) interface{} {
+			return map[string]ColumnDistinctStats{}
+		}
+		return plan
+	}
+
+	rowCount, _ := provider.AggregateExpressions(AggregateRowCount, ColumnMetadata{})
+	plan.groups = append([][]string{rowCount}, plan.groups...)
+	plan.build = func(values [][]sql.NullFloat64) interface{} {
+		counts := make([]sql.NullFloat64, len(columns))
+		for i := range columns {
+			counts[i] = values[i+1][0]
+		}
+		return buildDistinctStats(columns, values[0][0], counts)
+	}
+	return plan
+}
+
+// numericStatsPlan 数值列统计，分位数无法以聚合表达式计算的方言返回 nil
+func numericStatsPlan(provider DatabaseProvider, columns []ColumnMetadata) *aggregatePlan {
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return isNumericColumnType(column.ColumnType)
+	})
+	return columnAggregatePlan(provider, AggregateNumericStats, columns, func(values [][]sql.NullFloat64) interface{} {
+		return buildNumericStats(columns, flattenAggregateValues(values), false)
+	})
+}
+
+// stringStatsPlan 字符列长度与空白统计；方言中的大对象类型（如 SQL Server 旧式的 text 不能用于 LEN、LIKE）跳过
+func stringStatsPlan(provider DatabaseProvider, columns []ColumnMetadata) *aggregatePlan {
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return isStringColumnType(column.ColumnType) && !isLOBColumn(provider, column)
+	})
+	return columnAggregatePlan(provider, AggregateStringStats, columns, func(values [][]sql.NullFloat64) interface{} {
+		return buildStringStats(columns, flattenAggregateValues(values))
+	})
+}
+
+// flattenAggregateValues 将按组的结果展开为连续的结果
+func flattenAggregateValues(values [][]sql.NullFloat64) []sql.NullFloat64 {
+	var flattened []sql.NullFloat64
+	for _, group := range values {
+		flattened = append(flattened, group...)
+	}
+	return flattened
+}
+
+// executeNonNullRate 各方言 ExecuteNonNullRate 的通用实现
+func executeNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (map[string]float64, error) {
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	output, err := executeAggregatePlan(ctx, db, config, provider, tableName, nonNullRatePlan(provider, columns))
+	if err != nil {
+		return nil, err
+	}
+	return output.(map[string]float64), nil
+}
+
+// executeDistinctCount 各方言 ExecuteDistinctCount 的通用实现
+func executeDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (map[string]ColumnDistinctStats, error) {
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	output, err := executeAggregatePlan(ctx, db, config, provider, tableName, distinctCountPlan(provider, columns))
+	if err != nil {
+		return nil, err
+	}
+	return output.(map[string]ColumnDistinctStats), nil
+}
+
+// executeNumericStats 分位数可作为聚合函数计算的方言（PostgreSQL、Oracle）的 ExecuteNumericStats 实现
+func executeNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (map[string]NumericColumnStats, error) {
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	plan := numericStatsPlan(provider, columns)
+	if plan == nil {
+		return nil, fmt.Errorf("numeric stats are not supported by aggregate expressions on %s", provider.Name())
+	}
+	output, err := executeAggregatePlan(ctx, db, config, provider, tableName, plan)
+	if err != nil {
+		return nil, err
+	}
+	return output.(map[string]NumericColumnStats), nil
+}
+
+// executeNearestRankNumericStats 没有 PERCENTILE_CONT 的方言（MySQL、SQLite）的 ExecuteNumericStats 实现
+// 基础统计合并扫描，分位数按列排序后取最近秩（nearest-rank）作为近似值
+func executeNearestRankNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (map[string]NumericColumnStats, error) {
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return isNumericColumnType(column.ColumnType)
+	})
+	if len(columns) == 0 {
+		return map[string]NumericColumnStats{}, nil
+	}
+
+	width := numericStatsWidth()
+	values := make([]sql.NullFloat64, len(columns)*width)
+	plan := columnAggregatePlan(provider, AggregateNumericMoments, columns, func(moments [][]sql.NullFloat64) interface{} {
+		// 矩的布局为 [min, max, mean, stddev, 非空数]，前四项即数值统计的前四项
+		for i := range columns {
+			copy(values[i*width:i*width+4], moments[i][:4])
+		}
+		return nil
+	})
+	if plan == nil {
+		return nil, fmt.Errorf("numeric moments are not supported by aggregate expressions on %s", provider.Name())
+	}
+	if _, err := executeAggregatePlan(ctx, db, config, provider, tableName, plan); err != nil {
+		return nil, err
+	}
+
+	// 首个满足 rn >= p * cnt 的值即秩为 CEIL(p * cnt) 的值，不依赖 CEIL（SQLite 默认没有数学函数）
+	tableRef := sampledTableRef(ctx, provider, config, tableName)
+	for i, column := range columns {
+		col := provider.QuoteIdentifier(column.ColumnName)
+		percentileParts := make([]string, 0, len(numericPercentiles))
+		percentileArgs := make([]interface{}, 0, len(numericPercentiles))
+		for j, percentile := range numericPercentiles {
+			percentileParts = append(percentileParts, fmt.Sprintf("MIN(CASE WHEN rn >= %g * cnt THEN v END)", percentile))
+			percentileArgs = append(percentileArgs, &values[i*width+4+j])
+		}
+
+		query := fmt.Sprintf(
+			"SELECT %s FROM (SELECT %s AS v, ROW_NUMBER() OVER (ORDER BY %s) AS rn, COUNT(*) OVER () AS cnt FROM %s WHERE %s IS NOT NULL) ranked",
+			strings.Join(percentileParts, ", "), col, col, tableRef, col,
+		)
+		if err := db.QueryRowContext(ctx, query).Scan(percentileArgs...); err != nil {
+			return nil, err
+		}
+	}
+
+	return buildNumericStats(columns, values, true), nil
+}
+
+// executeStringStats 各方言 ExecuteStringStats 的通用实现
+func executeStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (map[string]StringColumnStats, error) {
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	output, err := executeAggregatePlan(ctx, db, config, provider, tableName, stringStatsPlan(provider, columns))
+	if err != nil {
+		return nil, err
+	}
+	return output.(map[string]StringColumnStats), nil
+}
+
+type tableColumnsContextKey struct{}
+
+// cachedTableColumns 一次表分析中共享的列信息
+type cachedTableColumns struct {
+	tableName string
+	columns   []ColumnMetadata
+}
+
+// withTableColumns 将表的列信息附加到 context，同一次分析中的规则不再重复查询
+func withTableColumns(ctx context.Context, tableName string, columns []ColumnMetadata) context.Context {
+	return context.WithValue(ctx, tableColumnsContextKey{}, &cachedTableColumns{tableName: tableName, columns: columns})
+}
+
+// tableColumns 优先使用 context 中缓存的列信息，未缓存时查询数据库
+func tableColumns(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) ([]ColumnMetadata, error) {
+	if cached, ok := ctx.Value(tableColumnsContextKey{}).(*cachedTableColumns); ok && cached.tableName == tableName {
+		return cached.columns, nil
+	}
+	return provider.GetTableColumns(ctx, db, config, tableName)
+}
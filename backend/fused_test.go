@@ -0,0 +1,34 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringStatsPlanSkipsLOBColumns(t *testing.T) {
+	columns := []ColumnMetadata{
+		{ColumnName: "name", ColumnType: "nvarchar(50)"},
+		{ColumnName: "body", ColumnType: "text"},
+		{ColumnName: "amount", ColumnType: "int"},
+	}
+
+	// SQL Server 旧式的 text 不能用于 LEN，只统计 name
+	plan := stringStatsPlan(&sqlServerProvider{}, columns)
+	if plan == nil {
+		t.Fatal("sqlserver: no plan")
+	}
+	if len(plan.groups) != 1 {
+		t.Fatalf("sqlserver: %d column groups, want 1", len(plan.groups))
+	}
+	for _, expression := range plan.groups[0] {
+		if strings.Contains(expression, "[body]") {
+			t.Errorf("sqlserver: text column in expression %s", expression)
+		}
+	}
+
+	// PostgreSQL 的 text 是普通字符类型
+	plan = stringStatsPlan(&postgresProvider{}, columns)
+	if plan == nil || len(plan.groups) != 2 {
+		t.Fatalf("postgres: want name and body in the plan, got %+v", plan)
+	}
+}
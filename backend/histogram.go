@@ -0,0 +1,245 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HistogramMode 直方图分桶方式
+type HistogramMode string
+
+const (
+	HistogramEqualWidth  HistogramMode = "equal_width"  // 等宽分桶
+	HistogramEqualHeight HistogramMode = "equal_height" // 等高（等频）分桶
+)
+
+// defaultHistogramBins 默认分桶数量
+const defaultHistogramBins = 10
+
+// HistogramBin 直方图的单个分桶
+type HistogramBin struct {
+	Lower      float64 `json:"lower"`
+	Upper      float64 `json:"upper"`
+	LowerLabel string  `json:"lower_label,omitempty"` // 日期时间列的下界（RFC3339）
+	UpperLabel string  `json:"upper_label,omitempty"` // 日期时间列的上界（RFC3339）
+	Count      int64   `json:"count"`
+}
+
+// ColumnHistogram 列的值分布直方图
+// 日期时间列按 Unix 秒数分桶，Lower/Upper 为秒数，同时给出可读标签
+type ColumnHistogram struct {
+	Mode         HistogramMode  `json:"mode"`
+	Temporal     bool           `json:"temporal"`
+	Min          *float64       `json:"min"`
+	Max          *float64       `json:"max"`
+	NonNullCount int64          `json:"non_null_count"`
+	Bins         []HistogramBin `json:"bins"`
+}
+
+// HistogramRule 值分布直方图规则，适用于数值列和日期时间列
+type HistogramRule struct {
+	Bins int
+	Mode HistogramMode
+}
+
+// NewHistogramRule 创建直方图规则，参数非法时使用默认值
+func NewHistogramRule(bins int, mode HistogramMode) *HistogramRule {
+	if bins <= 0 {
+		bins = defaultHistogramBins
+	}
+	if mode != HistogramEqualHeight {
+		mode = HistogramEqualWidth
+	}
+	return &HistogramRule{Bins: bins, Mode: mode}
+}
+
+func (r *HistogramRule) GetName() string {
+	return "histogram"
+}
+
+func (r *HistogramRule) GetDescription() string {
+	return "按等宽或等高分桶统计数值列和日期列的值分布"
+}
+
+func (r *HistogramRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *HistogramRule) GetParameters() []RuleParameter {
+	return []RuleParameter{
+		{Name: "bins", Type: ParamInteger, Description: "分桶数量", Default: r.Bins, Min: paramBound(1), Max: paramBound(100)},
+		{Name: "mode", Type: ParamEnum, Description: "分桶方式：等宽或等高", Default: string(r.Mode), Options: []string{string(HistogramEqualWidth), string(HistogramEqualHeight)}},
+		{Name: "columns", Type: ParamStringList, Description: "只统计指定的列，为空时统计全部数值列和日期列"},
+	}
+}
+
+func (r *HistogramRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns = filterColumns(selectColumns(columns, params.StringList("columns", nil)), func(column ColumnMetadata) bool {
+		return isNumericColumnType(column.ColumnType) || isTemporalColumnType(column.ColumnType)
+	})
+
+	rule := NewHistogramRule(params.Int("bins", r.Bins), HistogramMode(params.String("mode", string(r.Mode))))
+	result := make(map[string]*ColumnHistogram, len(columns))
+	for _, column := range columns {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		histogram, err := provider.ExecuteHistogram(ctx, db, config, tableName, column, rule.Bins, rule.Mode)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.ColumnName, err)
+		}
+		result[column.ColumnName] = histogram
+	}
+
+	return result, nil
+}
+
+// histogramDialect 直方图查询所需的方言片段
+type histogramDialect struct {
+	tableRef  string // 已引用的表名
+	column    string // 已引用的列名
+	valueExpr string // 数值化后的列表达式（日期列转换为 Unix 秒数）
+	// bucketExpr 返回 [lower, upper] 区间的等宽分桶表达式，桶序号从 bucketBase 开始且不超过 bucketBase+bins-1
+	bucketExpr func(lower, upper float64, bins int) string
+	bucketBase int
+}
+
+// sqlFloat 将浮点数格式化为 SQL 字面量
+func sqlFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// executeHistogram 按方言片段执行直方图查询
+func executeHistogram(ctx context.Context, db *sql.DB, dialect histogramDialect, bins int, mode HistogramMode, temporal bool) (*ColumnHistogram, error) {
+	histogram := &ColumnHistogram{
+		Mode:     mode,
+		Temporal: temporal,
+		Bins:     []HistogramBin{},
+	}
+
+	var minValue, maxValue sql.NullFloat64
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s), COUNT(%s) FROM %s", dialect.valueExpr, dialect.valueExpr, dialect.column, dialect.tableRef)
+	if err := db.QueryRowContext(ctx, query).Scan(&minValue, &maxValue, &histogram.NonNullCount); err != nil {
+		return nil, err
+	}
+	histogram.Min = nullableFloat(minValue)
+	histogram.Max = nullableFloat(maxValue)
+	if histogram.NonNullCount == 0 || !minValue.Valid || !maxValue.Valid {
+		return histogram, nil
+	}
+
+	// 所有值相同，只有一个分桶
+	if minValue.Float64 == maxValue.Float64 {
+		histogram.Bins = append(histogram.Bins, newHistogramBin(minValue.Float64, maxValue.Float64, histogram.NonNullCount, temporal))
+		return histogram, nil
+	}
+
+	var err error
+	if mode == HistogramEqualHeight {
+		histogram.Bins, err = queryEqualHeightBins(ctx, db, dialect, bins, temporal)
+	} else {
+		histogram.Bins, err = queryEqualWidthBins(ctx, db, dialect, minValue.Float64, maxValue.Float64, bins, temporal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return histogram, nil
+}
+
+// queryEqualWidthBins 等宽分桶：按 [min, max] 均分，空桶计数为 0
+func queryEqualWidthBins(ctx context.Context, db *sql.DB, dialect histogramDialect, minValue, maxValue float64, bins int, temporal bool) ([]HistogramBin, error) {
+	query := fmt.Sprintf(
+		"SELECT bucket, COUNT(*) FROM (SELECT %s AS bucket FROM %s WHERE %s IS NOT NULL) bucketed GROUP BY bucket",
+		dialect.bucketExpr(minValue, maxValue, bins), dialect.tableRef, dialect.column,
+	)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int64, bins)
+	for rows.Next() {
+		var bucket sql.NullFloat64
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		if !bucket.Valid {
+			continue
+		}
+		index := int(bucket.Float64) - dialect.bucketBase
+		if index < 0 {
+			index = 0
+		}
+		if index >= bins {
+			index = bins - 1
+		}
+		counts[index] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	width := (maxValue - minValue) / float64(bins)
+	result := make([]HistogramBin, 0, bins)
+	for i, count := range counts {
+		lower := minValue + width*float64(i)
+		upper := lower + width
+		if i == bins-1 {
+			upper = maxValue
+		}
+		result = append(result, newHistogramBin(lower, upper, count, temporal))
+	}
+	return result, nil
+}
+
+// queryEqualHeightBins 等高分桶：使用 NTILE 将非空值按顺序均分，每个桶给出实际上下界
+func queryEqualHeightBins(ctx context.Context, db *sql.DB, dialect histogramDialect, bins int, temporal bool) ([]HistogramBin, error) {
+	query := fmt.Sprintf(
+		"SELECT tile, MIN(v), MAX(v), COUNT(*) FROM (SELECT %s AS v, NTILE(%d) OVER (ORDER BY %s) AS tile FROM %s WHERE %s IS NOT NULL) tiled GROUP BY tile ORDER BY tile",
+		dialect.valueExpr, bins, dialect.valueExpr, dialect.tableRef, dialect.column,
+	)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]HistogramBin, 0, bins)
+	for rows.Next() {
+		var tile int64
+		var lower, upper sql.NullFloat64
+		var count int64
+		if err := rows.Scan(&tile, &lower, &upper, &count); err != nil {
+			return nil, err
+		}
+		result = append(result, newHistogramBin(lower.Float64, upper.Float64, count, temporal))
+	}
+	return result, rows.Err()
+}
+
+func newHistogramBin(lower, upper float64, count int64, temporal bool) HistogramBin {
+	bin := HistogramBin{
+		Lower: lower,
+		Upper: upper,
+		Count: count,
+	}
+	if temporal {
+		bin.LowerLabel = time.Unix(int64(lower), 0).UTC().Format(time.RFC3339)
+		bin.UpperLabel = time.Unix(int64(upper), 0).UTC().Format(time.RFC3339)
+	}
+	return bin
+}
@@ -0,0 +1,245 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WatermarkConfig 任务的增量分析配置，为 nil 时每次分析全表
+// 适用于只追加的事实表：可合并的规则（行数、非空率、数值统计、字符统计）只分析上次水位之后的新行并与累计值合并，其余规则仍整表执行
+type WatermarkConfig struct {
+	Column string `json:"column"` // 水位列，须为随写入递增的数值或日期时间列，如自增 id、updated_at（SQLite 及文件数据源只能用数值列）
+}
+
+// Validate 校验增量分析配置
+func (c *WatermarkConfig) Validate() error {
+	if strings.TrimSpace(c.Column) == "" {
+		return fmt.Errorf("watermark column is required")
+	}
+	return nil
+}
+
+// IncrementalInfo 增量分析结果的说明
+type IncrementalInfo struct {
+	Column        string   `json:"column"`
+	FromWatermark string   `json:"fromWatermark,omitempty"` // 本次分析的起始水位（不含），为空表示从头累计
+	ToWatermark   string   `json:"toWatermark,omitempty"`   // 本次分析到的水位（含），为空表示表中没有数据
+	MergedRules   []string `json:"mergedRules"`             // 累计合并的规则，其余规则整表执行
+}
+
+// AnalysisWatermark 任务中一张表的增量分析水位及累计的部分聚合值
+type AnalysisWatermark struct {
+	TaskID    string
+	TableID   string
+	Column    string
+	Watermark string // 已分析到的水位（SQL 字面量），为空表示尚未分析到任何行
+	Signature string // 累计规则与列结构的指纹，变化后重新从头累计
+	Partials  map[string][][]*float64
+	UpdatedAt string
+}
+
+// watermarkRange 两次分析之间的新行 (lower, upper]，lower 为空时从头开始并包含水位列为 NULL 的行
+type watermarkRange struct {
+	column string
+	lower  string
+	upper  string
+}
+
+func (r *watermarkRange) condition(provider DatabaseProvider) string {
+	col := provider.QuoteIdentifier(r.column)
+	if r.lower == "" {
+		return fmt.Sprintf("(%s <= %s OR %s IS NULL)", col, r.upper, col)
+	}
+	return fmt.Sprintf("%s > %s AND %s <= %s", col, r.lower, col, r.upper)
+}
+
+// queryWatermark 查询水位列当前的最大值并返回 SQL 字面量，表为空时返回空字符串
+func queryWatermark(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, column ColumnMetadata) (string, error) {
+	col := provider.QuoteIdentifier(column.ColumnName)
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s", col, provider.QuoteTableName(config, tableName))
+
+	if isTemporalColumnType(column.ColumnType) {
+		var watermark sql.NullTime
+		if err := db.QueryRowContext(ctx, query).Scan(&watermark); err != nil {
+			return "", err
+		}
+		if !watermark.Valid {
+			return "", nil
+		}
+		return provider.TimestampLiteral(watermark.Time), nil
+	}
+
+	// 数值按字符串读取，避免大整数转换为浮点数后丢失精度
+	var watermark sql.NullString
+	if err := db.QueryRowContext(ctx, query).Scan(&watermark); err != nil {
+		return "", err
+	}
+	if !watermark.Valid {
+		return "", nil
+	}
+	literal := strings.TrimSpace(watermark.String)
+	if _, err := strconv.ParseFloat(literal, 64); err != nil {
+		return "", fmt.Errorf("invalid watermark value %q: %w", literal, err)
+	}
+	return literal, nil
+}
+
+// watermarkSignature 累计值的指纹，水位列、可合并规则及参数或列结构变化后累计值不再可用
+func watermarkSignature(column string, rules []TaskRuleConfig, columns []ColumnMetadata) (string, error) {
+	columnTypes := make([]string, len(columns))
+	for i, c := range columns {
+		columnTypes[i] = c.ColumnName + " " + c.ColumnType
+	}
+	data, err := json.Marshal(struct {
+		Column  string           `json:"column"`
+		Rules   []TaskRuleConfig `json:"rules"`
+		Columns []string         `json:"columns"`
+	}{column, rules, columnTypes})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// performIncrementalAnalysis 执行增量分析：可合并的规则只分析上次水位之后的新行并与累计值合并，其余规则整表执行
+// 累计值与水位在分析成功后一起保存；没有可合并的规则时按整表分析执行
+func (tm *TaskManager) performIncrementalAnalysis(task *AnalysisTask) {
+	if tm.analysisEngine == nil || task.DatabaseConfig == nil || tm.storageManager == nil {
+		tm.performTableAnalysis(task)
+		return
+	}
+	rules := tm.resolveRules(task)
+	partial, whole := tm.analysisEngine.SplitPartitionRules(rules)
+	if len(partial) == 0 {
+		tm.performTableAnalysis(task)
+		return
+	}
+	ruleNames := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleNames = append(ruleNames, rule.Name)
+	}
+
+	tm.updateTaskProgress(task.ID, 10)
+
+	tempDBManager, err := tm.openTaskDatabase(task)
+	if err != nil {
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		tm.finishTableAnalysis(task, ruleNames, nil, err, err.Error())
+		return
+	}
+	defer tempDBManager.Close()
+
+	// 为任务创建带120秒超时的context
+	timeoutCtx, timeoutCancel := context.WithTimeout(task.ctx, 120*time.Second)
+	defer timeoutCancel()
+
+	tm.updateTaskProgress(task.ID, 30)
+	analysisResults, watermark, err := tm.executeIncrementalAnalysis(timeoutCtx, tempDBManager.GetDB(), tempDBManager.GetProvider(), task, partial, whole)
+	tm.updateTaskProgress(task.ID, 80)
+
+	errorMessage := ""
+	if err != nil {
+		errorMessage = err.Error()
+		if timeoutCtx.Err() == context.DeadlineExceeded {
+			errorMessage = "分析任务超时（120秒限制）"
+		}
+	} else if err := tm.storageManager.SaveAnalysisWatermark(watermark); err != nil {
+		// 累计值未更新时下次分析仍从上次保存的水位开始，不会重复累计
+		GetLogger().LogError("INCREMENTAL", fmt.Sprintf("保存水位失败 - %s: %s", task.TableName, err.Error()))
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.finishTableAnalysis(task, ruleNames, analysisResults, err, errorMessage)
+}
+
+// executeIncrementalAnalysis 分析上次水位之后的新行并合并累计值，返回分析结果和待保存的新水位
+func (tm *TaskManager) executeIncrementalAnalysis(ctx context.Context, db *sql.DB, provider DatabaseProvider, task *AnalysisTask, partial, whole []TaskRuleConfig) (*AnalysisResultSet, *AnalysisWatermark, error) {
+	logger := GetLogger()
+	logger.SetModuleName("INCREMENTAL")
+
+	config := task.DatabaseConfig
+	columns, err := provider.GetTableColumns(ctx, db, config, task.TableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	column, err := findOrderedColumn(provider, columns, task.Watermark.Column)
+	if err != nil {
+		return nil, nil, fmt.Errorf("watermark %w", err)
+	}
+	signature, err := watermarkSignature(column.ColumnName, partial, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous, err := tm.storageManager.GetAnalysisWatermark(task.TaskID, task.TableID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load watermark: %w", err)
+	}
+	if previous != nil && previous.Signature != signature {
+		logger.LogInfo("INCREMENTAL", fmt.Sprintf("规则或列结构已变化，重新累计 - %s", task.TableName))
+		previous = nil
+	}
+
+	if err := prepareTable(provider, config, task.TableName, partial); err != nil {
+		return nil, nil, fmt.Errorf("failed to prepare table: %w", err)
+	}
+	upper, err := queryWatermark(ctx, db, config, provider, task.TableName, column)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 表为空时从头累计；新水位与上次相同时没有新行，直接使用累计值
+	var states []partialState
+	info := &IncrementalInfo{Column: column.ColumnName, ToWatermark: upper}
+	for _, rule := range partial {
+		info.MergedRules = append(info.MergedRules, rule.Name)
+	}
+	if upper == "" {
+		previous = nil
+	}
+	if previous != nil {
+		states = append(states, previous.Partials)
+		info.FromWatermark = previous.Watermark
+	}
+	if previous == nil || previous.Watermark != upper {
+		deltaCtx := ctx
+		if upper != "" {
+			deltaCtx = withRowFilter(ctx, &watermarkRange{column: column.ColumnName, lower: info.FromWatermark, upper: upper})
+		}
+		logger.LogInfo("INCREMENTAL", fmt.Sprintf("分析新增行 - 表: %s, 水位: (%s, %s]", task.TableName, info.FromWatermark, upper))
+		delta, err := tm.analysisEngine.ExecutePartition(deltaCtx, db, task.TableName, config, provider, columns, partial)
+		if err != nil {
+			return nil, nil, err
+		}
+		states = append(states, delta)
+	}
+
+	analysisResults := NewAnalysisResultSet()
+	if len(whole) > 0 {
+		if analysisResults, err = tm.analysisEngine.ExecuteAnalysis(ctx, db, task.TableName, config, provider, whole); err != nil {
+			return nil, nil, err
+		}
+	}
+	cumulative := tm.analysisEngine.MergePartitions(ctx, provider, columns, partial, states, analysisResults)
+	analysisResults.Incremental = info
+
+	watermark := &AnalysisWatermark{
+		TaskID:    task.TaskID,
+		TableID:   task.TableID,
+		Column:    column.ColumnName,
+		Watermark: upper,
+		Signature: signature,
+		Partials:  cumulative,
+	}
+	return analysisResults, watermark, nil
+}
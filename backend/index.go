@@ -0,0 +1,267 @@
+package backend
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+// TableIndex 表索引定义
+type TableIndex struct {
+	IndexName string   `json:"indexName"`
+	IndexType string   `json:"indexType"` // 方言原始的索引类型，如 BTREE、CLUSTERED、NORMAL
+	Columns   []string `json:"columns"`   // 按索引键顺序排列，表达式索引保留表达式文本
+	Unique    bool     `json:"unique"`
+	Primary   bool     `json:"primary"`
+	Partial   bool     `json:"partial,omitempty"` // 带 WHERE 条件的部分索引，只包含部分行
+}
+
+// scanTableIndexes 读取按索引名和键顺序排列的结果集
+// 每行依次为 索引名、列名、是否唯一(0/1)、索引类型、是否主键(0/1)，同一索引的多列合并为一个 TableIndex
+func scanTableIndexes(rows *sql.Rows) ([]TableIndex, error) {
+	var indexes []TableIndex
+	var current *TableIndex
+
+	for rows.Next() {
+		var name, column, indexType sql.NullString
+		var unique, primary sql.NullInt64
+		if err := rows.Scan(&name, &column, &unique, &indexType, &primary); err != nil {
+			return nil, err
+		}
+
+		if current == nil || current.IndexName != name.String {
+			indexes = append(indexes, TableIndex{
+				IndexName: name.String,
+				IndexType: strings.TrimSpace(indexType.String),
+				Unique:    unique.Int64 != 0,
+				Primary:   primary.Int64 != 0,
+			})
+			current = &indexes[len(indexes)-1]
+		}
+		current.Columns = append(current.Columns, column.String)
+	}
+
+	return indexes, rows.Err()
+}
+
+// parsePostgresIndexDef 解析 pg_indexes.indexdef，如
+// CREATE UNIQUE INDEX name ON schema.table USING btree (col1, lower(col2)) INCLUDE (col3) WHERE ...
+func parsePostgresIndexDef(definition string) (indexType string, columns []string, unique, partial bool) {
+	unique = strings.HasPrefix(strings.ToUpper(definition), "CREATE UNIQUE")
+
+	usingAt := strings.Index(definition, " USING ")
+	if usingAt < 0 {
+		return "", nil, unique, false
+	}
+	rest := definition[usingAt+len(" USING "):]
+	open := strings.Index(rest, "(")
+	if open < 0 {
+		return strings.TrimSpace(rest), nil, unique, false
+	}
+	indexType = strings.TrimSpace(rest[:open])
+
+	// 按括号深度切分键列表，忽略表达式内部的逗号
+	depth := 0
+	start := open + 1
+	for i := open; i < len(rest); i++ {
+		switch rest[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				columns = append(columns, postgresIndexKey(rest[start:i]))
+				// 键列表之后依次可能是 INCLUDE、WITH、TABLESPACE 子句，WHERE 总在最后
+				partial = strings.Contains(rest[i+1:], " WHERE ")
+				return indexType, columns, unique, partial
+			}
+		case ',':
+			if depth == 1 {
+				columns = append(columns, postgresIndexKey(rest[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return indexType, columns, unique, false
+}
+
+// postgresIndexKey 去除索引键上的引号和排序选项，表达式保持原样
+func postgresIndexKey(key string) string {
+	key = strings.TrimSpace(key)
+	if strings.Contains(key, "(") {
+		return key
+	}
+	if strings.HasPrefix(key, `"`) {
+		if end := strings.Index(key[1:], `"`); end >= 0 {
+			return key[1 : end+1]
+		}
+	}
+	if fields := strings.Fields(key); len(fields) > 0 {
+		return fields[0]
+	}
+	return key
+}
+
+// DuplicateIndex 冗余索引：与另一个同类型的索引完全相同，或是其最左前缀
+type DuplicateIndex struct {
+	IndexName   string   `json:"indexName"`
+	Columns     []string `json:"columns"`
+	DuplicateOf string   `json:"duplicateOf"`
+	Reason      string   `json:"reason"` // identical 或 prefix
+}
+
+// MissingIndex 缺失索引：外键列没有以其为最左前缀的索引
+type MissingIndex struct {
+	ConstraintName  string   `json:"constraintName"`
+	Columns         []string `json:"columns"`
+	ReferencedTable string   `json:"referencedTable"`
+}
+
+// IndexSummary 表索引使用情况汇总
+type IndexSummary struct {
+	IndexCount       int              `json:"indexCount"`
+	HasPrimaryKey    bool             `json:"hasPrimaryKey"`
+	DuplicateIndexes []DuplicateIndex `json:"duplicateIndexes"`
+	MissingIndexes   []MissingIndex   `json:"missingIndexes"`
+}
+
+// summarizeIndexes 根据索引和约束找出冗余索引及未建索引的外键
+// 类型不同的索引（如 BTREE 与 FULLTEXT、HASH）用途不同，部分索引和表达式索引的键与列比较没有意义，均不视为冗余
+func summarizeIndexes(indexes []TableIndex, constraints []TableConstraint) *IndexSummary {
+	summary := &IndexSummary{
+		IndexCount:       len(indexes),
+		DuplicateIndexes: []DuplicateIndex{},
+		MissingIndexes:   []MissingIndex{},
+	}
+
+	sorted := append([]TableIndex(nil), indexes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].IndexName < sorted[j].IndexName
+	})
+
+	for _, constraint := range constraints {
+		if constraint.ConstraintType == ConstraintPrimaryKey {
+			summary.HasPrimaryKey = true
+		}
+	}
+
+	for i, index := range sorted {
+		if index.Primary {
+			summary.HasPrimaryKey = true
+			continue
+		}
+		if !plainIndex(index) {
+			continue
+		}
+		for j, other := range sorted {
+			if i == j || !plainIndex(other) || !strings.EqualFold(index.IndexType, other.IndexType) {
+				continue
+			}
+			if sameColumns(index.Columns, other.Columns) {
+				// 完全相同时保留主键、唯一索引，其次保留名称靠前的索引
+				if indexRank(other) > indexRank(index) || (indexRank(other) == indexRank(index) && j < i) {
+					summary.DuplicateIndexes = append(summary.DuplicateIndexes, DuplicateIndex{
+						IndexName:   index.IndexName,
+						Columns:     index.Columns,
+						DuplicateOf: other.IndexName,
+						Reason:      "identical",
+					})
+					break
+				}
+				continue
+			}
+			if !index.Unique && isColumnPrefix(index.Columns, other.Columns) {
+				summary.DuplicateIndexes = append(summary.DuplicateIndexes, DuplicateIndex{
+					IndexName:   index.IndexName,
+					Columns:     index.Columns,
+					DuplicateOf: other.IndexName,
+					Reason:      "prefix",
+				})
+				break
+			}
+		}
+	}
+
+	for _, constraint := range constraints {
+		if constraint.ConstraintType != ConstraintForeignKey {
+			continue
+		}
+		covered := false
+		for _, index := range indexes {
+			if !index.Partial && coversColumns(index.Columns, constraint.Columns) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			summary.MissingIndexes = append(summary.MissingIndexes, MissingIndex{
+				ConstraintName:  constraint.ConstraintName,
+				Columns:         constraint.Columns,
+				ReferencedTable: constraint.ReferencedTable,
+			})
+		}
+	}
+
+	return summary
+}
+
+// plainIndex 判断索引是否覆盖全部行且各键均为列，表达式键在 PostgreSQL 中带括号、在 MySQL 中列名为空、在 SQLite 中为 <expression>
+func plainIndex(index TableIndex) bool {
+	if index.Partial {
+		return false
+	}
+	for _, column := range index.Columns {
+		if column == "" || column == "<expression>" || strings.Contains(column, "(") {
+			return false
+		}
+	}
+	return true
+}
+
+// indexRank 索引保留优先级：主键 > 唯一索引 > 普通索引
+func indexRank(index TableIndex) int {
+	switch {
+	case index.Primary:
+		return 2
+	case index.Unique:
+		return 1
+	}
+	return 0
+}
+
+// sameColumns 判断两个索引的键列是否完全相同（忽略大小写）
+func sameColumns(a, b []string) bool {
+	return len(a) == len(b) && isColumnPrefix(a, b)
+}
+
+// isColumnPrefix 判断 prefix 是否为 columns 的最左前缀（忽略大小写）
+func isColumnPrefix(prefix, columns []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(columns) {
+		return false
+	}
+	for i, column := range prefix {
+		if !strings.EqualFold(column, columns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// coversColumns 判断索引的前 len(columns) 个键是否恰好包含给定列（顺序不限）
+func coversColumns(indexColumns, columns []string) bool {
+	if len(columns) == 0 || len(columns) > len(indexColumns) {
+		return false
+	}
+	remaining := make(map[string]int, len(columns))
+	for _, column := range columns {
+		remaining[strings.ToLower(column)]++
+	}
+	for _, column := range indexColumns[:len(columns)] {
+		key := strings.ToLower(column)
+		if remaining[key] == 0 {
+			return false
+		}
+		remaining[key]--
+	}
+	return true
+}
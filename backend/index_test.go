@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePostgresIndexDef(t *testing.T) {
+	tests := []struct {
+		definition string
+		indexType  string
+		columns    []string
+		unique     bool
+		partial    bool
+	}{
+		{
+			definition: `CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)`,
+			indexType:  "btree",
+			columns:    []string{"id"},
+			unique:     true,
+		},
+		{
+			definition: `CREATE INDEX idx_name ON public.users USING btree ("Last Name" DESC NULLS LAST, lower((email)::text), coalesce(a, b)) INCLUDE (created_at)`,
+			indexType:  "btree",
+			columns:    []string{"Last Name", "lower((email)::text)", "coalesce(a, b)"},
+		},
+		{
+			definition: `CREATE INDEX idx_active ON public.users USING btree (tenant_id, status) WHERE (deleted_at IS NULL)`,
+			indexType:  "btree",
+			columns:    []string{"tenant_id", "status"},
+			partial:    true,
+		},
+		{
+			definition: `CREATE INDEX idx_tags ON public.posts USING gin (tags) WITH (fastupdate=off) WHERE (published)`,
+			indexType:  "gin",
+			columns:    []string{"tags"},
+			partial:    true,
+		},
+	}
+
+	for _, test := range tests {
+		indexType, columns, unique, partial := parsePostgresIndexDef(test.definition)
+		if indexType != test.indexType || !reflect.DeepEqual(columns, test.columns) || unique != test.unique || partial != test.partial {
+			t.Errorf("%s:\n got %q %q unique=%v partial=%v\nwant %q %q unique=%v partial=%v",
+				test.definition, indexType, columns, unique, partial, test.indexType, test.columns, test.unique, test.partial)
+		}
+	}
+}
+
+func TestSummarizeIndexes(t *testing.T) {
+	indexes := []TableIndex{
+		{IndexName: "PRIMARY", IndexType: "BTREE", Columns: []string{"id"}, Unique: true, Primary: true},
+		{IndexName: "idx_id", IndexType: "BTREE", Columns: []string{"ID"}},
+		{IndexName: "idx_a", IndexType: "BTREE", Columns: []string{"a"}},
+		{IndexName: "idx_a_b", IndexType: "BTREE", Columns: []string{"a", "b"}},
+		{IndexName: "idx_b_hash", IndexType: "HASH", Columns: []string{"b"}},
+		{IndexName: "idx_b_tree", IndexType: "BTREE", Columns: []string{"b"}},
+		{IndexName: "ft_body", IndexType: "FULLTEXT", Columns: []string{"body"}},
+		{IndexName: "idx_body", IndexType: "BTREE", Columns: []string{"body"}},
+		{IndexName: "idx_c_active", IndexType: "btree", Columns: []string{"c"}, Partial: true},
+		{IndexName: "idx_c_d", IndexType: "btree", Columns: []string{"c", "d"}},
+		{IndexName: "idx_lower_e", IndexType: "btree", Columns: []string{"lower(e)"}},
+		{IndexName: "idx_lower_e_f", IndexType: "btree", Columns: []string{"lower(e)", "f"}},
+	}
+	constraints := []TableConstraint{
+		{ConstraintName: "fk_c", ConstraintType: ConstraintForeignKey, Columns: []string{"c"}, ReferencedTable: "parent"},
+		{ConstraintName: "fk_g", ConstraintType: ConstraintForeignKey, Columns: []string{"g"}, ReferencedTable: "other"},
+	}
+
+	summary := summarizeIndexes(indexes, constraints)
+
+	if summary.IndexCount != len(indexes) || !summary.HasPrimaryKey {
+		t.Errorf("index count %d, primary key %v", summary.IndexCount, summary.HasPrimaryKey)
+	}
+	// 类型不同、部分索引、表达式索引都不视为冗余
+	wantDuplicates := []DuplicateIndex{
+		{IndexName: "idx_a", Columns: []string{"a"}, DuplicateOf: "idx_a_b", Reason: "prefix"},
+		{IndexName: "idx_id", Columns: []string{"ID"}, DuplicateOf: "PRIMARY", Reason: "identical"},
+	}
+	if !reflect.DeepEqual(summary.DuplicateIndexes, wantDuplicates) {
+		t.Errorf("duplicate indexes:\n got %+v\nwant %+v", summary.DuplicateIndexes, wantDuplicates)
+	}
+	// fk_c 由 idx_c_d 覆盖；部分索引不覆盖全部行
+	wantMissing := []MissingIndex{
+		{ConstraintName: "fk_g", Columns: []string{"g"}, ReferencedTable: "other"},
+	}
+	if !reflect.DeepEqual(summary.MissingIndexes, wantMissing) {
+		t.Errorf("missing indexes:\n got %+v\nwant %+v", summary.MissingIndexes, wantMissing)
+	}
+
+	// 只有部分索引时外键视为缺少索引
+	summary = summarizeIndexes(indexes[8:9], constraints[:1])
+	if len(summary.MissingIndexes) != 1 {
+		t.Errorf("partial index should not cover fk_c: %+v", summary.MissingIndexes)
+	}
+}
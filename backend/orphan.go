@@ -0,0 +1,178 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// defaultOrphanSampleLimit 默认返回的孤儿外键值样本数量
+const defaultOrphanSampleLimit = 10
+
+// ForeignKeyRelation 用户指定的外键关系，用于数据库未声明外键的场景
+type ForeignKeyRelation struct {
+	Name              string   `json:"name"`
+	Table             string   `json:"table"` // 子表，格式与 GetTables 返回的表名一致
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referencedTable"`
+	ReferencedColumns []string `json:"referencedColumns"`
+}
+
+// ForeignKeyOrphanStats 单个外键关系的孤儿检查结果
+type ForeignKeyOrphanStats struct {
+	Name              string           `json:"name"`
+	Declared          bool             `json:"declared"` // 是否为数据库中声明的外键
+	Columns           []string         `json:"columns"`
+	ReferencedTable   string           `json:"referenced_table"`
+	ReferencedColumns []string         `json:"referenced_columns"`
+	CheckedRows       int64            `json:"checked_rows"` // 外键列全部非空的子表行数
+	OrphanCount       int64            `json:"orphan_count"`
+	OrphanRate        float64          `json:"orphan_rate"`
+	Samples           []KeyValueSample `json:"samples,omitempty"`
+	Error             string           `json:"error,omitempty"`
+}
+
+// ForeignKeyOrphanRule 外键孤儿检查规则
+// 检查表上声明的外键以及 Relations 中以该表为子表的关系
+type ForeignKeyOrphanRule struct {
+	Relations   []ForeignKeyRelation
+	SampleLimit int
+}
+
+func (r *ForeignKeyOrphanRule) GetName() string {
+	return "fk_orphans"
+}
+
+func (r *ForeignKeyOrphanRule) GetDescription() string {
+	return "统计外键值在父表中不存在的子表行数，并返回孤儿值样本"
+}
+
+func (r *ForeignKeyOrphanRule) GetParameters() []RuleParameter {
+	return []RuleParameter{
+		{Name: "relations", Type: ParamJSON, Description: "用户指定的外键关系列表：[{name, table, columns, referencedTable, referencedColumns}]"},
+		{Name: "sample_limit", Type: ParamInteger, Description: "每个外键返回的孤儿值样本数量", Default: r.SampleLimit, Min: paramBound(1), Max: paramBound(1000)},
+	}
+}
+
+func (r *ForeignKeyOrphanRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+
+	constraints, err := provider.GetTableConstraints(ctx, db, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []ForeignKeyOrphanStats
+	for _, constraint := range constraints {
+		if constraint.ConstraintType != ConstraintForeignKey {
+			continue
+		}
+		relations = append(relations, ForeignKeyOrphanStats{
+			Name:              constraint.ConstraintName,
+			Declared:          true,
+			Columns:           constraint.Columns,
+			ReferencedTable:   constraint.ReferencedTable,
+			ReferencedColumns: constraint.ReferencedColumns,
+		})
+	}
+	userRelations := r.Relations
+	if err := params.Decode("relations", &userRelations); err != nil {
+		return nil, err
+	}
+	for _, relation := range userRelations {
+		if !strings.EqualFold(relation.Table, tableName) {
+			continue
+		}
+		relations = append(relations, ForeignKeyOrphanStats{
+			Name:              relation.Name,
+			Columns:           relation.Columns,
+			ReferencedTable:   relation.ReferencedTable,
+			ReferencedColumns: relation.ReferencedColumns,
+		})
+	}
+
+	limit := params.Int("sample_limit", r.SampleLimit)
+	if limit <= 0 {
+		limit = defaultOrphanSampleLimit
+	}
+
+	result := make([]ForeignKeyOrphanStats, 0, len(relations))
+	for _, relation := range relations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// 单个关系失败（如无父表权限）时记录错误，继续检查其余关系
+		if err := checkForeignKeyOrphans(ctx, db, config, provider, tableName, &relation, limit); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			relation.Error = err.Error()
+		}
+		result = append(result, relation)
+	}
+
+	return result, nil
+}
+
+// checkForeignKeyOrphans 通过 NOT EXISTS 统计孤儿行并采样孤儿值
+// 不使用 LEFT JOIN：被引用列不唯一时一行子表会匹配多行父表，使检查行数偏大
+func checkForeignKeyOrphans(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, relation *ForeignKeyOrphanStats, limit int) error {
+	if len(relation.Columns) == 0 || len(relation.Columns) != len(relation.ReferencedColumns) || relation.ReferencedTable == "" {
+		return fmt.Errorf("invalid foreign key relation %s", relation.Name)
+	}
+
+	joinConditions := make([]string, 0, len(relation.Columns))
+	notNullConditions := make([]string, 0, len(relation.Columns))
+	childColumns := make([]string, 0, len(relation.Columns))
+	for i, column := range relation.Columns {
+		childColumn := "child." + provider.QuoteIdentifier(column)
+		parentColumn := "parent." + provider.QuoteIdentifier(relation.ReferencedColumns[i])
+		joinConditions = append(joinConditions, fmt.Sprintf("%s = %s", childColumn, parentColumn))
+		notNullConditions = append(notNullConditions, childColumn+" IS NOT NULL")
+		childColumns = append(childColumns, childColumn)
+	}
+
+	// 外键列任一为 NULL 时不参与检查（MATCH SIMPLE 语义）
+	fromClause := fmt.Sprintf(
+		"%s child WHERE %s",
+		provider.QuoteTableName(config, tableName),
+		strings.Join(notNullConditions, " AND "),
+	)
+	parentMissing := fmt.Sprintf(
+		"NOT EXISTS (SELECT 1 FROM %s parent WHERE %s)",
+		provider.QuoteTableName(config, relation.ReferencedTable),
+		strings.Join(joinConditions, " AND "),
+	)
+
+	// SQL Server 不允许聚合函数的参数包含子查询，先在派生表中标记孤儿行再汇总
+	query := fmt.Sprintf(
+		"SELECT COUNT(*), SUM(orphan) FROM (SELECT CASE WHEN %s THEN 1 ELSE 0 END AS orphan FROM %s) checked",
+		parentMissing, fromClause,
+	)
+	var orphans sql.NullInt64
+	if err := db.QueryRowContext(ctx, query).Scan(&relation.CheckedRows, &orphans); err != nil {
+		return err
+	}
+	relation.OrphanCount = orphans.Int64
+	if relation.CheckedRows > 0 {
+		relation.OrphanRate = float64(relation.OrphanCount) / float64(relation.CheckedRows)
+	}
+	if relation.OrphanCount == 0 {
+		return nil
+	}
+
+	columnList := strings.Join(childColumns, ", ")
+	sampleQuery := fmt.Sprintf(
+		"SELECT %s, COUNT(*) AS cnt FROM %s AND %s GROUP BY %s ORDER BY COUNT(*) DESC",
+		columnList, fromClause, parentMissing, columnList,
+	)
+	samples, err := queryKeyValueSamples(ctx, db, provider.LimitQuery(sampleQuery, limit), relation.Columns)
+	if err != nil {
+		return err
+	}
+	relation.Samples = samples
+	return nil
+}
@@ -0,0 +1,644 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Parquet 文件格式中用到的 Thrift 结构，字段编号与 parquet.thrift 一致，未用到的字段跳过
+
+// parquetMagic 文件首尾的魔数
+const parquetMagic = "PAR1"
+
+// parquetMaxFooterSize 元数据大小的上限，避免损坏的文件申请过大的内存
+const parquetMaxFooterSize = 256 << 20
+
+// 物理类型
+const (
+	parquetBoolean           int32 = 0
+	parquetInt32             int32 = 1
+	parquetInt64             int32 = 2
+	parquetInt96             int32 = 3
+	parquetFloat             int32 = 4
+	parquetDouble            int32 = 5
+	parquetByteArray         int32 = 6
+	parquetFixedLenByteArray int32 = 7
+)
+
+// 字段的重复类型
+const (
+	parquetRequired int32 = 0
+	parquetOptional int32 = 1
+	parquetRepeated int32 = 2
+)
+
+// 旧版逻辑类型（ConvertedType）
+const (
+	convertedUTF8            int32 = 0
+	convertedEnum            int32 = 4
+	convertedDecimal         int32 = 5
+	convertedDate            int32 = 6
+	convertedTimeMillis      int32 = 7
+	convertedTimeMicros      int32 = 8
+	convertedTimestampMillis int32 = 9
+	convertedTimestampMicros int32 = 10
+	convertedUint8           int32 = 11
+	convertedUint16          int32 = 12
+	convertedUint32          int32 = 13
+	convertedUint64          int32 = 14
+	convertedInt8            int32 = 15
+	convertedInt16           int32 = 16
+	convertedInt32           int32 = 17
+	convertedInt64           int32 = 18
+	convertedJSON            int32 = 19
+)
+
+// 压缩算法
+const (
+	parquetUncompressed int32 = 0
+	parquetSnappy       int32 = 1
+	parquetGzip         int32 = 2
+	parquetZstd         int32 = 6
+	parquetLz4Raw       int32 = 7
+)
+
+// 页类型
+const (
+	parquetDataPage       int32 = 0
+	parquetDictionaryPage int32 = 2
+	parquetDataPageV2     int32 = 3
+)
+
+// 值编码
+const (
+	parquetPlain                int32 = 0
+	parquetPlainDictionary      int32 = 2
+	parquetRLE                  int32 = 3
+	parquetDeltaBinaryPacked    int32 = 5
+	parquetDeltaLengthByteArray int32 = 6
+	parquetDeltaByteArray       int32 = 7
+	parquetRLEDictionary        int32 = 8
+	parquetByteStreamSplit      int32 = 9
+)
+
+// 时间单位
+const (
+	parquetMillis = iota + 1
+	parquetMicros
+	parquetNanos
+)
+
+// parquetLogicalType 逻辑类型（LogicalType 联合体），kind 为联合体的字段编号
+type parquetLogicalType struct {
+	kind      int16
+	scale     int32
+	precision int32
+	unit      int // 时间单位
+	bitWidth  int8
+	signed    bool
+}
+
+// 逻辑类型联合体的字段编号
+const (
+	logicalString    int16 = 1
+	logicalEnum      int16 = 4
+	logicalDecimal   int16 = 5
+	logicalDate      int16 = 6
+	logicalTime      int16 = 7
+	logicalTimestamp int16 = 8
+	logicalInteger   int16 = 10
+	logicalJSON      int16 = 12
+	logicalUUID      int16 = 14
+	logicalFloat16   int16 = 15
+)
+
+// parquetSchemaElement 按深度优先顺序展开的 schema 节点，首个节点为根
+type parquetSchemaElement struct {
+	name          string
+	physicalType  int32
+	hasType       bool
+	typeLength    int32
+	repetition    int32
+	numChildren   int32
+	convertedType int32
+	hasConverted  bool
+	scale         int32
+	precision     int32
+	logicalType   *parquetLogicalType
+}
+
+// parquetStatistics 列块的统计信息，只读取空值数
+type parquetStatistics struct {
+	nullCount    int64
+	hasNullCount bool
+}
+
+// parquetColumnChunk 行组中一列的数据位置
+type parquetColumnChunk struct {
+	filePath              string
+	physicalType          int32
+	path                  []string
+	codec                 int32
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+	dictionaryPageOffset  int64
+	hasDictionaryOffset   bool
+	statistics            *parquetStatistics
+}
+
+// parquetRowGroup 行组
+type parquetRowGroup struct {
+	numRows int64
+	columns []parquetColumnChunk
+}
+
+// parquetFileMetaData 文件尾部的元数据
+type parquetFileMetaData struct {
+	numRows   int64
+	schema    []parquetSchemaElement
+	rowGroups []parquetRowGroup
+}
+
+// parquetPageHeader 页头，v1/v2 数据页的字段合并在一起
+type parquetPageHeader struct {
+	pageType            int32
+	uncompressedSize    int32
+	compressedSize      int32
+	numValues           int32
+	encoding            int32
+	defLevelsByteLength int32 // 仅 v2
+	repLevelsByteLength int32 // 仅 v2
+	isCompressed        bool  // 仅 v2，默认 true
+	dictionaryNumValues int32
+}
+
+// readParquetFooter 读取并解析文件尾部的元数据：文件以 4 字节元数据长度（小端）和魔数结尾
+func readParquetFooter(f *os.File, size int64) (*parquetFileMetaData, error) {
+	if size < 12 {
+		return nil, fmt.Errorf("not a parquet file")
+	}
+	tail := make([]byte, 8)
+	if _, err := f.ReadAt(tail, size-8); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, fmt.Errorf("not a parquet file")
+	}
+	footerSize := int64(binary.LittleEndian.Uint32(tail))
+	if footerSize > size-12 || footerSize > parquetMaxFooterSize {
+		return nil, fmt.Errorf("invalid parquet footer size %d", footerSize)
+	}
+	footer := make([]byte, footerSize)
+	if _, err := f.ReadAt(footer, size-8-footerSize); err != nil {
+		return nil, err
+	}
+
+	metadata := &parquetFileMetaData{}
+	err := newThriftReader(bytes.NewReader(footer)).readStruct(func(t *thriftReader, id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 2 && typ == thriftList:
+			err = t.readList(func(t *thriftReader, _ byte) error {
+				element, err := readParquetSchemaElement(t)
+				metadata.schema = append(metadata.schema, element)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			metadata.numRows, err = t.readI64()
+		case id == 4 && typ == thriftList:
+			err = t.readList(func(t *thriftReader, _ byte) error {
+				rowGroup, err := readParquetRowGroup(t)
+				metadata.rowGroups = append(metadata.rowGroups, rowGroup)
+				return err
+			})
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid parquet footer: %w", err)
+	}
+	if len(metadata.schema) == 0 {
+		return nil, fmt.Errorf("invalid parquet footer: empty schema")
+	}
+	return metadata, nil
+}
+
+func readParquetSchemaElement(t *thriftReader) (parquetSchemaElement, error) {
+	element := parquetSchemaElement{}
+	err := t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			element.physicalType, err = t.readI32()
+			element.hasType = true
+		case id == 2 && typ == thriftI32:
+			element.typeLength, err = t.readI32()
+		case id == 3 && typ == thriftI32:
+			element.repetition, err = t.readI32()
+		case id == 4 && typ == thriftBinary:
+			var name []byte
+			name, err = t.readBinary()
+			element.name = string(name)
+		case id == 5 && typ == thriftI32:
+			element.numChildren, err = t.readI32()
+		case id == 6 && typ == thriftI32:
+			element.convertedType, err = t.readI32()
+			element.hasConverted = true
+		case id == 7 && typ == thriftI32:
+			element.scale, err = t.readI32()
+		case id == 8 && typ == thriftI32:
+			element.precision, err = t.readI32()
+		case id == 10 && typ == thriftStruct:
+			element.logicalType, err = readParquetLogicalType(t)
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+	return element, err
+}
+
+func readParquetLogicalType(t *thriftReader) (*parquetLogicalType, error) {
+	logical := &parquetLogicalType{}
+	err := t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		if typ != thriftStruct {
+			return t.skip(typ)
+		}
+		logical.kind = id
+		return t.readStruct(func(t *thriftReader, field int16, typ byte) error {
+			var err error
+			switch {
+			case id == logicalDecimal && field == 1 && typ == thriftI32:
+				logical.scale, err = t.readI32()
+			case id == logicalDecimal && field == 2 && typ == thriftI32:
+				logical.precision, err = t.readI32()
+			case (id == logicalTime || id == logicalTimestamp) && field == 2 && typ == thriftStruct:
+				// TimeUnit 联合体，字段编号即单位
+				err = t.readStruct(func(t *thriftReader, unit int16, typ byte) error {
+					logical.unit = int(unit)
+					return t.skip(typ)
+				})
+			case id == logicalInteger && field == 1 && typ == thriftByte:
+				var width byte
+				width, err = t.readByte()
+				logical.bitWidth = int8(width)
+			case id == logicalInteger && field == 2 && (typ == thriftTrue || typ == thriftFalse):
+				logical.signed = typ == thriftTrue
+			default:
+				err = t.skip(typ)
+			}
+			return err
+		})
+	})
+	return logical, err
+}
+
+func readParquetRowGroup(t *thriftReader) (parquetRowGroup, error) {
+	rowGroup := parquetRowGroup{}
+	err := t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftList:
+			err = t.readList(func(t *thriftReader, _ byte) error {
+				chunk, err := readParquetColumnChunk(t)
+				rowGroup.columns = append(rowGroup.columns, chunk)
+				return err
+			})
+		case id == 3 && typ == thriftI64:
+			rowGroup.numRows, err = t.readI64()
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+	return rowGroup, err
+}
+
+func readParquetColumnChunk(t *thriftReader) (parquetColumnChunk, error) {
+	chunk := parquetColumnChunk{}
+	err := t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		switch {
+		case id == 1 && typ == thriftBinary:
+			path, err := t.readBinary()
+			chunk.filePath = string(path)
+			return err
+		case id == 3 && typ == thriftStruct:
+			return readParquetColumnMetaData(t, &chunk)
+		}
+		return t.skip(typ)
+	})
+	return chunk, err
+}
+
+func readParquetColumnMetaData(t *thriftReader, chunk *parquetColumnChunk) error {
+	return t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			chunk.physicalType, err = t.readI32()
+		case id == 3 && typ == thriftList:
+			err = t.readList(func(t *thriftReader, _ byte) error {
+				name, err := t.readBinary()
+				chunk.path = append(chunk.path, string(name))
+				return err
+			})
+		case id == 4 && typ == thriftI32:
+			chunk.codec, err = t.readI32()
+		case id == 5 && typ == thriftI64:
+			chunk.numValues, err = t.readI64()
+		case id == 6 && typ == thriftI64:
+			chunk.totalUncompressedSize, err = t.readI64()
+		case id == 7 && typ == thriftI64:
+			chunk.totalCompressedSize, err = t.readI64()
+		case id == 9 && typ == thriftI64:
+			chunk.dataPageOffset, err = t.readI64()
+		case id == 11 && typ == thriftI64:
+			chunk.dictionaryPageOffset, err = t.readI64()
+			chunk.hasDictionaryOffset = true
+		case id == 12 && typ == thriftStruct:
+			chunk.statistics = &parquetStatistics{}
+			err = t.readStruct(func(t *thriftReader, field int16, typ byte) error {
+				if field == 3 && typ == thriftI64 {
+					var err error
+					chunk.statistics.nullCount, err = t.readI64()
+					chunk.statistics.hasNullCount = true
+					return err
+				}
+				return t.skip(typ)
+			})
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+}
+
+// readParquetPageHeader 读取页头，之后紧跟 compressedSize 字节的页数据
+func readParquetPageHeader(t *thriftReader) (*parquetPageHeader, error) {
+	header := &parquetPageHeader{isCompressed: true}
+	err := t.readStruct(func(t *thriftReader, id int16, typ byte) error {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			header.pageType, err = t.readI32()
+		case id == 2 && typ == thriftI32:
+			header.uncompressedSize, err = t.readI32()
+		case id == 3 && typ == thriftI32:
+			header.compressedSize, err = t.readI32()
+		case id == 5 && typ == thriftStruct:
+			err = t.readStruct(func(t *thriftReader, field int16, typ byte) error {
+				var err error
+				switch {
+				case field == 1 && typ == thriftI32:
+					header.numValues, err = t.readI32()
+				case field == 2 && typ == thriftI32:
+					header.encoding, err = t.readI32()
+				default:
+					err = t.skip(typ)
+				}
+				return err
+			})
+		case id == 7 && typ == thriftStruct:
+			err = t.readStruct(func(t *thriftReader, field int16, typ byte) error {
+				var err error
+				switch {
+				case field == 1 && typ == thriftI32:
+					header.dictionaryNumValues, err = t.readI32()
+				default:
+					err = t.skip(typ)
+				}
+				return err
+			})
+		case id == 8 && typ == thriftStruct:
+			err = t.readStruct(func(t *thriftReader, field int16, typ byte) error {
+				var err error
+				switch {
+				case field == 1 && typ == thriftI32:
+					header.numValues, err = t.readI32()
+				case field == 4 && typ == thriftI32:
+					header.encoding, err = t.readI32()
+				case field == 5 && typ == thriftI32:
+					header.defLevelsByteLength, err = t.readI32()
+				case field == 6 && typ == thriftI32:
+					header.repLevelsByteLength, err = t.readI32()
+				case field == 7 && (typ == thriftTrue || typ == thriftFalse):
+					header.isCompressed = typ == thriftTrue
+				default:
+					err = t.skip(typ)
+				}
+				return err
+			})
+		default:
+			err = t.skip(typ)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if header.compressedSize < 0 || header.uncompressedSize < 0 {
+		return nil, fmt.Errorf("invalid parquet page size")
+	}
+	return header, nil
+}
+
+// Thrift Compact 协议的字段类型
+const (
+	thriftStop   byte = 0
+	thriftTrue   byte = 1
+	thriftFalse  byte = 2
+	thriftByte   byte = 3
+	thriftI16    byte = 4
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftDouble byte = 7
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftSet    byte = 10
+	thriftMap    byte = 11
+	thriftStruct byte = 12
+)
+
+// thriftMaxDepth 嵌套结构的最大深度，避免损坏的数据导致无限递归
+const thriftMaxDepth = 64
+
+// thriftByteReader 可逐字节读取的输入
+type thriftByteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// thriftReader Thrift Compact 协议的解码器，结构体按字段回调读取
+type thriftReader struct {
+	r     thriftByteReader
+	depth int
+}
+
+func newThriftReader(r thriftByteReader) *thriftReader {
+	return &thriftReader{r: r}
+}
+
+// readStruct 读取结构体，每个字段调用一次 field；布尔字段的值由类型表示（thriftTrue/thriftFalse），不再读取
+// field 必须读取或跳过字段的值
+func (t *thriftReader) readStruct(field func(t *thriftReader, id int16, typ byte) error) error {
+	if t.depth++; t.depth > thriftMaxDepth {
+		return fmt.Errorf("thrift struct nested too deep")
+	}
+	defer func() { t.depth-- }()
+
+	var last int16
+	for {
+		header, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		typ := header & 0x0f
+		if typ == thriftStop {
+			return nil
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := t.readVarint()
+			if err != nil {
+				return err
+			}
+			id = int16(zigzag(v))
+		}
+		last = id
+		if err := field(t, id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// readList 读取列表或集合，每个元素调用一次 elem；布尔元素占一个字节，需由 elem 读取
+func (t *thriftReader) readList(elem func(t *thriftReader, typ byte) error) error {
+	if t.depth++; t.depth > thriftMaxDepth {
+		return fmt.Errorf("thrift list nested too deep")
+	}
+	defer func() { t.depth-- }()
+
+	header, err := t.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	size := uint64(header >> 4)
+	if size == 15 {
+		if size, err = t.readVarint(); err != nil {
+			return err
+		}
+	}
+	typ := header & 0x0f
+	for i := uint64(0); i < size; i++ {
+		if err := elem(t, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *thriftReader) readVarint() (uint64, error) {
+	return binary.ReadUvarint(t.r)
+}
+
+func (t *thriftReader) readByte() (byte, error) {
+	return t.r.ReadByte()
+}
+
+func (t *thriftReader) readI32() (int32, error) {
+	v, err := t.readVarint()
+	return int32(zigzag(v)), err
+}
+
+func (t *thriftReader) readI64() (int64, error) {
+	v, err := t.readVarint()
+	return zigzag(v), err
+}
+
+func (t *thriftReader) readBinary() ([]byte, error) {
+	length, err := t.readVarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > parquetMaxFooterSize {
+		return nil, fmt.Errorf("thrift binary too large")
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(t.r, data)
+	return data, err
+}
+
+// skip 跳过一个值；结构体字段中的布尔值没有额外内容，列表中的布尔值占一个字节，由调用方区分
+func (t *thriftReader) skip(typ byte) error {
+	var err error
+	switch typ {
+	case thriftTrue, thriftFalse:
+	case thriftByte:
+		_, err = t.r.ReadByte()
+	case thriftI16, thriftI32, thriftI64:
+		_, err = t.readVarint()
+	case thriftDouble:
+		_, err = io.CopyN(io.Discard, t.r, 8)
+	case thriftBinary:
+		var length uint64
+		if length, err = t.readVarint(); err == nil {
+			if length > math.MaxInt64 {
+				return fmt.Errorf("thrift binary too large")
+			}
+			_, err = io.CopyN(io.Discard, t.r, int64(length))
+		}
+	case thriftList, thriftSet:
+		err = t.readList(func(t *thriftReader, elem byte) error {
+			if elem == thriftTrue || elem == thriftFalse {
+				_, err := t.r.ReadByte()
+				return err
+			}
+			return t.skip(elem)
+		})
+	case thriftMap:
+		err = t.skipMap()
+	case thriftStruct:
+		err = t.readStruct(func(t *thriftReader, _ int16, typ byte) error {
+			return t.skip(typ)
+		})
+	default:
+		err = fmt.Errorf("unknown thrift type %d", typ)
+	}
+	return err
+}
+
+func (t *thriftReader) skipMap() error {
+	size, err := t.readVarint()
+	if err != nil || size == 0 {
+		return err
+	}
+	types, err := t.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < size; i++ {
+		for _, typ := range []byte{types >> 4, types & 0x0f} {
+			if typ == thriftTrue || typ == thriftFalse {
+				if _, err := t.r.ReadByte(); err != nil {
+					return err
+				}
+			} else if err := t.skip(typ); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// zigzag 解码 ZigZag 编码的有符号整数
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
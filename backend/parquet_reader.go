@@ -0,0 +1,706 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// parquetColumn schema 中的一个叶子列
+type parquetColumn struct {
+	name          string // 以点连接的路径
+	element       parquetSchemaElement
+	maxDefinition int  // 最大定义级别，为 0 时列不可能为空
+	repeated      bool // 自身或祖先为重复字段（列表、映射），按行读取需要重复级别，暂不支持
+	index         int  // 在行组列块中的位置
+}
+
+// parquetLeafColumns 按 schema 顺序展开叶子列，嵌套结构体的字段以点连接路径命名
+func parquetLeafColumns(schema []parquetSchemaElement) ([]parquetColumn, error) {
+	var columns []parquetColumn
+	pos := 1
+	var walk func(count int32, path []string, definition int, repeated bool) error
+	walk = func(count int32, path []string, definition int, repeated bool) error {
+		for i := int32(0); i < count; i++ {
+			if pos >= len(schema) {
+				return fmt.Errorf("invalid parquet schema")
+			}
+			element := schema[pos]
+			pos++
+
+			elementPath := append(append([]string(nil), path...), element.name)
+			elementDefinition := definition
+			if element.repetition != parquetRequired {
+				elementDefinition++
+			}
+			elementRepeated := repeated || element.repetition == parquetRepeated
+			if element.numChildren > 0 {
+				if err := walk(element.numChildren, elementPath, elementDefinition, elementRepeated); err != nil {
+					return err
+				}
+				continue
+			}
+			if !element.hasType {
+				continue
+			}
+			columns = append(columns, parquetColumn{
+				name:          strings.Join(elementPath, "."),
+				element:       element,
+				maxDefinition: elementDefinition,
+				repeated:      elementRepeated,
+				index:         len(columns),
+			})
+		}
+		return nil
+	}
+	if err := walk(schema[0].numChildren, nil, 0, false); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// parquetColumnReader 按页流式读取一个列块，每次返回一行的值
+type parquetColumnReader struct {
+	file    *os.File
+	column  *parquetColumn
+	convert func(value interface{}) interface{} // 将物理值转换为写入缓存库的值
+
+	pages      *bufio.Reader
+	thrift     *thriftReader
+	codec      int32
+	chunkSize  int64
+	rawSize    int64 // 列块解压后的大小，用于在分配内存前校验页头
+	remaining  int64 // 列块中尚未读取的值（含空值）
+	dictionary []interface{}
+
+	levels     []int32 // 当前页的定义级别，列不可能为空时为 nil
+	values     []interface{}
+	pageValues int
+	levelPos   int
+	valuePos   int
+}
+
+func newParquetColumnReader(file *os.File, column *parquetColumn, convert func(value interface{}) interface{}) *parquetColumnReader {
+	return &parquetColumnReader{file: file, column: column, convert: convert}
+}
+
+// openChunk 定位到行组中该列的第一页；有字典页时字典页在数据页之前
+func (r *parquetColumnReader) openChunk(chunk *parquetColumnChunk) error {
+	if chunk.filePath != "" {
+		return fmt.Errorf("column %s: column chunks in external files are not supported", r.column.name)
+	}
+	offset := chunk.dataPageOffset
+	if chunk.hasDictionaryOffset && chunk.dictionaryPageOffset > 0 && chunk.dictionaryPageOffset < offset {
+		offset = chunk.dictionaryPageOffset
+	}
+	r.pages = bufio.NewReader(io.NewSectionReader(r.file, offset, chunk.totalCompressedSize))
+	r.thrift = newThriftReader(r.pages)
+	r.codec = chunk.codec
+	r.chunkSize = chunk.totalCompressedSize
+	r.rawSize = chunk.totalUncompressedSize
+	r.remaining = chunk.numValues
+	r.dictionary = nil
+	r.levels, r.values = nil, nil
+	r.pageValues, r.levelPos, r.valuePos = 0, 0, 0
+	return nil
+}
+
+// next 返回下一行的值，空值为 nil
+func (r *parquetColumnReader) next() (interface{}, error) {
+	for r.levelPos >= r.pageValues {
+		if err := r.readPage(); err != nil {
+			return nil, fmt.Errorf("column %s: %w", r.column.name, err)
+		}
+	}
+	present := r.levels == nil || int(r.levels[r.levelPos]) == r.column.maxDefinition
+	r.levelPos++
+	if !present {
+		return nil, nil
+	}
+	if r.valuePos >= len(r.values) {
+		return nil, fmt.Errorf("column %s: page has fewer values than definition levels", r.column.name)
+	}
+	value := r.values[r.valuePos]
+	r.valuePos++
+	return value, nil
+}
+
+// readPage 读取下一个数据页，途中遇到的字典页保存为当前列块的字典
+func (r *parquetColumnReader) readPage() error {
+	for {
+		if r.remaining <= 0 {
+			return fmt.Errorf("column chunk has fewer values than rows")
+		}
+		header, err := readParquetPageHeader(r.thrift)
+		if err != nil {
+			return err
+		}
+		// 页头中的大小和值数在分配内存前按列块元数据校验，损坏的文件返回错误而不是耗尽内存
+		if header.compressedSize < 0 || int64(header.compressedSize) > r.chunkSize ||
+			header.uncompressedSize < 0 || int64(header.uncompressedSize) > r.rawSize {
+			return fmt.Errorf("invalid parquet page size")
+		}
+		if header.numValues < 0 || int64(header.numValues) > r.remaining || header.dictionaryNumValues < 0 {
+			return fmt.Errorf("invalid parquet page value count")
+		}
+		data := make([]byte, header.compressedSize)
+		if _, err := io.ReadFull(r.pages, data); err != nil {
+			return err
+		}
+
+		switch header.pageType {
+		case parquetDictionaryPage:
+			page, err := decompressParquetPage(r.codec, data, header.uncompressedSize)
+			if err != nil {
+				return err
+			}
+			values, err := decodeParquetPlain(page, &r.column.element, int(header.dictionaryNumValues))
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				values[i] = r.convert(value)
+			}
+			r.dictionary = values
+			continue
+		case parquetDataPage:
+			page, err := decompressParquetPage(r.codec, data, header.uncompressedSize)
+			if err != nil {
+				return err
+			}
+			if r.column.maxDefinition > 0 {
+				if len(page) < 4 {
+					return errParquetTruncated
+				}
+				length := int(binary.LittleEndian.Uint32(page))
+				if length > len(page)-4 {
+					return errParquetTruncated
+				}
+				if r.levels, err = decodeParquetRLE(page[4:4+length], parquetBitWidth(r.column.maxDefinition), int(header.numValues)); err != nil {
+					return err
+				}
+				page = page[4+length:]
+			}
+			err = r.decodePage(header, page)
+			return err
+		case parquetDataPageV2:
+			// 重复级别和定义级别不压缩，位于页首
+			levelsLength := int(header.repLevelsByteLength) + int(header.defLevelsByteLength)
+			if header.repLevelsByteLength < 0 || header.defLevelsByteLength < 0 || levelsLength > len(data) {
+				return errParquetTruncated
+			}
+			if r.column.maxDefinition > 0 {
+				levels := data[header.repLevelsByteLength:levelsLength]
+				if r.levels, err = decodeParquetRLE(levels, parquetBitWidth(r.column.maxDefinition), int(header.numValues)); err != nil {
+					return err
+				}
+			}
+			page := data[levelsLength:]
+			if header.isCompressed {
+				if page, err = decompressParquetPage(r.codec, page, header.uncompressedSize-int32(levelsLength)); err != nil {
+					return err
+				}
+			}
+			err = r.decodePage(header, page)
+			return err
+		}
+		// 索引页等其他页跳过
+	}
+}
+
+// decodePage 解码数据页中的非空值
+func (r *parquetColumnReader) decodePage(header *parquetPageHeader, page []byte) error {
+	if header.numValues < 0 {
+		return fmt.Errorf("invalid parquet page value count")
+	}
+	if r.column.maxDefinition == 0 {
+		r.levels = nil
+	}
+	count := int(header.numValues)
+	if r.levels != nil {
+		count = 0
+		for _, level := range r.levels {
+			if int(level) == r.column.maxDefinition {
+				count++
+			}
+		}
+	}
+
+	var err error
+	switch header.encoding {
+	case parquetPlainDictionary, parquetRLEDictionary:
+		if r.dictionary == nil {
+			return fmt.Errorf("dictionary page is missing")
+		}
+		if len(page) == 0 {
+			if count > 0 {
+				return errParquetTruncated
+			}
+			r.values = nil
+			break
+		}
+		var indexes []int32
+		if indexes, err = decodeParquetRLE(page[1:], int(page[0]), count); err != nil {
+			return err
+		}
+		r.values = make([]interface{}, count)
+		for i, index := range indexes {
+			if index < 0 || int(index) >= len(r.dictionary) {
+				return fmt.Errorf("dictionary index %d out of range", index)
+			}
+			r.values[i] = r.dictionary[index]
+		}
+	default:
+		if r.values, err = decodeParquetValues(header.encoding, page, &r.column.element, count); err != nil {
+			return err
+		}
+		for i, value := range r.values {
+			r.values[i] = r.convert(value)
+		}
+	}
+
+	r.remaining -= int64(header.numValues)
+	r.pageValues = int(header.numValues)
+	r.levelPos, r.valuePos = 0, 0
+	return nil
+}
+
+var errParquetTruncated = fmt.Errorf("parquet page is truncated")
+
+// parquetZstdDecoder 各文件共用的 zstd 解码器，DecodeAll 可并发调用
+var (
+	parquetZstdDecoder     *zstd.Decoder
+	parquetZstdDecoderErr  error
+	parquetZstdDecoderOnce sync.Once
+)
+
+// decompressParquetPage 按列块的压缩算法解压页数据
+func decompressParquetPage(codec int32, data []byte, size int32) ([]byte, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("invalid parquet page size")
+	}
+	var page []byte
+	var err error
+	switch codec {
+	case parquetUncompressed:
+		page = data
+	case parquetSnappy:
+		// S2 兼容 Snappy 块格式
+		page, err = s2.Decode(make([]byte, size), data)
+	case parquetGzip:
+		var reader *gzip.Reader
+		if reader, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			page = make([]byte, size)
+			if _, err = io.ReadFull(reader, page); err == nil {
+				err = reader.Close()
+			}
+		}
+	case parquetZstd:
+		parquetZstdDecoderOnce.Do(func() {
+			parquetZstdDecoder, parquetZstdDecoderErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+		})
+		if parquetZstdDecoderErr != nil {
+			return nil, parquetZstdDecoderErr
+		}
+		page, err = parquetZstdDecoder.DecodeAll(data, make([]byte, 0, size))
+	case parquetLz4Raw:
+		// LZ4 块先转换为 S2 块再解码，S2 块以解压后长度开头
+		converted := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+s2.MaxEncodedLen(int(size))), uint64(size))
+		var decodedSize int
+		converted, decodedSize, err = (&s2.LZ4Converter{}).ConvertBlock(converted, data)
+		if err == nil && decodedSize != int(size) {
+			err = fmt.Errorf("unexpected decompressed size")
+		}
+		if err == nil {
+			page, err = s2.Decode(make([]byte, size), converted)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported parquet compression codec %s", parquetCodecName(codec))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress parquet page: %w", err)
+	}
+	if len(page) != int(size) {
+		return nil, fmt.Errorf("failed to decompress parquet page: unexpected decompressed size")
+	}
+	return page, nil
+}
+
+// parquetCodecName 压缩算法名称，用于错误信息
+func parquetCodecName(codec int32) string {
+	names := []string{"UNCOMPRESSED", "SNAPPY", "GZIP", "LZO", "BROTLI", "LZ4", "ZSTD", "LZ4_RAW"}
+	if codec >= 0 && int(codec) < len(names) {
+		return names[codec]
+	}
+	return fmt.Sprintf("%d", codec)
+}
+
+// decodeParquetValues 按编码解码 count 个非空的物理值
+func decodeParquetValues(encoding int32, data []byte, element *parquetSchemaElement, count int) ([]interface{}, error) {
+	switch encoding {
+	case parquetPlain:
+		return decodeParquetPlain(data, element, count)
+	case parquetRLE:
+		if element.physicalType != parquetBoolean {
+			break
+		}
+		if len(data) < 4 {
+			return nil, errParquetTruncated
+		}
+		length := int(binary.LittleEndian.Uint32(data))
+		if length > len(data)-4 {
+			return nil, errParquetTruncated
+		}
+		bits, err := decodeParquetRLE(data[4:4+length], 1, count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, count)
+		for i, bit := range bits {
+			values[i] = bit == 1
+		}
+		return values, nil
+	case parquetDeltaBinaryPacked:
+		if element.physicalType != parquetInt32 && element.physicalType != parquetInt64 {
+			break
+		}
+		deltas, _, err := decodeParquetDeltaBinaryPacked(data, count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, count)
+		for i, v := range deltas {
+			if element.physicalType == parquetInt32 {
+				values[i] = int32(v)
+			} else {
+				values[i] = v
+			}
+		}
+		return values, nil
+	case parquetDeltaLengthByteArray:
+		if element.physicalType != parquetByteArray {
+			break
+		}
+		arrays, _, err := decodeParquetDeltaLengthByteArray(data, count)
+		return byteArrayValues(arrays), err
+	case parquetDeltaByteArray:
+		if element.physicalType != parquetByteArray && element.physicalType != parquetFixedLenByteArray {
+			break
+		}
+		arrays, err := decodeParquetDeltaByteArray(data, count)
+		return byteArrayValues(arrays), err
+	case parquetByteStreamSplit:
+		width := parquetFixedWidth(element)
+		if width <= 0 || element.physicalType == parquetBoolean || element.physicalType == parquetByteArray {
+			break
+		}
+		if len(data) < width*count {
+			return nil, errParquetTruncated
+		}
+		// 每个值的第 k 个字节依次存放在第 k 个字节流中，还原为 PLAIN 布局后解码
+		plain := make([]byte, width*count)
+		for i := 0; i < count; i++ {
+			for k := 0; k < width; k++ {
+				plain[i*width+k] = data[k*count+i]
+			}
+		}
+		return decodeParquetPlain(plain, element, count)
+	}
+	return nil, fmt.Errorf("unsupported parquet encoding %d for column %s", encoding, element.name)
+}
+
+// parquetFixedWidth 定长物理类型每个值的字节数，变长类型返回 0
+func parquetFixedWidth(element *parquetSchemaElement) int {
+	switch element.physicalType {
+	case parquetInt32, parquetFloat:
+		return 4
+	case parquetInt64, parquetDouble:
+		return 8
+	case parquetInt96:
+		return 12
+	case parquetFixedLenByteArray:
+		return int(element.typeLength)
+	}
+	return 0
+}
+
+// decodeParquetPlain 解码 PLAIN 编码的 count 个值：布尔按位打包，定长类型小端依次存放，字节数组以 4 字节长度开头
+func decodeParquetPlain(data []byte, element *parquetSchemaElement, count int) ([]interface{}, error) {
+	if count < 0 || len(data) < parquetMinPlainSize(element, count) {
+		return nil, errParquetTruncated
+	}
+	values := make([]interface{}, count)
+	switch element.physicalType {
+	case parquetBoolean:
+		if len(data)*8 < count {
+			return nil, errParquetTruncated
+		}
+		for i := range values {
+			values[i] = data[i/8]>>(i%8)&1 == 1
+		}
+		return values, nil
+	case parquetByteArray:
+		pos := 0
+		for i := range values {
+			if len(data)-pos < 4 {
+				return nil, errParquetTruncated
+			}
+			length := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if length < 0 || length > len(data)-pos {
+				return nil, errParquetTruncated
+			}
+			values[i] = data[pos : pos+length]
+			pos += length
+		}
+		return values, nil
+	}
+
+	width := parquetFixedWidth(element)
+	if width <= 0 {
+		return nil, fmt.Errorf("invalid parquet type length for column %s", element.name)
+	}
+	if len(data)/width < count {
+		return nil, errParquetTruncated
+	}
+	for i := range values {
+		value := data[i*width : (i+1)*width]
+		switch element.physicalType {
+		case parquetInt32:
+			values[i] = int32(binary.LittleEndian.Uint32(value))
+		case parquetInt64:
+			values[i] = int64(binary.LittleEndian.Uint64(value))
+		case parquetFloat:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(value))
+		case parquetDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		default:
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+// parquetMinPlainSize PLAIN 编码的 count 个值至少占用的字节数
+func parquetMinPlainSize(element *parquetSchemaElement, count int) int {
+	switch element.physicalType {
+	case parquetBoolean:
+		return (count + 7) / 8
+	case parquetByteArray:
+		return count * 4
+	}
+	return count * max(parquetFixedWidth(element), 0)
+}
+
+// parquetBitWidth 表示 0..max 所需的位数
+func parquetBitWidth(max int) int {
+	width := 0
+	for ; max > 0; max >>= 1 {
+		width++
+	}
+	return width
+}
+
+// decodeParquetRLE 解码 RLE/位打包混合编码的 count 个值，用于定义级别、字典索引和布尔值
+func decodeParquetRLE(data []byte, bitWidth int, count int) ([]int32, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, fmt.Errorf("invalid parquet bit width %d", bitWidth)
+	}
+	values := make([]int32, 0, count)
+	byteWidth := (bitWidth + 7) / 8
+	for len(values) < count {
+		header, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errParquetTruncated
+		}
+		data = data[n:]
+
+		if header&1 == 0 {
+			// RLE：重复次数和以 byteWidth 字节小端存放的值
+			if len(data) < byteWidth {
+				return nil, errParquetTruncated
+			}
+			var value uint32
+			for i := 0; i < byteWidth; i++ {
+				value |= uint32(data[i]) << (8 * i)
+			}
+			data = data[byteWidth:]
+			run := header >> 1
+			for ; run > 0 && len(values) < count; run-- {
+				values = append(values, int32(value))
+			}
+			continue
+		}
+
+		// 位打包：每组 8 个值，末组不足时以填充补齐；数据被截断时只取完整的值
+		groups := header >> 1
+		size, available := 0, count-len(values)
+		if bitWidth > 0 {
+			size = len(data)
+			if groups <= uint64(len(data)/bitWidth) {
+				size = int(groups) * bitWidth
+			}
+			available = min(available, size*8/bitWidth)
+		} else if groups*8 < uint64(available) {
+			available = int(groups * 8)
+		}
+		if available == 0 {
+			return nil, errParquetTruncated
+		}
+		for _, v := range unpackParquetBits(data[:size], bitWidth, available) {
+			values = append(values, int32(v))
+		}
+		data = data[size:]
+	}
+	return values, nil
+}
+
+// unpackParquetBits 按从低位到高位的顺序读取 count 个 bitWidth 位的值
+func unpackParquetBits(data []byte, bitWidth int, count int) []uint64 {
+	values := make([]uint64, count)
+	if bitWidth == 0 {
+		return values
+	}
+	bit := 0
+	for i := range values {
+		var value uint64
+		for read := 0; read < bitWidth; {
+			offset := bit & 7
+			take := min(8-offset, bitWidth-read)
+			value |= uint64(data[bit>>3]>>offset&(1<<take-1)) << read
+			read += take
+			bit += take
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// decodeParquetDeltaBinaryPacked 解码 DELTA_BINARY_PACKED 编码的 count 个整数，返回值和消耗的字节数
+// 头部为块大小、每块的小块数、值的总数和首个值，之后每块为最小差值、各小块位宽和按位打包的差值
+func decodeParquetDeltaBinaryPacked(data []byte, count int) ([]int64, int, error) {
+	pos := 0
+	readVarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return 0, errParquetTruncated
+		}
+		pos += n
+		return v, nil
+	}
+
+	var header [4]uint64
+	for i := range header {
+		v, err := readVarint()
+		if err != nil {
+			return nil, 0, err
+		}
+		header[i] = v
+	}
+	blockSize, miniblocks, total := header[0], header[1], header[2]
+	if miniblocks == 0 || blockSize == 0 || blockSize%miniblocks != 0 || (blockSize/miniblocks)%8 != 0 || blockSize > 1<<20 {
+		return nil, 0, fmt.Errorf("invalid delta encoding header")
+	}
+	if total < uint64(count) {
+		return nil, 0, errParquetTruncated
+	}
+	valuesPerMiniblock := int(blockSize / miniblocks)
+
+	values := make([]int64, 0, count)
+	last := zigzag(header[3])
+	if count > 0 {
+		values = append(values, last)
+	}
+	for len(values) < count {
+		v, err := readVarint()
+		if err != nil {
+			return nil, 0, err
+		}
+		minDelta := zigzag(v)
+		if len(data)-pos < int(miniblocks) {
+			return nil, 0, errParquetTruncated
+		}
+		widths := data[pos : pos+int(miniblocks)]
+		pos += int(miniblocks)
+		for _, width := range widths {
+			if len(values) == count {
+				break
+			}
+			if width > 64 {
+				return nil, 0, fmt.Errorf("invalid delta bit width %d", width)
+			}
+			size := valuesPerMiniblock * int(width) / 8
+			if len(data)-pos < size {
+				return nil, 0, errParquetTruncated
+			}
+			for _, delta := range unpackParquetBits(data[pos:pos+size], int(width), valuesPerMiniblock) {
+				if len(values) == count {
+					break
+				}
+				last += minDelta + int64(delta)
+				values = append(values, last)
+			}
+			pos += size
+		}
+	}
+	return values, pos, nil
+}
+
+// decodeParquetDeltaLengthByteArray 解码 DELTA_LENGTH_BYTE_ARRAY：先是差值编码的各值长度，之后依次存放各值
+func decodeParquetDeltaLengthByteArray(data []byte, count int) ([][]byte, int, error) {
+	lengths, pos, err := decodeParquetDeltaBinaryPacked(data, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	values := make([][]byte, count)
+	for i, length := range lengths {
+		if length < 0 || length > int64(len(data)-pos) {
+			return nil, 0, errParquetTruncated
+		}
+		values[i] = data[pos : pos+int(length)]
+		pos += int(length)
+	}
+	return values, pos, nil
+}
+
+// decodeParquetDeltaByteArray 解码 DELTA_BYTE_ARRAY：每个值由与前一个值共同前缀的长度和剩余后缀组成
+func decodeParquetDeltaByteArray(data []byte, count int) ([][]byte, error) {
+	prefixes, pos, err := decodeParquetDeltaBinaryPacked(data, count)
+	if err != nil {
+		return nil, err
+	}
+	suffixes, _, err := decodeParquetDeltaLengthByteArray(data[pos:], count)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, count)
+	var previous []byte
+	for i, prefix := range prefixes {
+		if prefix < 0 || prefix > int64(len(previous)) {
+			return nil, fmt.Errorf("invalid delta byte array prefix length")
+		}
+		value := make([]byte, 0, int(prefix)+len(suffixes[i]))
+		value = append(append(value, previous[:prefix]...), suffixes[i]...)
+		values[i] = value
+		previous = value
+	}
+	return values, nil
+}
+
+func byteArrayValues(arrays [][]byte) []interface{} {
+	values := make([]interface{}, len(arrays))
+	for i, array := range arrays {
+		values[i] = array
+	}
+	return values
+}
@@ -0,0 +1,316 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 测试文件由 testdata/parquet/generate.py 生成，各列的值是行号的简单函数，与下面的期望值一一对应
+
+// readParquetFixture 读取文件中可导入的列，按列名返回各行转换后的值
+func readParquetFixture(path string) (*parquetTable, map[string][]interface{}, error) {
+	table, err := readParquetTable(stagedFile{table: "fixture", path: path, parts: []string{path}})
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer source.Close()
+
+	values := make(map[string][]interface{}, len(table.columns))
+	err = readParquetRows(source, table.parts[0], table.columns, func(row []interface{}) error {
+		for i, column := range table.columns {
+			values[column.tableName] = append(values[column.tableName], row[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return table, values, nil
+}
+
+// nullable 条件成立时为空值
+func nullable(null bool, value interface{}) interface{} {
+	if null {
+		return nil
+	}
+	return value
+}
+
+// assertParquetColumns 按行号逐列比较读取的值与期望值
+func assertParquetColumns(t *testing.T, values map[string][]interface{}, rows int, expected map[string]func(i int) interface{}) {
+	t.Helper()
+	if len(values) != len(expected) {
+		t.Errorf("read %d columns, want %d", len(values), len(expected))
+	}
+	for name, want := range expected {
+		got := values[name]
+		if len(got) != rows {
+			t.Errorf("column %s: read %d rows, want %d", name, len(got), rows)
+			continue
+		}
+		for i := range got {
+			if !reflect.DeepEqual(got[i], want(i)) {
+				t.Errorf("column %s row %d: got %#v, want %#v", name, i, got[i], want(i))
+				break
+			}
+		}
+	}
+}
+
+func TestParquetCodecs(t *testing.T) {
+	expected := map[string]func(i int) interface{}{
+		"id":    func(i int) interface{} { return int64(i) },
+		"name":  func(i int) interface{} { return nullable(i%4 == 0, []string{"alpha", "beta", "gamma"}[i%3]) },
+		"score": func(i int) interface{} { return nullable(i%5 == 0, float64(i)*1.5) },
+	}
+	for _, codec := range []string{"uncompressed", "snappy", "gzip", "zstd", "lz4_raw"} {
+		t.Run(codec, func(t *testing.T) {
+			table, values, err := readParquetFixture(filepath.Join("testdata", "parquet", "codec_"+codec+".parquet"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if table.rowCount != 40 || len(table.parts[0].rowGroups) != 2 {
+				t.Errorf("got %d rows in %d row groups, want 40 rows in 2", table.rowCount, len(table.parts[0].rowGroups))
+			}
+			assertParquetColumns(t, values, 40, expected)
+			if want := map[string]int64{"id": 0, "name": 10, "score": 8}; !reflect.DeepEqual(table.nullCounts, want) {
+				t.Errorf("null counts %v, want %v", table.nullCounts, want)
+			}
+		})
+	}
+}
+
+func TestParquetEncodings(t *testing.T) {
+	expected := map[string]func(i int) interface{}{
+		"plain_i32":  func(i int) interface{} { return nullable(i%7 == 0, int64(i*3-100)) },
+		"delta_i32":  func(i int) interface{} { return int64(i*i%1000 - 500) },
+		"delta_i64":  func(i int) interface{} { return nullable(i%6 == 0, int64(i-150)*1e12) },
+		"rle_bool":   func(i int) interface{} { return nullable(i%9 == 0, boolInt(i/10%2 == 0)) },
+		"plain_bool": func(i int) interface{} { return boolInt(i%3 == 0) },
+		"dict_str":   func(i int) interface{} { return nullable(i%8 == 0, fmt.Sprintf("k%d", i%5)) },
+		"dlba_str":   func(i int) interface{} { return nullable(i%10 == 0, strings.Repeat("s", i%7)+fmt.Sprint(i)) },
+		"dba_str":    func(i int) interface{} { return fmt.Sprintf("prefix/%03d/%s", i/20, strings.Repeat("x", i%3)) },
+		"bss_float":  func(i int) interface{} { return nullable(i%11 == 0, float64(i)*0.25) },
+		"bss_double": func(i int) interface{} { return float64(i)/8 - 10 },
+		"fixed":      func(i int) interface{} { return []byte{byte(i), byte(i * 7), byte(255 - i%256)} },
+		"no_stats":   func(i int) interface{} { return nullable(i%2 == 0, int64(i)) },
+	}
+	// v1 页不压缩；v2 页以 Snappy 压缩，字典索引使用 RLE_DICTIONARY
+	for _, version := range []string{"v1", "v2"} {
+		t.Run(version, func(t *testing.T) {
+			table, values, err := readParquetFixture(filepath.Join("testdata", "parquet", "encodings_"+version+".parquet"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertParquetColumns(t, values, 300, expected)
+			if _, ok := table.nullCounts["no_stats"]; ok {
+				t.Errorf("column without statistics has a null count")
+			}
+			if table.nullCounts["plain_i32"] != 43 || table.nullCounts["delta_i32"] != 0 {
+				t.Errorf("null counts %v", table.nullCounts)
+			}
+		})
+	}
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestParquetNestedColumns(t *testing.T) {
+	table, values, err := readParquetFixture(filepath.Join("testdata", "parquet", "nested.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 结构体字段展开为以点连接的列，列表字段跳过；可选结构体中的必填字段可为空
+	var names []string
+	notNull := make(map[string]bool)
+	for _, column := range table.columns {
+		names = append(names, column.tableName)
+		notNull[column.tableName] = column.notNull()
+	}
+	if want := []string{"id", "addr.city", "addr.zip", "meta.source", "meta.score"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns %v, want %v", names, want)
+	}
+	if want := []string{"tags.list.element"}; !reflect.DeepEqual(table.skipped, want) {
+		t.Errorf("skipped %v, want %v", table.skipped, want)
+	}
+	if want := map[string]bool{"id": true, "addr.city": false, "addr.zip": false, "meta.source": true, "meta.score": false}; !reflect.DeepEqual(notNull, want) {
+		t.Errorf("not null %v, want %v", notNull, want)
+	}
+
+	// addr 在 i%5==0 时为空，city 在 addr 非空且 i%3==0 时为空
+	assertParquetColumns(t, values, 30, map[string]func(i int) interface{}{
+		"id":          func(i int) interface{} { return int64(i) },
+		"addr.city":   func(i int) interface{} { return nullable(i%5 == 0 || i%3 == 0, []string{"Paris", "Berlin"}[i%2]) },
+		"addr.zip":    func(i int) interface{} { return nullable(i%5 == 0, int64(10000+i)) },
+		"meta.source": func(i int) interface{} { return fmt.Sprintf("src%d", i%2) },
+		"meta.score":  func(i int) interface{} { return nullable(i%2 == 1, float64(i)/2) },
+	})
+	if want := map[string]int64{"id": 0, "addr.city": 14, "addr.zip": 6, "meta.source": 0, "meta.score": 15}; !reflect.DeepEqual(table.nullCounts, want) {
+		t.Errorf("null counts %v, want %v", table.nullCounts, want)
+	}
+}
+
+// parquetFixtures 返回全部测试文件
+func parquetFixtures(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "parquet", "*.parquet"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no parquet fixtures: %v", err)
+	}
+	return paths
+}
+
+// writeParquetVariant 将修改后的文件写入临时目录
+func writeParquetVariant(t *testing.T, dir string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, "variant.parquet")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParquetTruncatedFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, fixture := range parquetFixtures(t) {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, size := range []int{0, 4, 12, len(data) / 2, len(data) - 8, len(data) - 5, len(data) - 1} {
+			path := writeParquetVariant(t, dir, data[:size])
+			if _, _, err := readParquetFixture(path); err == nil {
+				t.Errorf("%s truncated to %d bytes: expected an error", filepath.Base(fixture), size)
+			}
+		}
+	}
+}
+
+func TestParquetMalformedFooter(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "parquet", "codec_uncompressed.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	footerLength := binary.LittleEndian.Uint32(data[len(data)-8:])
+	dir := t.TempDir()
+
+	variants := map[string]func(b []byte){
+		"bad magic": func(b []byte) { copy(b[len(b)-4:], "PAR0") },
+		"footer longer than file": func(b []byte) {
+			binary.LittleEndian.PutUint32(b[len(b)-8:], uint32(len(b)))
+		},
+		"footer cut short": func(b []byte) {
+			binary.LittleEndian.PutUint32(b[len(b)-8:], footerLength/2)
+		},
+		"footer overwritten": func(b []byte) {
+			start := len(b) - 8 - int(footerLength)
+			copy(b[start:len(b)-8], bytes.Repeat([]byte{0xff}, int(footerLength)))
+		},
+	}
+	for name, corrupt := range variants {
+		b := append([]byte(nil), data...)
+		corrupt(b)
+		if _, _, err := readParquetFixture(writeParquetVariant(t, dir, b)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestParquetCorruptPages 页数据或页头损坏时返回错误而不崩溃；损坏的值本身可能仍能解码，不要求一定出错
+func TestParquetCorruptPages(t *testing.T) {
+	dir := t.TempDir()
+	for _, fixture := range parquetFixtures(t) {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		footerStart := len(data) - 8 - int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+
+		// 数据区全部清零时每列的第一个页头都无效
+		zeroed := append([]byte(nil), data...)
+		copy(zeroed[4:footerStart], make([]byte, footerStart-4))
+		if _, _, err := readParquetFixture(writeParquetVariant(t, dir, zeroed)); err == nil {
+			t.Errorf("%s with zeroed pages: expected an error", filepath.Base(fixture))
+		}
+
+		step := 1
+		if footerStart > 4096 {
+			step = 7
+		}
+		for offset := 4; offset < footerStart; offset += step {
+			corrupted := append([]byte(nil), data...)
+			corrupted[offset] ^= 0xff
+			path := writeParquetVariant(t, dir, corrupted)
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("%s with byte %d flipped: panic: %v", filepath.Base(fixture), offset, r)
+					}
+				}()
+				readParquetFixture(path)
+			}()
+		}
+	}
+}
+
+func TestParquetValueConversions(t *testing.T) {
+	float16 := []struct {
+		bits uint16
+		want interface{}
+	}{
+		{0x3c00, 1.0},
+		{0xc000, -2.0},
+		{0x0001, 5.960464477539063e-08}, // 最小的非规格化数
+		{0x7bff, 65504.0},
+		{0x7c00, nil}, // 无穷按 NULL 写入
+		{0x7e00, nil}, // NaN
+	}
+	for _, tc := range float16 {
+		b := binary.LittleEndian.AppendUint16(nil, tc.bits)
+		if got := parquetFloat16(b); got != tc.want {
+			t.Errorf("float16 %#04x: got %v, want %v", tc.bits, got, tc.want)
+		}
+	}
+
+	decimals := []struct {
+		value interface{}
+		scale int32
+		want  float64
+	}{
+		{int32(12345), 2, 123.45},
+		{int64(-5), 1, -0.5},
+		{[]byte{0xff, 0x85}, 2, -1.23},     // 大端补码 -123
+		{[]byte{0x00, 0x00, 0x7b}, 0, 123}, // 带前导零
+	}
+	for _, tc := range decimals {
+		if got := parquetDecimal(tc.value, tc.scale); got != tc.want {
+			t.Errorf("decimal %v scale %d: got %v, want %v", tc.value, tc.scale, got, tc.want)
+		}
+	}
+
+	if got := parquetInteger(int32(-1), false); got != int64(4294967295) {
+		t.Errorf("uint32 max: got %v", got)
+	}
+	if got := parquetInteger(int64(-1), false); got != float64(18446744073709551615) {
+		t.Errorf("uint64 max: got %v", got)
+	}
+	if got := parquetInteger(int32(-1), true); got != int64(-1) {
+		t.Errorf("int32 -1: got %v", got)
+	}
+}
@@ -0,0 +1,520 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPartitionCount 单表最多拆分的分区数
+const maxPartitionCount = 64
+
+// PartitionConfig 任务的分区扫描配置，为 nil 时整表分析
+// 可合并的规则（行数、非空率、数值统计、字符统计）按键范围拆分为多个子任务分别执行后合并，其余规则作为一个子任务整表执行
+// 分区对去重计数、高频值、直方图、模式匹配、重复行、外键孤儿和自定义规则没有帮助：它们仍在整表子任务中扫描全表，
+// 同样受单个子任务 120 秒的超时限制；数值统计合并后只有最值、均值和标准差，分位数标记为不可用
+type PartitionConfig struct {
+	Column string `json:"column,omitempty"` // 分区列，须为数值或日期时间列（SQLite 及文件数据源只能用数值列）；为空时使用单列主键
+	Count  int    `json:"count"`            // 分区数 [2, 64]
+}
+
+// Validate 校验分区配置
+func (c *PartitionConfig) Validate() error {
+	if c.Count < 2 || c.Count > maxPartitionCount {
+		return fmt.Errorf("partition count must be between 2 and %d", maxPartitionCount)
+	}
+	return nil
+}
+
+// PartitionRange 分区的键范围 [Lower, Upper)，边界为 SQL 字面量
+// 首个分区没有下界并包含 NULL，最后一个分区没有上界，各分区合起来覆盖全表
+type PartitionRange struct {
+	Index  int    `json:"index"`
+	Column string `json:"column"`
+	Lower  string `json:"lower,omitempty"`
+	Upper  string `json:"upper,omitempty"`
+}
+
+// condition 返回分区的过滤条件
+func (r *PartitionRange) condition(provider DatabaseProvider) string {
+	col := provider.QuoteIdentifier(r.Column)
+	switch {
+	case r.Lower == "" && r.Upper == "":
+		return "1 = 1"
+	case r.Lower == "":
+		return fmt.Sprintf("(%s < %s OR %s IS NULL)", col, r.Upper, col)
+	case r.Upper == "":
+		return fmt.Sprintf("%s >= %s", col, r.Lower)
+	}
+	return fmt.Sprintf("%s >= %s AND %s < %s", col, r.Lower, col, r.Upper)
+}
+
+// rowFilter 限定规则查询读取的行，如分区的键范围、增量分析的水位区间
+type rowFilter interface {
+	condition(provider DatabaseProvider) string
+}
+
+type rowFilterContextKey struct{}
+
+// withRowFilter 将行过滤条件附加到 context，filter 为 nil 时读取全部行
+func withRowFilter(ctx context.Context, filter rowFilter) context.Context {
+	return context.WithValue(ctx, rowFilterContextKey{}, filter)
+}
+
+// rowFilterFromContext 返回 context 中的行过滤条件
+func rowFilterFromContext(ctx context.Context) rowFilter {
+	filter, _ := ctx.Value(rowFilterContextKey{}).(rowFilter)
+	return filter
+}
+
+// WithPartition 将分区范围附加到 context，规则的查询只读取该范围内的行
+func WithPartition(ctx context.Context, partition *PartitionRange) context.Context {
+	if partition == nil {
+		return withRowFilter(ctx, nil)
+	}
+	return withRowFilter(ctx, partition)
+}
+
+// resolvePartitionColumn 返回分区列，未指定时使用单列主键
+func resolvePartitionColumn(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, columns []ColumnMetadata, name string) (ColumnMetadata, error) {
+	if name == "" {
+		constraints, err := provider.GetTableConstraints(ctx, db, config, tableName)
+		if err != nil {
+			return ColumnMetadata{}, err
+		}
+		for _, constraint := range constraints {
+			if constraint.ConstraintType == ConstraintPrimaryKey && len(constraint.Columns) == 1 {
+				name = constraint.Columns[0]
+				break
+			}
+		}
+		if name == "" {
+			return ColumnMetadata{}, fmt.Errorf("table %s has no single-column primary key, a partition column is required", tableName)
+		}
+	}
+
+	return findOrderedColumn(provider, columns, name)
+}
+
+// findOrderedColumn 查找可按范围划分的列（数值或日期时间列），日期时间列须能与 TimestampLiteral 比较
+func findOrderedColumn(provider DatabaseProvider, columns []ColumnMetadata, name string) (ColumnMetadata, error) {
+	for _, column := range columns {
+		if column.ColumnName != name {
+			continue
+		}
+		if isTemporalColumnType(column.ColumnType) {
+			if provider.Capabilities().UntypedTemporalColumns {
+				return ColumnMetadata{}, fmt.Errorf("column %s is temporal, %s cannot compare temporal columns by range, use a numeric column", name, provider.Name())
+			}
+			return column, nil
+		}
+		if !isNumericColumnType(column.ColumnType) {
+			return ColumnMetadata{}, fmt.Errorf("column %s must be numeric or temporal", name)
+		}
+		return column, nil
+	}
+	return ColumnMetadata{}, fmt.Errorf("column %s not found", name)
+}
+
+// planPartitionRanges 按分区列的最小值和最大值将表等宽拆分为至多 count 个键范围
+// 值域过窄产生重复边界时合并相邻分区，表为空或分区列全为 NULL 时返回一个不带条件的分区
+func planPartitionRanges(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, column ColumnMetadata, count int) ([]PartitionRange, error) {
+	col := provider.QuoteIdentifier(column.ColumnName)
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", col, col, provider.QuoteTableName(config, tableName))
+
+	var bounds []string
+	if isTemporalColumnType(column.ColumnType) {
+		var lower, upper sql.NullTime
+		if err := db.QueryRowContext(ctx, query).Scan(&lower, &upper); err != nil {
+			return nil, err
+		}
+		if lower.Valid && upper.Valid {
+			step := upper.Time.Sub(lower.Time) / time.Duration(count)
+			for i := 1; i < count; i++ {
+				bounds = append(bounds, provider.TimestampLiteral(lower.Time.Add(step*time.Duration(i))))
+			}
+		}
+	} else {
+		var lower, upper sql.NullFloat64
+		if err := db.QueryRowContext(ctx, query).Scan(&lower, &upper); err != nil {
+			return nil, err
+		}
+		if lower.Valid && upper.Valid {
+			step := (upper.Float64 - lower.Float64) / float64(count)
+			for i := 1; i < count; i++ {
+				bounds = append(bounds, strconv.FormatFloat(lower.Float64+step*float64(i), 'f', -1, 64))
+			}
+		}
+	}
+
+	ranges := []PartitionRange{{Column: column.ColumnName}}
+	for _, bound := range bounds {
+		current := &ranges[len(ranges)-1]
+		if bound == current.Lower {
+			continue
+		}
+		current.Upper = bound
+		ranges = append(ranges, PartitionRange{Index: len(ranges), Column: column.ColumnName, Lower: bound})
+	}
+	return ranges, nil
+}
+
+// mergeOp 部分聚合值跨分区的合并方式
+type mergeOp int
+
+const (
+	mergeSum    mergeOp = iota
+	mergeMin            // 忽略 NULL
+	mergeMax            // 忽略 NULL
+	mergeMean           // 按 weight 加权平均
+	mergeStdDev         // 由各分区的样本标准差、均值（mean）和计数（weight）合并
+)
+
+// valueRef 部分聚合值在计划中的位置
+type valueRef struct {
+	group int
+	index int
+}
+
+// partialMerge 单个聚合表达式的合并方式
+type partialMerge struct {
+	op     mergeOp
+	weight valueRef
+	mean   valueRef
+}
+
+// partialPlan 可按分区执行并合并的聚合计划
+// plan.groups 在各分区上执行，merge 与 groups 一一对应；合并后的值交给 plan.build 生成与整表执行相同的规则输出
+type partialPlan struct {
+	plan  *aggregatePlan
+	merge [][]partialMerge
+}
+
+// partialRule 能按分区执行并合并结果的规则，返回 nil 时规则只能整表执行
+type partialRule interface {
+	PlanPartial(ctx context.Context, provider DatabaseProvider, columns []ColumnMetadata, params RuleParams) *partialPlan
+}
+
+// partialState 一个分区上各规则的部分聚合值，规则名 -> 按组排列的值，NULL 为 nil
+type partialState map[string][][]*float64
+
+// uniformMerge 每组表达式按相同方式合并
+func uniformMerge(plan *aggregatePlan, ops ...mergeOp) [][]partialMerge {
+	merge := make([][]partialMerge, len(plan.groups))
+	for i := range plan.groups {
+		merge[i] = make([]partialMerge, len(ops))
+		for j, op := range ops {
+			merge[i][j] = partialMerge{op: op}
+		}
+	}
+	return merge
+}
+
+// rowCountPartial 行数按分区求和
+func rowCountPartial(ctx context.Context, provider DatabaseProvider) *partialPlan {
+	expressions, _ := provider.AggregateExpressions(AggregateRowCount, ColumnMetadata{})
+	plan := &aggregatePlan{
+		groups: [][]string{expressions},
+		build: func(values [][]sql.NullFloat64) interface{} {
+			return scaleSampledRowCount(ctx, int64(values[0][0].Float64))
+		},
+	}
+	return &partialPlan{plan: plan, merge: uniformMerge(plan, mergeSum)}
+}
+
+// nonNullRatePartial 非空率按各分区行数加权平均，首组为行数
+func nonNullRatePartial(provider DatabaseProvider, columns []ColumnMetadata) *partialPlan {
+	plan := nonNullRatePlan(provider, columns)
+	if plan == nil {
+		return nil
+	}
+	build := plan.build
+	rowCount, _ := provider.AggregateExpressions(AggregateRowCount, ColumnMetadata{})
+	plan = &aggregatePlan{
+		groups: append([][]string{rowCount}, plan.groups...),
+		build: func(values [][]sql.NullFloat64) interface{} {
+			return build(values[1:])
+		},
+	}
+
+	merge := uniformMerge(plan, mergeMean)
+	merge[0][0] = partialMerge{op: mergeSum}
+	for i := 1; i < len(merge); i++ {
+		merge[i][0].weight = valueRef{group: 0}
+	}
+	return &partialPlan{plan: plan, merge: merge}
+}
+
+// numericStatsPartial 数值列的最值、均值和标准差，分位数无法合并，结果中为空并标记为不可用
+func numericStatsPartial(provider DatabaseProvider, columns []ColumnMetadata) *partialPlan {
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return isNumericColumnType(column.ColumnType)
+	})
+	plan := columnAggregatePlan(provider, AggregateNumericMoments, columns, func(values [][]sql.NullFloat64) interface{} {
+		width := numericStatsWidth()
+		stats := make([]sql.NullFloat64, len(columns)*width)
+		for i := range columns {
+			copy(stats[i*width:], values[i][:4])
+		}
+		result := buildNumericStats(columns, stats, false)
+		for name, columnStats := range result {
+			columnStats.PercentilesUnavailable = true
+			result[name] = columnStats
+		}
+		return result
+	})
+	if plan == nil {
+		return nil
+	}
+
+	merge := make([][]partialMerge, len(plan.groups))
+	for i := range merge {
+		count := valueRef{group: i, index: 4}
+		merge[i] = []partialMerge{
+			{op: mergeMin},
+			{op: mergeMax},
+			{op: mergeMean, weight: count},
+			{op: mergeStdDev, weight: count, mean: valueRef{group: i, index: 2}},
+			{op: mergeSum},
+		}
+	}
+	return &partialPlan{plan: plan, merge: merge}
+}
+
+// stringStatsPartial 字符列统计，平均长度按各分区非空数加权
+func stringStatsPartial(provider DatabaseProvider, columns []ColumnMetadata) *partialPlan {
+	plan := stringStatsPlan(provider, columns)
+	if plan == nil {
+		return nil
+	}
+
+	merge := make([][]partialMerge, len(plan.groups))
+	for i := range merge {
+		merge[i] = []partialMerge{
+			{op: mergeSum},
+			{op: mergeMin},
+			{op: mergeMean, weight: valueRef{group: i, index: 0}},
+			{op: mergeMax},
+			{op: mergeSum},
+			{op: mergeSum},
+			{op: mergeSum},
+			{op: mergeSum},
+		}
+	}
+	return &partialPlan{plan: plan, merge: merge}
+}
+
+// mergePartials 合并各分区的部分聚合值，partials 按分区排列
+func mergePartials(merge [][]partialMerge, partials [][][]*float64) [][]sql.NullFloat64 {
+	merged := make([][]sql.NullFloat64, len(merge))
+	for group, ops := range merge {
+		merged[group] = make([]sql.NullFloat64, len(ops))
+		for index, op := range ops {
+			merged[group][index] = mergeValue(op, valueRef{group: group, index: index}, partials)
+		}
+	}
+	return merged
+}
+
+// mergeValue 合并单个聚合表达式在各分区上的值
+func mergeValue(merge partialMerge, ref valueRef, partials [][][]*float64) sql.NullFloat64 {
+	value := func(partial [][]*float64, ref valueRef) (float64, bool) {
+		if v := partial[ref.group][ref.index]; v != nil {
+			return *v, true
+		}
+		return 0, false
+	}
+
+	var result sql.NullFloat64
+	switch merge.op {
+	case mergeSum:
+		result.Valid = true
+		for _, partial := range partials {
+			v, _ := value(partial, ref)
+			result.Float64 += v
+		}
+	case mergeMin, mergeMax:
+		for _, partial := range partials {
+			v, ok := value(partial, ref)
+			if !ok {
+				continue
+			}
+			if !result.Valid || (merge.op == mergeMin && v < result.Float64) || (merge.op == mergeMax && v > result.Float64) {
+				result = sql.NullFloat64{Float64: v, Valid: true}
+			}
+		}
+	case mergeMean:
+		var total, weight float64
+		for _, partial := range partials {
+			v, ok := value(partial, ref)
+			w, _ := value(partial, merge.weight)
+			if ok && w > 0 {
+				total += v * w
+				weight += w
+			}
+		}
+		if weight > 0 {
+			result = sql.NullFloat64{Float64: total / weight, Valid: true}
+		}
+	case mergeStdDev:
+		// 总平方和 = 各分区组内平方和 (n-1)s² + 组间平方和 n(mean-总均值)²
+		var count, sum float64
+		for _, partial := range partials {
+			n, _ := value(partial, merge.weight)
+			mean, ok := value(partial, merge.mean)
+			if ok && n > 0 {
+				count += n
+				sum += n * mean
+			}
+		}
+		if count < 2 {
+			break
+		}
+		grandMean := sum / count
+		var squares float64
+		for _, partial := range partials {
+			n, _ := value(partial, merge.weight)
+			mean, ok := value(partial, merge.mean)
+			if !ok || n == 0 {
+				continue
+			}
+			if stddev, ok := value(partial, ref); ok {
+				squares += (n - 1) * stddev * stddev
+			}
+			squares += n * (mean - grandMean) * (mean - grandMean)
+		}
+		result = sql.NullFloat64{Float64: math.Sqrt(squares / (count - 1)), Valid: true}
+	}
+	return result
+}
+
+// SplitPartitionRules 将规则分为可按分区执行后合并的规则和需要整表执行的规则
+func (e *AnalysisEngine) SplitPartitionRules(rules []TaskRuleConfig) (partial, whole []TaskRuleConfig) {
+	for _, ruleConfig := range rules {
+		rule, exists := e.GetRule(ruleConfig.Name)
+		if _, ok := rule.(partialRule); exists && ok {
+			partial = append(partial, ruleConfig)
+		} else {
+			whole = append(whole, ruleConfig)
+		}
+	}
+	return partial, whole
+}
+
+// partialPlanFor 校验规则参数并生成部分聚合计划
+func (e *AnalysisEngine) partialPlanFor(ctx context.Context, provider DatabaseProvider, columns []ColumnMetadata, ruleConfig TaskRuleConfig) (*partialPlan, error) {
+	rule, exists := e.GetRule(ruleConfig.Name)
+	if !exists {
+		return nil, fmt.Errorf("unknown rule: %s", ruleConfig.Name)
+	}
+	params, err := ValidateRuleParams(rule.GetParameters(), ruleConfig.Params)
+	if err != nil {
+		return nil, err
+	}
+	partitioned, ok := rule.(partialRule)
+	if !ok {
+		return nil, fmt.Errorf("rule %s cannot be partitioned", ruleConfig.Name)
+	}
+	plan := partitioned.PlanPartial(ctx, provider, columns, params)
+	if plan == nil {
+		return nil, fmt.Errorf("rule %s cannot be partitioned on %s", ruleConfig.Name, provider.Name())
+	}
+	return plan, nil
+}
+
+// ExecutePartition 在 ctx 中的分区范围（或水位区间）上执行可合并的规则，返回各规则的部分聚合值
+// 任一规则失败时整个分区失败，以便分区作为一个整体重试
+func (e *AnalysisEngine) ExecutePartition(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, columns []ColumnMetadata, rules []TaskRuleConfig) (partialState, error) {
+	if err := prepareTable(provider, config, tableName, rules); err != nil {
+		return nil, fmt.Errorf("failed to prepare table: %w", err)
+	}
+
+	names := make([]string, 0, len(rules))
+	plans := make(map[string]*aggregatePlan, len(rules))
+	for _, ruleConfig := range rules {
+		partial, err := e.partialPlanFor(ctx, provider, columns, ruleConfig)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, ruleConfig.Name)
+		plans[ruleConfig.Name] = &aggregatePlan{
+			groups: partial.plan.groups,
+			build: func(values [][]sql.NullFloat64) interface{} {
+				return values
+			},
+		}
+	}
+
+	outputs, failed := executeAggregatePlans(ctx, db, config, provider, tableName, names, plans)
+	for name, err := range failed {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+
+	state := make(partialState, len(outputs))
+	for name, output := range outputs {
+		state[name] = nullableValues(output.([][]sql.NullFloat64))
+	}
+	return state, nil
+}
+
+// MergePartitions 合并各分区的部分聚合值，将规则输出写入 result，并返回合并后的部分聚合值供之后继续累计
+func (e *AnalysisEngine) MergePartitions(ctx context.Context, provider DatabaseProvider, columns []ColumnMetadata, rules []TaskRuleConfig, states []partialState, result *AnalysisResultSet) partialState {
+	merged := make(partialState, len(rules))
+	for _, ruleConfig := range rules {
+		partial, err := e.partialPlanFor(ctx, provider, columns, ruleConfig)
+		if err != nil {
+			result.SetRuleError(ruleConfig.Name, RuleStatusFailed, err)
+			continue
+		}
+
+		partials := make([][][]*float64, 0, len(states))
+		for i, state := range states {
+			values, ok := state[ruleConfig.Name]
+			if !ok {
+				err = fmt.Errorf("partition %d has no result", i)
+				break
+			}
+			partials = append(partials, values)
+		}
+		if err != nil {
+			result.SetRuleError(ruleConfig.Name, RuleStatusFailed, err)
+			continue
+		}
+		values := mergePartials(partial.merge, partials)
+		merged[ruleConfig.Name] = nullableValues(values)
+		result.SetRuleOutput(ruleConfig.Name, partial.plan.build(values))
+	}
+	return merged
+}
+
+// nullableValues 将按组排列的查询结果转换为部分聚合值，NULL 为 nil
+func nullableValues(groups [][]sql.NullFloat64) [][]*float64 {
+	values := make([][]*float64, len(groups))
+	for i, group := range groups {
+		values[i] = make([]*float64, len(group))
+		for j, value := range group {
+			values[i][j] = nullableFloat(value)
+		}
+	}
+	return values
+}
+
+// PartitionInfo 分区分析结果的说明
+type PartitionInfo struct {
+	Column      string   `json:"column"`
+	Count       int      `json:"count"`
+	MergedRules []string `json:"mergedRules"` // 按分区执行后合并的规则，其余规则整表执行
+}
+
+// describePartitions 返回分区范围的可读描述，用于日志
+func describePartitions(provider DatabaseProvider, ranges []PartitionRange) string {
+	conditions := make([]string, len(ranges))
+	for i := range ranges {
+		conditions[i] = ranges[i].condition(provider)
+	}
+	return strings.Join(conditions, "; ")
+}
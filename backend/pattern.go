@@ -0,0 +1,318 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PatternDefinition 命名的格式模式
+type PatternDefinition struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"` // Go (RE2) 正则表达式
+	Builtin     bool   `json:"builtin"`
+}
+
+// compiledPattern 编译后的模式，validate 用于正则之外的额外校验（如身份证校验位）
+type compiledPattern struct {
+	definition PatternDefinition
+	regex      *regexp.Regexp
+	validate   func(value string) bool
+}
+
+func (p *compiledPattern) matches(value string) bool {
+	if !p.regex.MatchString(value) {
+		return false
+	}
+	return p.validate == nil || p.validate(value)
+}
+
+// PatternRegistry 格式模式注册表，包含内置模式和用户自定义模式
+type PatternRegistry struct {
+	mu       sync.RWMutex
+	patterns map[string]*compiledPattern
+}
+
+// NewPatternRegistry 创建包含内置模式的注册表
+func NewPatternRegistry() *PatternRegistry {
+	registry := &PatternRegistry{
+		patterns: make(map[string]*compiledPattern),
+	}
+
+	registry.registerBuiltin("email", "电子邮箱", `^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`, nil)
+	registry.registerBuiltin("cn_mobile", "中国大陆手机号", `^(?:\+?86)?1[3-9]\d{9}$`, nil)
+	registry.registerBuiltin("cn_resident_id", "18位居民身份证号（含校验位）", `^[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]$`, validResidentIDChecksum)
+	registry.registerBuiltin("uuid", "UUID", `^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`, nil)
+	registry.registerBuiltin("iso_date", "ISO 8601 日期/时间字符串", `^\d{4}-(?:0[1-9]|1[0-2])-(?:0[1-9]|[12]\d|3[01])(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+\-]\d{2}:?\d{2})?)?$`, nil)
+	registry.registerBuiltin("url", "URL", `^(?:https?|ftp)://[^\s/$.?#][^\s]*$`, nil)
+	registry.registerBuiltin("ipv4", "IPv4 地址", `^(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)$`, nil)
+	registry.registerBuiltin("ipv6", "IPv6 地址", `^[0-9A-Fa-f:.]*:[0-9A-Fa-f:.]*$`, func(value string) bool {
+		return net.ParseIP(value) != nil
+	})
+
+	return registry
+}
+
+func (r *PatternRegistry) registerBuiltin(name, description, expression string, validate func(string) bool) {
+	r.patterns[name] = &compiledPattern{
+		definition: PatternDefinition{
+			ID:          name,
+			Name:        name,
+			Description: description,
+			Expression:  expression,
+			Builtin:     true,
+		},
+		regex:    regexp.MustCompile(expression),
+		validate: validate,
+	}
+}
+
+// ValidatePattern 校验自定义模式定义
+func (r *PatternRegistry) ValidatePattern(definition PatternDefinition) error {
+	if strings.TrimSpace(definition.Name) == "" {
+		return fmt.Errorf("pattern name is required")
+	}
+	if _, err := regexp.Compile(definition.Expression); err != nil {
+		return fmt.Errorf("invalid pattern expression: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if existing, ok := r.patterns[definition.Name]; ok && existing.definition.Builtin {
+		return fmt.Errorf("pattern %s conflicts with a builtin pattern", definition.Name)
+	}
+	return nil
+}
+
+// Register 注册或替换自定义模式
+func (r *PatternRegistry) Register(definition PatternDefinition) error {
+	if err := r.ValidatePattern(definition); err != nil {
+		return err
+	}
+
+	definition.Builtin = false
+	compiled := &compiledPattern{
+		definition: definition,
+		regex:      regexp.MustCompile(definition.Expression),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns[definition.Name] = compiled
+	return nil
+}
+
+// Remove 移除自定义模式，内置模式不可移除
+func (r *PatternRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.patterns[name]; ok && !existing.definition.Builtin {
+		delete(r.patterns, name)
+	}
+}
+
+// List 按名称排序返回所有模式（内置在前）
+func (r *PatternRegistry) List() []PatternDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]PatternDefinition, 0, len(r.patterns))
+	for _, pattern := range r.patterns {
+		definitions = append(definitions, pattern.definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		if definitions[i].Builtin != definitions[j].Builtin {
+			return definitions[i].Builtin
+		}
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// snapshot 返回当前模式的副本，供单次分析使用
+func (r *PatternRegistry) snapshot() []*compiledPattern {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make([]*compiledPattern, 0, len(r.patterns))
+	for _, pattern := range r.patterns {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// residentIDWeights 身份证前17位加权因子
+var residentIDWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// residentIDCheckCodes 身份证校验码，按加权和模11取值
+const residentIDCheckCodes = "10X98765432"
+
+// validResidentIDChecksum 校验18位身份证号的校验位（GB 11643-1999）
+func validResidentIDChecksum(value string) bool {
+	if len(value) != 18 {
+		return false
+	}
+	sum := 0
+	for i, weight := range residentIDWeights {
+		digit := value[i] - '0'
+		if digit > 9 {
+			return false
+		}
+		sum += int(digit) * weight
+	}
+	return strings.ToUpper(value[17:]) == string(residentIDCheckCodes[sum%11])
+}
+
+// ColumnPatternStats 列的格式符合情况
+type ColumnPatternStats struct {
+	SampleCount     int64              `json:"sample_count"` // 参与匹配的非空样本数
+	Rates           map[string]float64 `json:"rates"`        // 各模式的符合率，只包含符合率大于0的模式
+	DominantPattern string             `json:"dominant_pattern,omitempty"`
+}
+
+// defaultPatternSampleSize 默认采样行数
+const defaultPatternSampleSize = 1000
+
+// PatternMatchRule 格式识别规则，对字符列采样并按注册表中的模式分类
+type PatternMatchRule struct {
+	Registry   *PatternRegistry
+	SampleSize int
+}
+
+func (r *PatternMatchRule) GetName() string {
+	return "pattern_match"
+}
+
+func (r *PatternMatchRule) GetDescription() string {
+	return "采样字符列并识别邮箱、手机号、身份证号、UUID、日期、URL、IP 等格式的符合率"
+}
+
+func (r *PatternMatchRule) SupportsSampling() bool {
+	return true
+}
+
+func (r *PatternMatchRule) GetParameters() []RuleParameter {
+	return []RuleParameter{
+		{Name: "sample_size", Type: ParamInteger, Description: "采样行数", Default: r.SampleSize, Min: paramBound(1), Max: paramBound(1000000)},
+		{Name: "patterns", Type: ParamStringList, Description: "只匹配指定名称的模式，为空时匹配全部模式"},
+		{Name: "columns", Type: ParamStringList, Description: "只检查指定的列，为空时检查全部字符列"},
+	}
+}
+
+func (r *PatternMatchRule) Execute(ctx context.Context, db *sql.DB, tableName string, config *DatabaseConfig, provider DatabaseProvider, params RuleParams) (interface{}, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("database provider not available")
+	}
+	if r.Registry == nil {
+		return nil, fmt.Errorf("pattern registry not available")
+	}
+
+	columns, err := tableColumns(ctx, db, config, provider, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns = filterColumns(selectColumns(columns, params.StringList("columns", nil)), func(column ColumnMetadata) bool {
+		return isStringColumnType(column.ColumnType)
+	})
+
+	result := make(map[string]*ColumnPatternStats, len(columns))
+	if len(columns) == 0 {
+		return result, nil
+	}
+
+	sampleSize := params.Int("sample_size", r.SampleSize)
+	if sampleSize <= 0 {
+		sampleSize = defaultPatternSampleSize
+	}
+
+	patterns := r.Registry.snapshot()
+	if names := params.StringList("patterns", nil); len(names) > 0 {
+		selected := make(map[string]bool, len(names))
+		for _, name := range names {
+			selected[name] = true
+		}
+		filtered := patterns[:0]
+		for _, pattern := range patterns {
+			if selected[pattern.definition.Name] {
+				filtered = append(filtered, pattern)
+			}
+		}
+		patterns = filtered
+	}
+	matchCounts := make([]map[string]int64, len(columns))
+	sampleCounts := make([]int64, len(columns))
+	for i := range matchCounts {
+		matchCounts[i] = make(map[string]int64)
+	}
+
+	// 列数超过方言的 SELECT 上限时分块采样，各块独立取样本行
+	for _, chunk := range columnChunks(len(columns), 1, provider.Capabilities().MaxSelectExpressions) {
+		if err := samplePatternMatches(ctx, db, config, provider, tableName, columns[chunk[0]:chunk[1]], sampleSize, patterns, matchCounts[chunk[0]:chunk[1]], sampleCounts[chunk[0]:chunk[1]]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, column := range columns {
+		stats := &ColumnPatternStats{
+			SampleCount: sampleCounts[i],
+			Rates:       make(map[string]float64, len(matchCounts[i])),
+		}
+		var dominantCount int64
+		for name, count := range matchCounts[i] {
+			stats.Rates[name] = float64(count) / float64(sampleCounts[i])
+			if count > dominantCount || (count == dominantCount && name < stats.DominantPattern) {
+				dominantCount = count
+				stats.DominantPattern = name
+			}
+		}
+		result[column.ColumnName] = stats
+	}
+
+	return result, nil
+}
+
+// samplePatternMatches 读取一块列的样本行，累计各列的非空样本数和各模式的匹配数
+func samplePatternMatches(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string, columns []ColumnMetadata, sampleSize int, patterns []*compiledPattern, matchCounts []map[string]int64, sampleCounts []int64) error {
+	selectParts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		selectParts = append(selectParts, provider.QuoteIdentifier(column.ColumnName))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), sampledTableRef(ctx, provider, config, tableName))
+
+	rows, err := db.QueryContext(ctx, provider.LimitQuery(query, sampleSize))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		for i, value := range values {
+			if !value.Valid {
+				continue
+			}
+			sampleCounts[i]++
+			for _, pattern := range patterns {
+				if pattern.matches(value.String) {
+					matchCounts[i][pattern.definition.Name]++
+				}
+			}
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,308 @@
+package backend
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvFormat 目录中的 CSV/TSV 文件，表头为列名，列类型由数据推断
+type csvFormat struct{}
+
+func (f *csvFormat) name() string {
+	return "csv"
+}
+
+// version 类型推断或导入方式变化后递增
+func (f *csvFormat) version() int {
+	return 1
+}
+
+func (f *csvFormat) extensions() []string {
+	return []string{".csv", ".tsv", ".tab"}
+}
+
+// datasets 各文件有各自的表头，不合并
+func (f *csvFormat) datasets() bool {
+	return false
+}
+
+// csvDelimiter .tsv/.tab 文件以制表符分隔，其余以逗号分隔
+func csvDelimiter(path string) rune {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".tab":
+		return '\t'
+	}
+	return ','
+}
+
+// importFile 逐行读取文件两遍：第一遍推断列类型，第二遍重建表并写入
+func (f *csvFormat) importFile(tx *sql.Tx, file stagedFile) (int64, error) {
+	columns, types, err := inferCSVColumns(file)
+	if err != nil {
+		return 0, err
+	}
+
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		definitions[i] = fmt.Sprintf("%s %s", quoteSQLiteIdentifier(column), types[i])
+	}
+	stmt, err := createStagedTable(tx, file.table, definitions)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var rowCount int64
+	values := make([]interface{}, len(columns))
+	err = readCSVRecords(file, func(record []string) error {
+		for i := range values {
+			values[i] = nil
+			if i < len(record) {
+				values[i] = types[i].convert(record[i])
+			}
+		}
+		rowCount++
+		_, err := stmt.Exec(values...)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+// openCSVReader 打开文件并读取表头，返回的 reader 从第一行数据开始
+func openCSVReader(file stagedFile) (*os.File, *csv.Reader, []string, error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reader := csv.NewReader(f)
+	reader.Comma = csvDelimiter(file.path)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		f.Close()
+		return nil, nil, nil, fmt.Errorf("file has no header row")
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	return f, reader, csvColumnNames(header), nil
+}
+
+// readCSVRecords 逐行读取表头之后的记录，空行跳过
+func readCSVRecords(file stagedFile, handle func(record []string) error) error {
+	f, reader, _, err := openCSVReader(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(record); err != nil {
+			return err
+		}
+	}
+}
+
+// csvColumnNames 由表头生成列名：去除 BOM 和首尾空白
+func csvColumnNames(header []string) []string {
+	names := make([]string, len(header))
+	for i, raw := range header {
+		names[i] = strings.TrimSpace(strings.TrimPrefix(raw, "\ufeff"))
+	}
+	return uniqueColumnNames(names)
+}
+
+// csvColumnType 推断的列类型，取值即建表时声明的类型
+type csvColumnType string
+
+const (
+	csvInteger  csvColumnType = "INTEGER"
+	csvReal     csvColumnType = "REAL"
+	csvDate     csvColumnType = "DATE"
+	csvDatetime csvColumnType = "DATETIME"
+	csvText     csvColumnType = "TEXT"
+)
+
+// csvDateLayouts / csvDatetimeLayouts 可识别的日期、日期时间格式
+var (
+	csvDateLayouts     = []string{"2006-01-02", "2006/01/02"}
+	csvDatetimeLayouts = []string{
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		time.RFC3339Nano,
+		"2006/01/02 15:04:05",
+		"2006-01-02 15:04",
+	}
+)
+
+// csvTypeCandidates 列在已读取的值上仍可能成立的类型
+type csvTypeCandidates struct {
+	seen     bool
+	integer  bool
+	real     bool
+	date     bool
+	datetime bool
+}
+
+// observe 排除与值不符的类型，空值不参与推断
+func (c *csvTypeCandidates) observe(value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	c.seen = true
+	if c.integer && !isCSVInteger(value) {
+		c.integer = false
+	}
+	if c.real && !isCSVReal(value) {
+		c.real = false
+	}
+	if c.date {
+		if _, ok := parseCSVTime(value, csvDateLayouts); !ok {
+			c.date = false
+		}
+	}
+	if c.datetime {
+		if _, ok := parseCSVTime(value, csvDatetimeLayouts); !ok {
+			if _, ok := parseCSVTime(value, csvDateLayouts); !ok {
+				c.datetime = false
+			}
+		}
+	}
+}
+
+// resolve 返回最具体的类型，全部为空的列按文本处理
+func (c *csvTypeCandidates) resolve() csvColumnType {
+	switch {
+	case !c.seen:
+		return csvText
+	case c.integer:
+		return csvInteger
+	case c.real:
+		return csvReal
+	case c.date:
+		return csvDate
+	case c.datetime:
+		return csvDatetime
+	}
+	return csvText
+}
+
+// inferCSVColumns 读取整个文件推断各列类型
+func inferCSVColumns(file stagedFile) ([]string, []csvColumnType, error) {
+	f, _, columns, err := openCSVReader(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.Close()
+
+	candidates := make([]csvTypeCandidates, len(columns))
+	for i := range candidates {
+		candidates[i] = csvTypeCandidates{integer: true, real: true, date: true, datetime: true}
+	}
+	err = readCSVRecords(file, func(record []string) error {
+		for i := 0; i < len(columns) && i < len(record); i++ {
+			candidates[i].observe(record[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	types := make([]csvColumnType, len(columns))
+	for i := range candidates {
+		types[i] = candidates[i].resolve()
+	}
+	return columns, types, nil
+}
+
+// convert 将文件中的值转换为写入缓存库的值，空字段为 NULL；日期时间统一为 SQLite 的标准文本格式（UTC）
+// 文本保留原文以便统计首尾空白
+func (t csvColumnType) convert(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(value)
+	switch t {
+	case csvInteger:
+		if v, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return v
+		}
+	case csvReal:
+		if v, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return v
+		}
+	case csvDate:
+		if v, ok := parseCSVTime(trimmed, csvDateLayouts); ok {
+			return v.Format("2006-01-02")
+		}
+	case csvDatetime:
+		if v, ok := parseCSVTime(trimmed, csvDatetimeLayouts); ok {
+			return v.UTC().Format("2006-01-02 15:04:05.999999999")
+		}
+		if v, ok := parseCSVTime(trimmed, csvDateLayouts); ok {
+			return v.Format("2006-01-02 15:04:05")
+		}
+	}
+	if t != csvText && trimmed == "" {
+		return nil
+	}
+	return value
+}
+
+// isCSVInteger 判断是否为十进制整数；有前导零的值（如编码、邮编）按文本处理
+func isCSVInteger(value string) bool {
+	digits := strings.TrimPrefix(value, "-")
+	if len(digits) > 1 && digits[0] == '0' {
+		return false
+	}
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+// isCSVReal 判断是否为十进制数，排除 ParseFloat 接受的 Inf、NaN 及十六进制写法，有前导零的值同样按文本处理
+func isCSVReal(value string) bool {
+	if strings.IndexFunc(value, func(r rune) bool {
+		return !strings.ContainsRune("0123456789+-.eE", r)
+	}) >= 0 {
+		return false
+	}
+	integer := strings.TrimLeft(value, "+-")
+	if len(integer) > 1 && integer[0] == '0' && integer[1] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// parseCSVTime 依次尝试各格式解析日期时间
+func parseCSVTime(value string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
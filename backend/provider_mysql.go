@@ -5,14 +5,69 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
+)
+
+// mysqlFlavor 兼容 MySQL 协议的数据库，系统表和 SQL 方言与 MySQL 存在差异
+type mysqlFlavor string
+
+const (
+	mysqlFlavorMySQL     mysqlFlavor = "mysql"
+	mysqlFlavorMariaDB   mysqlFlavor = "mariadb"
+	mysqlFlavorTiDB      mysqlFlavor = "tidb"
+	mysqlFlavorDoris     mysqlFlavor = "doris"
+	mysqlFlavorStarRocks mysqlFlavor = "starrocks"
 )
 
 type mysqlProvider struct {
 	baseProvider
+	flavor mysqlFlavor // 为空时按 MySQL 处理，连接后按服务端版本识别实际的数据库
 }
 
 func (p *mysqlProvider) Name() string {
-	return "mysql"
+	if p.flavor == "" {
+		return string(mysqlFlavorMySQL)
+	}
+	return string(p.flavor)
+}
+
+// analytic Doris、StarRocks 为 MPP 分析型数据库，没有 InnoDB 式的约束与索引元数据
+func (p *mysqlProvider) analytic() bool {
+	return p.flavor == mysqlFlavorDoris || p.flavor == mysqlFlavorStarRocks
+}
+
+// detectMySQLFlavor 按 VERSION() 与 @@version_comment 识别数据库
+// TiDB 的版本形如 8.0.11-TiDB-v7.5.0，MariaDB 形如 10.11.6-MariaDB；Doris、StarRocks 伪装为 5.7.99，仅能从 version_comment 区分
+func detectMySQLFlavor(version, comment string) mysqlFlavor {
+	version, comment = strings.ToLower(version), strings.ToLower(comment)
+	switch {
+	case strings.Contains(version, "tidb"):
+		return mysqlFlavorTiDB
+	case strings.Contains(version, "mariadb"):
+		return mysqlFlavorMariaDB
+	case strings.Contains(comment, "starrocks"):
+		return mysqlFlavorStarRocks
+	case strings.Contains(comment, "doris"):
+		return mysqlFlavorDoris
+	}
+	return mysqlFlavorMySQL
+}
+
+// Resolve 连接类型为 MySQL 时识别实际的数据库，返回对应方言的提供者；显式选择的方言保持不变
+func (p *mysqlProvider) Resolve(ctx context.Context, db *sql.DB) (DatabaseProvider, error) {
+	if p.flavor != "" {
+		return p, nil
+	}
+	var version string
+	var comment sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT VERSION(), @@version_comment").Scan(&version, &comment); err != nil {
+		return p, err
+	}
+	flavor := detectMySQLFlavor(version, comment.String)
+	if flavor == mysqlFlavorMySQL {
+		return p, nil
+	}
+	return resolveProvider(string(flavor))
 }
 
 func (p *mysqlProvider) DriverName() string {
@@ -59,19 +114,25 @@ func (p *mysqlProvider) GetTables(ctx context.Context, db *sql.DB, _ *DatabaseCo
 func (p *mysqlProvider) GetTableMetadata(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]interface{}, error) {
 	metadata := make(map[string]interface{})
 
-	rowCount, err := p.ExecuteRowCount(ctx, db, config, tableName)
+	rowCount, estimated, err := estimateRowCount(ctx, db, p, config, tableName)
 	if err != nil {
 		return nil, err
 	}
 	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = estimated
 
+	// Doris、StarRocks 的 index_length 为 0 或 NULL，data_length 已包含前缀索引等数据
+	sizeExpr := "data_length + index_length"
+	if p.analytic() {
+		sizeExpr = "data_length"
+	}
 	var dataSize sql.NullInt64
-	err = db.QueryRowContext(ctx, `
+	err = db.QueryRowContext(ctx, fmt.Sprintf(`
 		SELECT
-			SUM(data_length + index_length)
+			SUM(%s)
 		FROM information_schema.tables
 		WHERE table_schema = ? AND table_name = ?
-	`, config.Database, tableName).Scan(&dataSize)
+	`, sizeExpr), config.Database, tableName).Scan(&dataSize)
 	if err != nil {
 		metadata["data_size"] = int64(0)
 	} else if dataSize.Valid {
@@ -110,7 +171,12 @@ func (p *mysqlProvider) GetTableColumns(ctx context.Context, db *sql.DB, config
 			column_name,
 			COALESCE(column_comment, '') AS column_comment,
 			ordinal_position,
-			column_type
+			column_type,
+			is_nullable,
+			column_default,
+			character_maximum_length,
+			numeric_precision,
+			numeric_scale
 		FROM information_schema.columns
 		WHERE table_schema = ? AND table_name = ?
 		ORDER BY ordinal_position
@@ -125,22 +191,88 @@ func (p *mysqlProvider) GetTableColumns(ctx context.Context, db *sql.DB, config
 	var columns []ColumnMetadata
 	for rows.Next() {
 		var column ColumnMetadata
-		if err := rows.Scan(
+		var details columnDetails
+		if err := rows.Scan(append([]interface{}{
 			&column.ColumnName,
 			&column.ColumnComment,
 			&column.ColumnOrdinal,
 			&column.ColumnType,
-		); err != nil {
+		}, details.scanArgs()...)...); err != nil {
 			return nil, err
 		}
+		details.apply(&column)
 		columns = append(columns, column)
 	}
 
 	return columns, nil
 }
 
+func (p *mysqlProvider) GetTableConstraints(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableConstraint, error) {
+	// Doris、StarRocks 的键由表模型定义且不强制约束，information_schema 中没有对应记录
+	if p.analytic() {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			tc.constraint_name,
+			tc.constraint_type,
+			kcu.column_name,
+			CASE
+				WHEN kcu.referenced_table_name IS NULL THEN NULL
+				WHEN kcu.referenced_table_schema = tc.table_schema THEN kcu.referenced_table_name
+				ELSE CONCAT(kcu.referenced_table_schema, '.', kcu.referenced_table_name)
+			END AS referenced_table,
+			kcu.referenced_column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema
+			AND kcu.constraint_name = tc.constraint_name
+			AND kcu.table_schema = tc.table_schema
+			AND kcu.table_name = tc.table_name
+		WHERE tc.table_schema = ? AND tc.table_name = ?
+			AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY tc.constraint_name, kcu.ordinal_position
+	`
+
+	rows, err := db.QueryContext(ctx, query, config.Database, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableConstraints(rows)
+}
+
+func (p *mysqlProvider) GetTableIndexes(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableIndex, error) {
+	// Doris、StarRocks 的前缀索引、Bloom Filter 等不出现在 information_schema.statistics 中
+	if p.analytic() {
+		return nil, nil
+	}
+
+	query := `
+		SELECT
+			index_name,
+			column_name,
+			CASE WHEN non_unique = 0 THEN 1 ELSE 0 END,
+			index_type,
+			CASE WHEN index_name = 'PRIMARY' THEN 1 ELSE 0 END
+		FROM information_schema.statistics
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY index_name, seq_in_index
+	`
+
+	rows, err := db.QueryContext(ctx, query, config.Database, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableIndexes(rows)
+}
+
 func (p *mysqlProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", p.QuoteTableName(config, tableName))
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", sampledTableRef(ctx, p, config, tableName))
 	var rowCount int64
 	if err := db.QueryRowContext(ctx, query).Scan(&rowCount); err != nil {
 		return 0, err
@@ -148,44 +280,123 @@ func (p *mysqlProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config
 	return rowCount, nil
 }
 
-func (p *mysqlProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
-	columns, err := p.GetTableColumns(ctx, db, config, tableName)
+// mysqlStatisticsRowCountQueries 各数据库自身统计表中的行数
+// MariaDB 为 ANALYZE TABLE ... PERSISTENT 收集的引擎无关统计，TiDB 的 stats_meta 随 DML 增量维护
+var mysqlStatisticsRowCountQueries = map[mysqlFlavor]string{
+	mysqlFlavorMariaDB: `
+		SELECT cardinality
+		FROM mysql.table_stats
+		WHERE db_name = ? AND table_name = ?
+	`,
+	mysqlFlavorTiDB: `
+		SELECT m.count
+		FROM mysql.stats_meta m
+		JOIN information_schema.tables t ON t.tidb_table_id = m.table_id
+		WHERE t.table_schema = ? AND t.table_name = ?
+	`,
+}
+
+// EstimateRowCount InnoDB 的 table_rows 为采样估算值，误差可能达到 40%-50%
+// 统计表不可读（权限不足、未收集统计）时回退到 information_schema
+func (p *mysqlProvider) EstimateRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	var rowCount sql.NullInt64
+	if query, ok := mysqlStatisticsRowCountQueries[p.flavor]; ok {
+		err := db.QueryRowContext(ctx, query, config.Database, tableName).Scan(&rowCount)
+		if err == nil && rowCount.Valid {
+			return rowCount.Int64, nil
+		}
+	}
+
+	err := db.QueryRowContext(ctx, `
+		SELECT table_rows
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_name = ?
+	`, config.Database, tableName).Scan(&rowCount)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	if len(columns) == 0 {
-		return map[string]float64{}, nil
+	if !rowCount.Valid {
+		return -1, nil
 	}
+	return rowCount.Int64, nil
+}
 
-	selectParts := make([]string, 0, len(columns))
-	for _, column := range columns {
-		col := p.QuoteIdentifier(column.ColumnName)
-		selectParts = append(selectParts, fmt.Sprintf("1 - AVG(%s IS NULL) AS %s", col, col))
-	}
+func (p *mysqlProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
+	return executeNonNullRate(ctx, db, config, p, tableName)
+}
 
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), p.QuoteTableName(config, tableName))
-	row := db.QueryRowContext(ctx, query)
+func (p *mysqlProvider) ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error) {
+	return executeDistinctCount(ctx, db, config, p, tableName)
+}
 
-	values := make([]sql.NullFloat64, len(columns))
-	scanArgs := make([]interface{}, len(columns))
-	for i := range values {
-		scanArgs[i] = &values[i]
+func (p *mysqlProvider) ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error) {
+	return executeNearestRankNumericStats(ctx, db, config, p, tableName)
+}
+
+func (p *mysqlProvider) ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	temporal := isTemporalColumnType(column.ColumnType)
+	valueExpr := col
+	if temporal {
+		valueExpr = fmt.Sprintf("TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', %s)", col)
 	}
 
-	if err := row.Scan(scanArgs...); err != nil {
-		return nil, err
+	dialect := histogramDialect{
+		tableRef:  sampledTableRef(ctx, p, config, tableName),
+		column:    col,
+		valueExpr: valueExpr,
+		bucketExpr: func(lower, upper float64, bins int) string {
+			return fmt.Sprintf("LEAST(FLOOR((%s - %s) * %d / (%s - %s)), %d)",
+				valueExpr, sqlFloat(lower), bins, sqlFloat(upper), sqlFloat(lower), bins-1)
+		},
 	}
+	return executeHistogram(ctx, db, dialect, bins, mode, temporal)
+}
+
+func (p *mysqlProvider) ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error) {
+	return executeStringStats(ctx, db, config, p, tableName)
+}
 
-	result := make(map[string]float64, len(columns))
-	for i, column := range columns {
-		val := 0.0
-		if values[i].Valid {
-			val = clampRatio(values[i].Float64)
+func (p *mysqlProvider) AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	switch metric {
+	case AggregateRowCount:
+		return []string{"COUNT(*)"}, true
+	case AggregateNonNullRate:
+		// Doris、StarRocks 的 AVG 不接受布尔参数
+		if p.analytic() {
+			return []string{fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1 ELSE 0 END)", col)}, true
+		}
+		return []string{fmt.Sprintf("1 - AVG(%s IS NULL)", col)}, true
+	case AggregateDistinctCount:
+		return []string{fmt.Sprintf("COUNT(DISTINCT %s)", col)}, true
+	case AggregateNumericMoments:
+		return []string{
+			fmt.Sprintf("MIN(%s)", col),
+			fmt.Sprintf("MAX(%s)", col),
+			fmt.Sprintf("AVG(%s)", col),
+			fmt.Sprintf("STDDEV_SAMP(%s)", col),
+			fmt.Sprintf("COUNT(%s)", col),
+		}, true
+	case AggregateStringStats:
+		trailing := fmt.Sprintf("SUM(CASE WHEN %s REGEXP '[[:space:]]$' THEN 1 ELSE 0 END)", col)
+		if isFixedCharColumnType(column.ColumnType) {
+			trailing = "0"
 		}
-		result[column.ColumnName] = val
+		return []string{
+			fmt.Sprintf("COUNT(%s)", col),
+			fmt.Sprintf("MIN(CHAR_LENGTH(%s))", col),
+			fmt.Sprintf("AVG(CHAR_LENGTH(%s))", col),
+			fmt.Sprintf("MAX(CHAR_LENGTH(%s))", col),
+			// 使用长度判断空串：PAD SPACE 排序规则下 '' = ' ' 为真
+			fmt.Sprintf("SUM(CASE WHEN CHAR_LENGTH(%s) = 0 THEN 1 ELSE 0 END)", col),
+			fmt.Sprintf("SUM(CASE WHEN %s REGEXP '^[[:space:]]' THEN 1 ELSE 0 END)", col),
+			trailing,
+			fmt.Sprintf("SUM(CASE WHEN %s REGEXP '^[[:space:]]+$' THEN 1 ELSE 0 END)", col),
+		}, true
 	}
-
-	return result, nil
+	// 分位数需要窗口函数单独扫描
+	return nil, false
 }
 
 func (p *mysqlProvider) QuoteIdentifier(name string) string {
@@ -201,6 +412,43 @@ func (p *mysqlProvider) QuoteTableName(_ *DatabaseConfig, tableName string) stri
 	return strings.Join(parts, ".")
 }
 
+// TableSource MySQL 没有 TABLESAMPLE，按带种子的 RAND() 过滤行，仍需扫描全表但避免了聚合和排序的开销
+// MariaDB、StarRocks 同样没有 TABLESAMPLE，TiDB 的 TABLESAMPLE REGIONS() 不能指定比例；Doris 按 tablet 采样，粒度较粗
+func (p *mysqlProvider) TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string {
+	if p.flavor == mysqlFlavorDoris {
+		return fmt.Sprintf("%s TABLESAMPLE(%g PERCENT) REPEATABLE %d", p.QuoteTableName(config, tableName), sampling.Percent, sampling.seed())
+	}
+	return fmt.Sprintf("(SELECT * FROM %s WHERE RAND(%d) < %g) sampled_rows", p.QuoteTableName(config, tableName), sampling.seed(), sampling.Percent/100)
+}
+
+// TimestampLiteral Doris、StarRocks 不支持 TIMESTAMP 字面量，使用字符串与 DATETIME 列比较时隐式转换
+func (p *mysqlProvider) TimestampLiteral(t time.Time) string {
+	if p.analytic() {
+		return fmt.Sprintf("'%s'", t.Format("2006-01-02 15:04:05.999999"))
+	}
+	return fmt.Sprintf("TIMESTAMP '%s'", t.Format("2006-01-02 15:04:05.999999"))
+}
+
+// mysqlLOBColumnTypes 大文本与二进制类型可以分组，但只按 max_sort_length 前缀比较且需要临时表，视为大对象
+var mysqlLOBColumnTypes = map[string]bool{
+	"tinyblob":   true,
+	"blob":       true,
+	"mediumblob": true,
+	"longblob":   true,
+	"mediumtext": true,
+	"longtext":   true,
+	"json":       true,
+}
+
+// Capabilities MySQL 单表最多 4096 列，查询结果列数受同一限制
+func (p *mysqlProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{MaxSelectExpressions: 4096, LOBColumnTypes: mysqlLOBColumnTypes}
+}
+
+func (p *mysqlProvider) LimitQuery(query string, limit int) string {
+	return fmt.Sprintf("%s LIMIT %d", query, limit)
+}
+
 func clampRatio(value float64) float64 {
 	if value < 0 {
 		return 0
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type oracleProvider struct {
@@ -57,11 +58,12 @@ func (p *oracleProvider) GetTableMetadata(ctx context.Context, db *sql.DB, confi
 	owner, table := splitSchemaAndTable(tableName, strings.ToUpper(config.Username))
 	metadata := make(map[string]interface{})
 
-	rowCount, err := p.ExecuteRowCount(ctx, db, config, tableName)
+	rowCount, estimated, err := estimateRowCount(ctx, db, p, config, tableName)
 	if err != nil {
 		return nil, err
 	}
 	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = estimated
 
 	var columnCount int
 	err = db.QueryRowContext(ctx, `
@@ -86,7 +88,12 @@ func (p *oracleProvider) GetTableColumns(ctx context.Context, db *sql.DB, config
 			c.COLUMN_NAME,
 			NVL(cc.COMMENTS, ''),
 			c.COLUMN_ID,
-			c.DATA_TYPE
+			c.DATA_TYPE,
+			c.NULLABLE,
+			c.DATA_DEFAULT,
+			CASE WHEN c.CHAR_LENGTH > 0 THEN c.CHAR_LENGTH END,
+			c.DATA_PRECISION,
+			c.DATA_SCALE
 		FROM ALL_TAB_COLUMNS c
 		LEFT JOIN ALL_COL_COMMENTS cc
 			ON c.OWNER = cc.OWNER AND c.TABLE_NAME = cc.TABLE_NAME AND c.COLUMN_NAME = cc.COLUMN_NAME
@@ -103,22 +110,81 @@ func (p *oracleProvider) GetTableColumns(ctx context.Context, db *sql.DB, config
 	var columns []ColumnMetadata
 	for rows.Next() {
 		var column ColumnMetadata
-		if err := rows.Scan(
+		var details columnDetails
+		if err := rows.Scan(append([]interface{}{
 			&column.ColumnName,
 			&column.ColumnComment,
 			&column.ColumnOrdinal,
 			&column.ColumnType,
-		); err != nil {
+		}, details.scanArgs()...)...); err != nil {
 			return nil, err
 		}
+		details.apply(&column)
 		columns = append(columns, column)
 	}
 
 	return columns, nil
 }
 
+func (p *oracleProvider) GetTableConstraints(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableConstraint, error) {
+	owner, table := splitSchemaAndTable(tableName, strings.ToUpper(config.Username))
+	query := `
+		SELECT
+			c.CONSTRAINT_NAME,
+			c.CONSTRAINT_TYPE,
+			cc.COLUMN_NAME,
+			CASE WHEN rc.TABLE_NAME IS NOT NULL THEN rc.OWNER || '.' || rc.TABLE_NAME END,
+			rcc.COLUMN_NAME
+		FROM ALL_CONSTRAINTS c
+		JOIN ALL_CONS_COLUMNS cc
+			ON cc.OWNER = c.OWNER AND cc.CONSTRAINT_NAME = c.CONSTRAINT_NAME AND cc.TABLE_NAME = c.TABLE_NAME
+		LEFT JOIN ALL_CONSTRAINTS rc
+			ON rc.OWNER = c.R_OWNER AND rc.CONSTRAINT_NAME = c.R_CONSTRAINT_NAME
+		LEFT JOIN ALL_CONS_COLUMNS rcc
+			ON rcc.OWNER = rc.OWNER AND rcc.CONSTRAINT_NAME = rc.CONSTRAINT_NAME AND rcc.POSITION = cc.POSITION
+		WHERE c.OWNER = :owner AND c.TABLE_NAME = :table AND c.CONSTRAINT_TYPE IN ('P', 'U', 'R')
+		ORDER BY c.CONSTRAINT_NAME, cc.POSITION
+	`
+
+	rows, err := db.QueryContext(ctx, query, owner, strings.ToUpper(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableConstraints(rows)
+}
+
+func (p *oracleProvider) GetTableIndexes(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) ([]TableIndex, error) {
+	owner, table := splitSchemaAndTable(tableName, strings.ToUpper(config.Username))
+	query := `
+		SELECT
+			i.INDEX_NAME,
+			ic.COLUMN_NAME,
+			CASE WHEN i.UNIQUENESS = 'UNIQUE' THEN 1 ELSE 0 END,
+			i.INDEX_TYPE,
+			CASE WHEN EXISTS (
+				SELECT 1 FROM ALL_CONSTRAINTS c
+				WHERE c.OWNER = i.TABLE_OWNER AND c.TABLE_NAME = i.TABLE_NAME
+					AND c.CONSTRAINT_TYPE = 'P' AND c.INDEX_NAME = i.INDEX_NAME
+			) THEN 1 ELSE 0 END
+		FROM ALL_INDEXES i
+		JOIN ALL_IND_COLUMNS ic ON ic.INDEX_OWNER = i.OWNER AND ic.INDEX_NAME = i.INDEX_NAME
+		WHERE i.TABLE_OWNER = :owner AND i.TABLE_NAME = :table
+		ORDER BY i.INDEX_NAME, ic.COLUMN_POSITION
+	`
+
+	rows, err := db.QueryContext(ctx, query, owner, strings.ToUpper(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableIndexes(rows)
+}
+
 func (p *oracleProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", p.QuoteTableName(config, tableName))
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", sampledTableRef(ctx, p, config, tableName))
 	var rowCount int64
 	if err := db.QueryRowContext(ctx, query).Scan(&rowCount); err != nil {
 		return 0, err
@@ -126,44 +192,109 @@ func (p *oracleProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config
 	return rowCount, nil
 }
 
-func (p *oracleProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
-	columns, err := p.GetTableColumns(ctx, db, config, tableName)
+// EstimateRowCount 读取最近一次收集统计信息时的 NUM_ROWS，未收集过统计信息的表为 NULL
+func (p *oracleProvider) EstimateRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	owner, table := splitSchemaAndTable(tableName, strings.ToUpper(config.Username))
+	var rowCount sql.NullInt64
+	err := db.QueryRowContext(ctx, `
+		SELECT NUM_ROWS
+		FROM ALL_TABLES
+		WHERE OWNER = :owner AND TABLE_NAME = :table
+	`, owner, strings.ToUpper(table)).Scan(&rowCount)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	if len(columns) == 0 {
-		return map[string]float64{}, nil
+	if !rowCount.Valid {
+		return -1, nil
 	}
+	return rowCount.Int64, nil
+}
 
-	selectParts := make([]string, 0, len(columns))
-	for _, column := range columns {
-		col := p.QuoteIdentifier(column.ColumnName)
-		selectParts = append(selectParts, fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1 ELSE 0 END) AS %s", col, col))
-	}
+func (p *oracleProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
+	return executeNonNullRate(ctx, db, config, p, tableName)
+}
+
+func (p *oracleProvider) ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error) {
+	return executeDistinctCount(ctx, db, config, p, tableName)
+}
 
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), p.QuoteTableName(config, tableName))
-	row := db.QueryRowContext(ctx, query)
+func (p *oracleProvider) ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error) {
+	return executeNumericStats(ctx, db, config, p, tableName)
+}
 
-	values := make([]sql.NullFloat64, len(columns))
-	scanArgs := make([]interface{}, len(columns))
-	for i := range values {
-		scanArgs[i] = &values[i]
+func (p *oracleProvider) ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	temporal := isTemporalColumnType(column.ColumnType)
+	valueExpr := col
+	if temporal {
+		valueExpr = fmt.Sprintf("((CAST(%s AS DATE) - DATE '1970-01-01') * 86400)", col)
 	}
 
-	if err := row.Scan(scanArgs...); err != nil {
-		return nil, err
+	dialect := histogramDialect{
+		tableRef:  sampledTableRef(ctx, p, config, tableName),
+		column:    col,
+		valueExpr: valueExpr,
+		// WIDTH_BUCKET 对等于上界的值返回 bins+1，需要收敛到最后一个桶
+		bucketExpr: func(lower, upper float64, bins int) string {
+			return fmt.Sprintf("LEAST(WIDTH_BUCKET(%s, %s, %s, %d), %d)",
+				valueExpr, sqlFloat(lower), sqlFloat(upper), bins, bins)
+		},
+		bucketBase: 1,
 	}
+	return executeHistogram(ctx, db, dialect, bins, mode, temporal)
+}
+
+func (p *oracleProvider) ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error) {
+	return executeStringStats(ctx, db, config, p, tableName)
+}
 
-	result := make(map[string]float64, len(columns))
-	for i, column := range columns {
-		val := 0.0
-		if values[i].Valid {
-			val = clampRatio(values[i].Float64)
+func (p *oracleProvider) AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	switch metric {
+	case AggregateRowCount:
+		return []string{"COUNT(*)"}, true
+	case AggregateNonNullRate:
+		return []string{fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1 ELSE 0 END)", col)}, true
+	case AggregateDistinctCount:
+		return []string{fmt.Sprintf("COUNT(DISTINCT %s)", col)}, true
+	case AggregateNumericMoments:
+		return []string{
+			fmt.Sprintf("MIN(%s)", col),
+			fmt.Sprintf("MAX(%s)", col),
+			fmt.Sprintf("AVG(%s)", col),
+			fmt.Sprintf("STDDEV_SAMP(%s)", col),
+			fmt.Sprintf("COUNT(%s)", col),
+		}, true
+	case AggregateNumericStats:
+		// 聚合与分位数在同一次扫描中完成
+		expressions := []string{
+			fmt.Sprintf("MIN(%s)", col),
+			fmt.Sprintf("MAX(%s)", col),
+			fmt.Sprintf("AVG(%s)", col),
+			fmt.Sprintf("STDDEV_SAMP(%s)", col),
+		}
+		for _, percentile := range numericPercentiles {
+			expressions = append(expressions, fmt.Sprintf("PERCENTILE_CONT(%g) WITHIN GROUP (ORDER BY %s)", percentile, col))
 		}
-		result[column.ColumnName] = val
+		return expressions, true
+	case AggregateStringStats:
+		trailing := fmt.Sprintf("SUM(CASE WHEN REGEXP_LIKE(%s, '\\s$') THEN 1 ELSE 0 END)", col)
+		if isFixedCharColumnType(column.ColumnType) {
+			trailing = "0"
+		}
+		return []string{
+			fmt.Sprintf("COUNT(%s)", col),
+			fmt.Sprintf("MIN(LENGTH(%s))", col),
+			fmt.Sprintf("AVG(LENGTH(%s))", col),
+			fmt.Sprintf("MAX(LENGTH(%s))", col),
+			// Oracle 中空串即 NULL
+			"0",
+			fmt.Sprintf("SUM(CASE WHEN REGEXP_LIKE(%s, '^\\s') THEN 1 ELSE 0 END)", col),
+			trailing,
+			fmt.Sprintf("SUM(CASE WHEN REGEXP_LIKE(%s, '^\\s+$') THEN 1 ELSE 0 END)", col),
+		}, true
 	}
-
-	return result, nil
+	return nil, false
 }
 
 func (p *oracleProvider) QuoteIdentifier(name string) string {
@@ -175,3 +306,34 @@ func (p *oracleProvider) QuoteTableName(config *DatabaseConfig, tableName string
 	owner, table := splitSchemaAndTable(tableName, strings.ToUpper(config.Username))
 	return fmt.Sprintf("%s.%s", p.QuoteIdentifier(owner), p.QuoteIdentifier(table))
 }
+
+// TableSource 使用行级 SAMPLE，SEED 保证各查询读取相同的样本
+func (p *oracleProvider) TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string {
+	return fmt.Sprintf("%s SAMPLE (%g) SEED (%d)", p.QuoteTableName(config, tableName), sampling.Percent, sampling.seed())
+}
+
+func (p *oracleProvider) TimestampLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP '%s'", t.Format("2006-01-02 15:04:05.999999999"))
+}
+
+// oracleLOBColumnTypes LOB、LONG 类型不能出现在 DISTINCT / GROUP BY 中（ORA-00932、ORA-00997）
+var oracleLOBColumnTypes = map[string]bool{
+	"blob":     true,
+	"clob":     true,
+	"nclob":    true,
+	"bfile":    true,
+	"long":     true,
+	"long raw": true,
+	"xmltype":  true,
+	"json":     true,
+}
+
+// Capabilities Oracle 的 SELECT 列表最多 1000 个表达式（ORA-01792）
+func (p *oracleProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{MaxSelectExpressions: 1000, LOBColumnTypes: oracleLOBColumnTypes}
+}
+
+// LimitQuery 使用 FETCH FIRST 限制行数（Oracle 12c+）
+func (p *oracleProvider) LimitQuery(query string, limit int) string {
+	return fmt.Sprintf("%s FETCH FIRST %d ROWS ONLY", query, limit)
+}
@@ -0,0 +1,578 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// parquetProvider 将目录中的 Parquet 文件或数据集目录作为表
+// 表、列和行数从文件尾部的元数据读取，连接时不导入；规则需要扫描数据时再将表导入缓存库
+// 全表分析的行数和非空率由行组统计信息得出，统计信息不完整或采样、分区时扫描缓存库
+type parquetProvider struct {
+	fileProvider
+}
+
+// Configure 只检查路径并创建缓存目录，表在 PrepareTable 中按需导入
+func (p *parquetProvider) Configure(_ *sql.DB, config *DatabaseConfig) error {
+	if _, err := p.configuredFiles(config); err != nil {
+		return err
+	}
+	cachePath, err := p.cachePath(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s cache directory: %w", p.format.name(), err)
+	}
+	return nil
+}
+
+// PrepareTable 将表导入缓存库；外键孤儿检查引用的父表也可能是文件，此时导入全部表，只有该表本身导入失败时返回错误
+func (p *parquetProvider) PrepareTable(config *DatabaseConfig, tableName string, rules []TaskRuleConfig) error {
+	files, err := p.configuredFiles(config)
+	if err != nil {
+		return err
+	}
+	tables := map[string]bool{tableName: true}
+	for _, rule := range rules {
+		if rule.Name == "fk_orphans" {
+			tables = nil
+			break
+		}
+	}
+	failed, err := p.stageFiles(config, files, tables)
+	if err != nil {
+		return err
+	}
+	return failed[tableName]
+}
+
+func (p *parquetProvider) GetTables(_ context.Context, _ *sql.DB, config *DatabaseConfig) ([]string, error) {
+	files, err := p.configuredFiles(config)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(files))
+	for i, file := range files {
+		tables[i] = file.table
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetTableMetadata 行数为各行组行数之和，数据大小为文件大小，注释为文件路径
+func (p *parquetProvider) GetTableMetadata(_ context.Context, _ *sql.DB, config *DatabaseConfig, tableName string) (map[string]interface{}, error) {
+	file, table, err := p.readTable(config, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"row_count":           table.rowCount,
+		"row_count_estimated": false,
+		"column_count":        len(table.columns),
+		"data_size":           file.size,
+		"comment":             file.path,
+	}, nil
+}
+
+// GetTableColumns 列与导入缓存库后的列相同
+func (p *parquetProvider) GetTableColumns(_ context.Context, _ *sql.DB, config *DatabaseConfig, tableName string) ([]ColumnMetadata, error) {
+	_, table, err := p.readTable(config, tableName)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]ColumnMetadata, len(table.columns))
+	for i, column := range table.columns {
+		columns[i] = ColumnMetadata{
+			ColumnName:    column.tableName,
+			ColumnOrdinal: i + 1,
+			ColumnType:    column.declaredType,
+			Nullable:      !column.notNull(),
+		}
+	}
+	return columns, nil
+}
+
+// EstimateRowCount 元数据中的行数是精确值
+func (p *parquetProvider) EstimateRowCount(_ context.Context, _ *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	_, table, err := p.readTable(config, tableName)
+	if err != nil {
+		return 0, err
+	}
+	return table.rowCount, nil
+}
+
+// TableStatistics 汇总各行组统计信息中的空值数，任一行组缺少统计信息的列不包含在内
+// 浮点数的 NaN 在统计信息中不计为空值，而导入缓存库后按 NULL 写入，因此采样与全表的非空率可能不同
+func (p *parquetProvider) TableStatistics(_ context.Context, config *DatabaseConfig, tableName string) (*TableStatistics, error) {
+	_, table, err := p.readTable(config, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return &TableStatistics{RowCount: table.rowCount, NullCounts: table.nullCounts}, nil
+}
+
+// readTable 读取表对应的各文件的元数据
+func (p *parquetProvider) readTable(config *DatabaseConfig, tableName string) (stagedFile, *parquetTable, error) {
+	file, err := p.findFile(config, tableName)
+	if err != nil {
+		return stagedFile{}, nil, err
+	}
+	table, err := readParquetTable(file)
+	if err != nil {
+		return stagedFile{}, nil, err
+	}
+	return file, table, nil
+}
+
+// parquetFormat 目录中的 Parquet 文件，子目录中的分片文件（如 Spark、Hive 的输出）合并为一张表
+// 列及类型来自文件的 schema；嵌套结构体的字段展开为以点连接的列，列表、映射等重复字段跳过
+type parquetFormat struct{}
+
+func (f *parquetFormat) name() string {
+	return "parquet"
+}
+
+// version 类型映射或导入方式变化后递增
+func (f *parquetFormat) version() int {
+	return 1
+}
+
+func (f *parquetFormat) extensions() []string {
+	return []string{".parquet", ".parq"}
+}
+
+func (f *parquetFormat) datasets() bool {
+	return true
+}
+
+// parquetTableColumn 导入缓存库的列
+type parquetTableColumn struct {
+	parquetColumn
+	tableName    string // 缓存库中的列名
+	declaredType string
+	convert      func(value interface{}) interface{}
+}
+
+// notNull 必填列声明为 NOT NULL；浮点数的非有限值按 NULL 写入，不声明
+func (c *parquetTableColumn) notNull() bool {
+	return c.maxDefinition == 0 && !parquetFloating(&c.element)
+}
+
+// parquetTable 表对应的各文件的元数据
+type parquetTable struct {
+	columns    []parquetTableColumn
+	skipped    []string // 跳过的重复字段
+	parts      []*parquetFileMetaData
+	rowCount   int64
+	nullCounts map[string]int64 // 统计信息完整的列的空值数
+}
+
+// readParquetTable 读取各文件尾部的元数据，数据集的各文件须有相同的列
+func readParquetTable(file stagedFile) (*parquetTable, error) {
+	table := &parquetTable{nullCounts: make(map[string]int64)}
+	incomplete := make(map[string]bool)
+	for _, part := range file.parts {
+		err := func() error {
+			source, err := os.Open(part)
+			if err != nil {
+				return err
+			}
+			defer source.Close()
+			info, err := source.Stat()
+			if err != nil {
+				return err
+			}
+			metadata, err := readParquetFooter(source, info.Size())
+			if err != nil {
+				return err
+			}
+			columns, skipped, err := parquetTableColumns(metadata)
+			if err != nil {
+				return err
+			}
+			if table.columns == nil {
+				table.columns, table.skipped = columns, skipped
+			} else if !sameParquetLayout(table.columns, columns) {
+				return fmt.Errorf("columns differ from %s", filepath.Base(file.parts[0]))
+			}
+			table.parts = append(table.parts, metadata)
+
+			for _, rowGroup := range metadata.rowGroups {
+				table.rowCount += rowGroup.numRows
+				for _, column := range columns {
+					if column.index >= len(rowGroup.columns) {
+						return fmt.Errorf("row group is missing column %s", column.name)
+					}
+					// 必填列没有空值，无需统计信息
+					statistics := rowGroup.columns[column.index].statistics
+					switch {
+					case statistics != nil && statistics.hasNullCount:
+						table.nullCounts[column.tableName] += statistics.nullCount
+					case column.maxDefinition > 0:
+						incomplete[column.tableName] = true
+					}
+				}
+			}
+			return nil
+		}()
+		if err != nil {
+			if len(file.parts) > 1 {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(part), err)
+			}
+			return nil, err
+		}
+	}
+
+	for _, column := range table.columns {
+		if incomplete[column.tableName] {
+			delete(table.nullCounts, column.tableName)
+		} else if _, ok := table.nullCounts[column.tableName]; !ok {
+			table.nullCounts[column.tableName] = 0
+		}
+	}
+	return table, nil
+}
+
+// importFile 按行组逐页读取各列并写入缓存库
+func (f *parquetFormat) importFile(tx *sql.Tx, file stagedFile) (int64, error) {
+	table, err := readParquetTable(file)
+	if err != nil {
+		return 0, err
+	}
+	if len(table.skipped) > 0 {
+		GetLogger().LogInfo("STAGE", fmt.Sprintf("跳过重复字段 - %s: %s", file.path, strings.Join(table.skipped, ", ")))
+	}
+
+	definitions := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		definitions[i] = fmt.Sprintf("%s %s", quoteSQLiteIdentifier(column.tableName), column.declaredType)
+		if column.notNull() {
+			definitions[i] += " NOT NULL"
+		}
+	}
+	stmt, err := createStagedTable(tx, file.table, definitions)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for i, part := range file.parts {
+		err := func() error {
+			source, err := os.Open(part)
+			if err != nil {
+				return err
+			}
+			defer source.Close()
+			return readParquetRows(source, table.parts[i], table.columns, func(values []interface{}) error {
+				_, err := stmt.Exec(values...)
+				return err
+			})
+		}()
+		if err != nil {
+			if len(file.parts) > 1 {
+				return 0, fmt.Errorf("%s: %w", filepath.Base(part), err)
+			}
+			return 0, err
+		}
+	}
+	return table.rowCount, nil
+}
+
+// parquetTableColumns 返回可导入的列及跳过的列表、映射等重复字段
+func parquetTableColumns(metadata *parquetFileMetaData) ([]parquetTableColumn, []string, error) {
+	leaves, err := parquetLeafColumns(metadata.schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var columns []parquetTableColumn
+	var skipped []string
+	for _, leaf := range leaves {
+		if leaf.repeated {
+			skipped = append(skipped, leaf.name)
+			continue
+		}
+		declaredType, convert := parquetColumnType(&leaf.element)
+		columns = append(columns, parquetTableColumn{parquetColumn: leaf, declaredType: declaredType, convert: convert})
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("parquet file has no supported columns")
+	}
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	for i, name := range uniqueColumnNames(names) {
+		columns[i].tableName = name
+	}
+	return columns, skipped, nil
+}
+
+// sameParquetLayout 判断数据集中各文件的列名和类型是否一致
+func sameParquetLayout(a, b []parquetTableColumn) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].tableName != b[i].tableName || a[i].declaredType != b[i].declaredType || (a[i].maxDefinition == 0) != (b[i].maxDefinition == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// readParquetRows 逐个行组读取各列，按行回调转换后的值；values 在回调之间复用
+func readParquetRows(source *os.File, metadata *parquetFileMetaData, columns []parquetTableColumn, handle func(values []interface{}) error) error {
+	readers := make([]*parquetColumnReader, len(columns))
+	for i := range columns {
+		readers[i] = newParquetColumnReader(source, &columns[i].parquetColumn, columns[i].convert)
+	}
+
+	values := make([]interface{}, len(columns))
+	for _, rowGroup := range metadata.rowGroups {
+		for i, column := range columns {
+			if err := readers[i].openChunk(&rowGroup.columns[column.index]); err != nil {
+				return err
+			}
+		}
+
+		for row := int64(0); row < rowGroup.numRows; row++ {
+			for i, reader := range readers {
+				value, err := reader.next()
+				if err != nil {
+					return err
+				}
+				values[i] = value
+			}
+			if err := handle(values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parquetColumnType 返回列在缓存库中声明的类型，及物理值到缓存库值的转换
+// 日期时间按 SQLite 的标准文本格式（UTC）写入，小数按浮点数写入，无法识别的字节数组按 BLOB 写入
+func parquetColumnType(element *parquetSchemaElement) (string, func(value interface{}) interface{}) {
+	logical := element.logicalType
+	if logical == nil {
+		logical = &parquetLogicalType{}
+	}
+	converted := int32(-1)
+	if element.hasConverted {
+		converted = element.convertedType
+	}
+
+	switch {
+	case element.physicalType == parquetInt96:
+		// 旧版 Impala/Spark 的时间戳：8 字节当日纳秒数和 4 字节儒略日
+		return "TIMESTAMP", func(value interface{}) interface{} {
+			b := value.([]byte)
+			nanos := int64(binary.LittleEndian.Uint64(b))
+			days := int64(binary.LittleEndian.Uint32(b[8:])) - 2440588
+			return time.Unix(days*86400, nanos).UTC().Format("2006-01-02 15:04:05.999999999")
+		}
+	case logical.kind == logicalString || logical.kind == logicalEnum || logical.kind == logicalJSON ||
+		converted == convertedUTF8 || converted == convertedEnum || converted == convertedJSON:
+		if element.physicalType == parquetByteArray || element.physicalType == parquetFixedLenByteArray {
+			return "TEXT", func(value interface{}) interface{} {
+				return string(value.([]byte))
+			}
+		}
+	case logical.kind == logicalUUID:
+		return "TEXT", func(value interface{}) interface{} {
+			b := value.([]byte)
+			if len(b) != 16 {
+				return b
+			}
+			return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+		}
+	case logical.kind == logicalDecimal || converted == convertedDecimal:
+		scale, precision := element.scale, element.precision
+		if logical.kind == logicalDecimal {
+			scale, precision = logical.scale, logical.precision
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale), func(value interface{}) interface{} {
+			return parquetDecimal(value, scale)
+		}
+	case logical.kind == logicalDate || converted == convertedDate:
+		return "DATE", func(value interface{}) interface{} {
+			return time.Unix(int64(value.(int32))*86400, 0).UTC().Format("2006-01-02")
+		}
+	case logical.kind == logicalTime || converted == convertedTimeMillis || converted == convertedTimeMicros:
+		unit := parquetTimeUnit(logical, converted, convertedTimeMillis)
+		return "TIME", func(value interface{}) interface{} {
+			return time.Time{}.Add(parquetDuration(value, unit)).Format("15:04:05.999999999")
+		}
+	case logical.kind == logicalTimestamp || converted == convertedTimestampMillis || converted == convertedTimestampMicros:
+		unit := parquetTimeUnit(logical, converted, convertedTimestampMillis)
+		return "TIMESTAMP", func(value interface{}) interface{} {
+			return time.Unix(0, 0).Add(parquetDuration(value, unit)).UTC().Format("2006-01-02 15:04:05.999999999")
+		}
+	case logical.kind == logicalFloat16 && element.physicalType == parquetFixedLenByteArray:
+		return "FLOAT", func(value interface{}) interface{} {
+			return parquetFloat16(value.([]byte))
+		}
+	case logical.kind == logicalInteger || (converted >= convertedUint8 && converted <= convertedInt64):
+		bitWidth, signed := int(logical.bitWidth), logical.signed
+		if logical.kind != logicalInteger {
+			bitWidth = []int{8, 16, 32, 64}[(converted-convertedUint8)%4]
+			signed = converted >= convertedInt8
+		}
+		declaredType := map[int]string{8: "TINYINT", 16: "SMALLINT", 32: "INTEGER", 64: "BIGINT"}[bitWidth]
+		if !signed && bitWidth == 32 {
+			declaredType = "BIGINT"
+		}
+		if declaredType == "" {
+			declaredType = "BIGINT"
+		}
+		return declaredType, func(value interface{}) interface{} {
+			return parquetInteger(value, signed)
+		}
+	}
+
+	switch element.physicalType {
+	case parquetBoolean:
+		return "BOOLEAN", func(value interface{}) interface{} {
+			if value.(bool) {
+				return int64(1)
+			}
+			return int64(0)
+		}
+	case parquetInt32:
+		return "INTEGER", func(value interface{}) interface{} {
+			return int64(value.(int32))
+		}
+	case parquetInt64:
+		return "BIGINT", func(value interface{}) interface{} {
+			return value
+		}
+	case parquetFloat:
+		return "FLOAT", func(value interface{}) interface{} {
+			return parquetFinite(float64(value.(float32)))
+		}
+	case parquetDouble:
+		return "DOUBLE", func(value interface{}) interface{} {
+			return parquetFinite(value.(float64))
+		}
+	}
+	return "BLOB", func(value interface{}) interface{} {
+		return value
+	}
+}
+
+// parquetTimeUnit 时间类型的单位，旧版逻辑类型只有毫秒和微秒
+func parquetTimeUnit(logical *parquetLogicalType, converted, millis int32) int {
+	if logical.kind == logicalTime || logical.kind == logicalTimestamp {
+		return logical.unit
+	}
+	if converted == millis {
+		return parquetMillis
+	}
+	return parquetMicros
+}
+
+// parquetDuration 按单位将整数值转换为时长
+func parquetDuration(value interface{}, unit int) time.Duration {
+	var v int64
+	switch n := value.(type) {
+	case int32:
+		v = int64(n)
+	case int64:
+		v = n
+	}
+	switch unit {
+	case parquetMillis:
+		return time.Duration(v) * time.Millisecond
+	case parquetMicros:
+		return time.Duration(v) * time.Microsecond
+	}
+	return time.Duration(v)
+}
+
+// parquetInteger 整数值，无符号 64 位整数超出 int64 范围时按浮点数写入
+func parquetInteger(value interface{}, signed bool) interface{} {
+	switch n := value.(type) {
+	case int32:
+		if !signed {
+			return int64(uint32(n))
+		}
+		return int64(n)
+	case int64:
+		if !signed && n < 0 {
+			return float64(uint64(n))
+		}
+		return n
+	}
+	return value
+}
+
+// parquetDecimal 将小数的非标度整数（INT32、INT64 或大端补码字节数组）转换为浮点数
+func parquetDecimal(value interface{}, scale int32) interface{} {
+	unscaled := new(big.Int)
+	switch n := value.(type) {
+	case int32:
+		unscaled.SetInt64(int64(n))
+	case int64:
+		unscaled.SetInt64(n)
+	case []byte:
+		unscaled.SetBytes(n)
+		if len(n) > 0 && n[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(n)*8)))
+		}
+	default:
+		return nil
+	}
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	result, _ := new(big.Rat).SetFrac(unscaled, denominator).Float64()
+	return result
+}
+
+// parquetFloating 判断列是否按浮点数写入
+func parquetFloating(element *parquetSchemaElement) bool {
+	if element.logicalType != nil && element.logicalType.kind == logicalFloat16 {
+		return true
+	}
+	return element.physicalType == parquetFloat || element.physicalType == parquetDouble
+}
+
+// parquetFinite 非有限值（NaN、无穷）按 NULL 写入：SQLite 将 NaN 存为 NULL，无穷会使数值统计和直方图失去意义
+func parquetFinite(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return value
+}
+
+// parquetFloat16 将小端 IEEE 754 半精度浮点数转换为 float64
+func parquetFloat16(b []byte) interface{} {
+	if len(b) != 2 {
+		return nil
+	}
+	bits := binary.LittleEndian.Uint16(b)
+	sign := 1.0
+	if bits&0x8000 != 0 {
+		sign = -1
+	}
+	exponent := int(bits>>10) & 0x1f
+	fraction := float64(bits & 0x3ff)
+	switch exponent {
+	case 0:
+		return sign * math.Ldexp(fraction, -24)
+	case 0x1f:
+		return nil
+	}
+	return sign * math.Ldexp(1+fraction/1024, exponent-15)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type postgresProvider struct {
@@ -63,11 +64,12 @@ func (p *postgresProvider) GetTableMetadata(ctx context.Context, db *sql.DB, _ *
 	schema, table := splitSchemaAndTable(tableName, "public")
 	metadata := make(map[string]interface{})
 
-	rowCount, err := p.ExecuteRowCount(ctx, db, nil, tableName)
+	rowCount, estimated, err := estimateRowCount(ctx, db, p, nil, tableName)
 	if err != nil {
 		return nil, err
 	}
 	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = estimated
 
 	var columnCount int
 	err = db.QueryRowContext(ctx, `
@@ -92,7 +94,12 @@ func (p *postgresProvider) GetTableColumns(ctx context.Context, db *sql.DB, _ *D
 			column_name,
 			COALESCE(col_description((quote_ident(table_schema)||'.'||quote_ident(table_name))::regclass::oid, ordinal_position), '') AS column_comment,
 			ordinal_position,
-			data_type
+			data_type,
+			is_nullable,
+			column_default,
+			character_maximum_length,
+			numeric_precision,
+			numeric_scale
 		FROM information_schema.columns
 		WHERE table_schema = $1 AND table_name = $2
 		ORDER BY ordinal_position
@@ -107,22 +114,91 @@ func (p *postgresProvider) GetTableColumns(ctx context.Context, db *sql.DB, _ *D
 	var columns []ColumnMetadata
 	for rows.Next() {
 		var column ColumnMetadata
-		if err := rows.Scan(
+		var details columnDetails
+		if err := rows.Scan(append([]interface{}{
 			&column.ColumnName,
 			&column.ColumnComment,
 			&column.ColumnOrdinal,
 			&column.ColumnType,
-		); err != nil {
+		}, details.scanArgs()...)...); err != nil {
 			return nil, err
 		}
+		details.apply(&column)
 		columns = append(columns, column)
 	}
 
 	return columns, nil
 }
 
-func (p *postgresProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", p.QuoteTableName(nil, tableName))
+func (p *postgresProvider) GetTableConstraints(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableConstraint, error) {
+	schema, table := splitSchemaAndTable(tableName, "public")
+	query := `
+		SELECT
+			con.conname,
+			con.contype::text,
+			a.attname,
+			CASE WHEN ft.oid IS NULL THEN NULL ELSE fn.nspname || '.' || ft.relname END,
+			fa.attname
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		LEFT JOIN pg_class ft ON ft.oid = con.confrelid
+		LEFT JOIN pg_namespace fn ON fn.oid = ft.relnamespace
+		LEFT JOIN pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = con.confkey[k.ord]
+		WHERE n.nspname = $1 AND t.relname = $2 AND con.contype IN ('p', 'u', 'f')
+		ORDER BY con.conname, k.ord
+	`
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableConstraints(rows)
+}
+
+func (p *postgresProvider) GetTableIndexes(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableIndex, error) {
+	schema, table := splitSchemaAndTable(tableName, "public")
+	query := `
+		SELECT
+			i.indexname,
+			i.indexdef,
+			EXISTS (
+				SELECT 1
+				FROM pg_constraint c
+				JOIN pg_namespace n ON n.oid = c.connamespace
+				WHERE c.contype = 'p' AND n.nspname = i.schemaname AND c.conname = i.indexname
+			)
+		FROM pg_indexes i
+		WHERE i.schemaname = $1 AND i.tablename = $2
+		ORDER BY i.indexname
+	`
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []TableIndex
+	for rows.Next() {
+		var index TableIndex
+		var definition string
+		if err := rows.Scan(&index.IndexName, &definition, &index.Primary); err != nil {
+			return nil, err
+		}
+		index.IndexType, index.Columns, index.Unique, index.Partial = parsePostgresIndexDef(definition)
+		indexes = append(indexes, index)
+	}
+
+	return indexes, rows.Err()
+}
+
+func (p *postgresProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", sampledTableRef(ctx, p, config, tableName))
 	var rowCount int64
 	if err := db.QueryRowContext(ctx, query).Scan(&rowCount); err != nil {
 		return 0, err
@@ -130,44 +206,121 @@ func (p *postgresProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, _ *D
 	return rowCount, nil
 }
 
-func (p *postgresProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
-	columns, err := p.GetTableColumns(ctx, db, config, tableName)
+// EstimateRowCount 读取 VACUUM/ANALYZE 维护的 reltuples，从未统计过的表为 -1（PostgreSQL 14 之前为 0）
+// 为 0 时无法区分空表与未统计的表（relpages 同样只在统计时更新，未统计的表也可能为 0），一律视为没有统计信息，空表回退到 COUNT(*) 的代价很小
+// 分区表的父表没有数据，汇总各分区的 reltuples，任一分区没有统计信息时整体视为没有
+func (p *postgresProvider) EstimateRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
+	schema, table := splitSchemaAndTable(tableName, "public")
+	var rowCount sql.NullFloat64
+	err := db.QueryRowContext(ctx, `
+		SELECT CASE
+			WHEN c.relkind = 'p' THEN (
+				SELECT CASE WHEN bool_and(child.reltuples > 0) THEN SUM(child.reltuples) END
+				FROM pg_inherits i
+				JOIN pg_class child ON child.oid = i.inhrelid
+				WHERE i.inhparent = c.oid
+			)
+			ELSE c.reltuples
+		END
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2
+	`, schema, table).Scan(&rowCount)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	if len(columns) == 0 {
-		return map[string]float64{}, nil
+	if !rowCount.Valid || rowCount.Float64 <= 0 {
+		return -1, nil
 	}
+	return int64(rowCount.Float64), nil
+}
 
-	selectParts := make([]string, 0, len(columns))
-	for _, column := range columns {
-		col := p.QuoteIdentifier(column.ColumnName)
-		selectParts = append(selectParts, fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1.0 ELSE 0.0 END) AS %s", col, col))
-	}
+func (p *postgresProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
+	return executeNonNullRate(ctx, db, config, p, tableName)
+}
+
+func (p *postgresProvider) ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error) {
+	return executeDistinctCount(ctx, db, config, p, tableName)
+}
 
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), p.QuoteTableName(config, tableName))
-	row := db.QueryRowContext(ctx, query)
+func (p *postgresProvider) ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error) {
+	return executeNumericStats(ctx, db, config, p, tableName)
+}
 
-	values := make([]sql.NullFloat64, len(columns))
-	scanArgs := make([]interface{}, len(columns))
-	for i := range values {
-		scanArgs[i] = &values[i]
+func (p *postgresProvider) ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	temporal := isTemporalColumnType(column.ColumnType)
+	valueExpr := fmt.Sprintf("%s::double precision", col)
+	if temporal {
+		valueExpr = fmt.Sprintf("EXTRACT(EPOCH FROM %s)::double precision", col)
 	}
 
-	if err := row.Scan(scanArgs...); err != nil {
-		return nil, err
+	dialect := histogramDialect{
+		tableRef:  sampledTableRef(ctx, p, config, tableName),
+		column:    col,
+		valueExpr: valueExpr,
+		// WIDTH_BUCKET 对等于上界的值返回 bins+1，需要收敛到最后一个桶
+		bucketExpr: func(lower, upper float64, bins int) string {
+			return fmt.Sprintf("LEAST(WIDTH_BUCKET(%s, %s, %s, %d), %d)",
+				valueExpr, sqlFloat(lower), sqlFloat(upper), bins, bins)
+		},
+		bucketBase: 1,
 	}
+	return executeHistogram(ctx, db, dialect, bins, mode, temporal)
+}
 
-	result := make(map[string]float64, len(columns))
-	for i, column := range columns {
-		val := 0.0
-		if values[i].Valid {
-			val = clampRatio(values[i].Float64)
+func (p *postgresProvider) ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error) {
+	return executeStringStats(ctx, db, config, p, tableName)
+}
+
+func (p *postgresProvider) AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	switch metric {
+	case AggregateRowCount:
+		return []string{"COUNT(*)"}, true
+	case AggregateNonNullRate:
+		return []string{fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1.0 ELSE 0.0 END)", col)}, true
+	case AggregateDistinctCount:
+		return []string{fmt.Sprintf("COUNT(DISTINCT %s)", col)}, true
+	case AggregateNumericMoments:
+		value := fmt.Sprintf("%s::double precision", col)
+		return []string{
+			fmt.Sprintf("MIN(%s)", value),
+			fmt.Sprintf("MAX(%s)", value),
+			fmt.Sprintf("AVG(%s)", value),
+			fmt.Sprintf("STDDEV_SAMP(%s)", value),
+			fmt.Sprintf("COUNT(%s)", col),
+		}, true
+	case AggregateNumericStats:
+		// 聚合与分位数在同一次扫描中完成
+		value := fmt.Sprintf("%s::double precision", col)
+		expressions := []string{
+			fmt.Sprintf("MIN(%s)", value),
+			fmt.Sprintf("MAX(%s)", value),
+			fmt.Sprintf("AVG(%s)", value),
+			fmt.Sprintf("STDDEV_SAMP(%s)", value),
+		}
+		for _, percentile := range numericPercentiles {
+			expressions = append(expressions, fmt.Sprintf("PERCENTILE_CONT(%g) WITHIN GROUP (ORDER BY %s)", percentile, value))
 		}
-		result[column.ColumnName] = val
+		return expressions, true
+	case AggregateStringStats:
+		trailing := fmt.Sprintf("SUM(CASE WHEN %s ~ '\\s$' THEN 1 ELSE 0 END)", col)
+		if isFixedCharColumnType(column.ColumnType) {
+			trailing = "0"
+		}
+		return []string{
+			fmt.Sprintf("COUNT(%s)", col),
+			fmt.Sprintf("MIN(LENGTH(%s))", col),
+			fmt.Sprintf("AVG(LENGTH(%s))", col),
+			fmt.Sprintf("MAX(LENGTH(%s))", col),
+			fmt.Sprintf("SUM(CASE WHEN LENGTH(%s) = 0 THEN 1 ELSE 0 END)", col),
+			fmt.Sprintf("SUM(CASE WHEN %s ~ '^\\s' THEN 1 ELSE 0 END)", col),
+			trailing,
+			fmt.Sprintf("SUM(CASE WHEN %s ~ '^\\s+$' THEN 1 ELSE 0 END)", col),
+		}, true
 	}
-
-	return result, nil
+	return nil, false
 }
 
 func (p *postgresProvider) QuoteIdentifier(name string) string {
@@ -182,3 +335,28 @@ func (p *postgresProvider) QuoteTableName(_ *DatabaseConfig, tableName string) s
 	}
 	return fmt.Sprintf("%s.%s", p.QuoteIdentifier(parts[0]), p.QuoteIdentifier(parts[1]))
 }
+
+// TableSource 使用块级 TABLESAMPLE SYSTEM，REPEATABLE 保证各查询读取相同的样本
+func (p *postgresProvider) TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string {
+	return fmt.Sprintf("%s TABLESAMPLE SYSTEM (%g) REPEATABLE (%d)", p.QuoteTableName(config, tableName), sampling.Percent, sampling.seed())
+}
+
+func (p *postgresProvider) TimestampLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP '%s'", t.Format("2006-01-02 15:04:05.999999"))
+}
+
+// postgresLOBColumnTypes json、xml 没有相等运算符不能分组；bytea 可以分组但不适合作为取值展示
+var postgresLOBColumnTypes = map[string]bool{
+	"json":  true,
+	"xml":   true,
+	"bytea": true,
+}
+
+// Capabilities PostgreSQL 的目标列表最多 1664 项
+func (p *postgresProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{MaxSelectExpressions: 1664, LOBColumnTypes: postgresLOBColumnTypes}
+}
+
+func (p *postgresProvider) LimitQuery(query string, limit int) string {
+	return fmt.Sprintf("%s LIMIT %d", query, limit)
+}
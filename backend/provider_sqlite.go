@@ -0,0 +1,387 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriverName 注册了统计函数的 SQLite 驱动，与 StorageManager 使用的 sqlite3 驱动相互独立
+const sqliteDriverName = "sqlite3_mole"
+
+// sqliteWhitespace 为 TRIM 使用的空白字符（空格、制表、换行、回车）集合
+const sqliteWhitespace = "' ' || char(9) || char(10) || char(13)"
+
+func init() {
+	// SQLite 没有内置的标准差聚合函数，在每个连接上注册
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterAggregator("stddev_samp", newSQLiteStdDev, true)
+		},
+	})
+}
+
+// sqliteStdDev 样本标准差聚合，使用 Welford 算法逐行累计以避免大数相减的精度损失
+type sqliteStdDev struct {
+	count int64
+	mean  float64
+	m2    float64
+}
+
+func newSQLiteStdDev() *sqliteStdDev {
+	return &sqliteStdDev{}
+}
+
+// Step 累计一行，NULL 及非数值忽略
+func (s *sqliteStdDev) Step(value interface{}) {
+	var x float64
+	switch v := value.(type) {
+	case int64:
+		x = float64(v)
+	case float64:
+		x = v
+	default:
+		return
+	}
+	s.count++
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Done 少于两个值时与 STDDEV_SAMP 一致返回 NULL
+func (s *sqliteStdDev) Done() interface{} {
+	if s.count < 2 {
+		return nil
+	}
+	return math.Sqrt(s.m2 / float64(s.count-1))
+}
+
+type sqliteProvider struct {
+	baseProvider
+}
+
+func (p *sqliteProvider) Name() string {
+	return "sqlite"
+}
+
+func (p *sqliteProvider) DriverName() string {
+	return sqliteDriverName
+}
+
+// BuildDSN 以只读方式打开 FilePath（兼容早期填写在 Database 中的路径），文件不存在时报错而不是创建空库
+func (p *sqliteProvider) BuildDSN(config *DatabaseConfig) (string, error) {
+	path := strings.TrimSpace(config.FilePath)
+	if path == "" {
+		path = strings.TrimSpace(config.Database)
+	}
+	if path == "" {
+		return "", fmt.Errorf("sqlite database file path is required")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("sqlite database file not accessible: %w", err)
+	}
+	return fmt.Sprintf("%s?_query_only=1&_busy_timeout=5000", path), nil
+}
+
+func (p *sqliteProvider) GetTables(ctx context.Context, db *sql.DB, _ *DatabaseConfig) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT name
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		tables = append(tables, tableName)
+	}
+	return tables, rows.Err()
+}
+
+func (p *sqliteProvider) GetTableMetadata(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+
+	rowCount, estimated, err := estimateRowCount(ctx, db, p, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = estimated
+
+	var columnCount int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?)", tableName).Scan(&columnCount)
+	if err != nil {
+		return nil, err
+	}
+	metadata["column_count"] = columnCount
+	// 单表占用的页数需要 dbstat 虚拟表，默认编译选项不提供
+	metadata["data_size"] = int64(0)
+	metadata["comment"] = ""
+
+	return metadata, nil
+}
+
+// GetTableColumns 列类型为建表时声明的类型，SQLite 不强制长度和精度，不返回长度、精度信息
+func (p *sqliteProvider) GetTableColumns(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]ColumnMetadata, error) {
+	query := `
+		SELECT
+			name,
+			'' AS column_comment,
+			cid + 1,
+			type,
+			CASE WHEN "notnull" = 0 AND pk = 0 THEN 'YES' ELSE 'NO' END,
+			dflt_value,
+			NULL,
+			NULL,
+			NULL
+		FROM pragma_table_info(?)
+		ORDER BY cid
+	`
+
+	rows, err := db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnMetadata
+	for rows.Next() {
+		var column ColumnMetadata
+		var details columnDetails
+		if err := rows.Scan(append([]interface{}{
+			&column.ColumnName,
+			&column.ColumnComment,
+			&column.ColumnOrdinal,
+			&column.ColumnType,
+		}, details.scanArgs()...)...); err != nil {
+			return nil, err
+		}
+		details.apply(&column)
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+// GetTableConstraints SQLite 的主键和外键没有名称，按 pk_<表名>、fk_<表名>_<序号> 命名；唯一约束使用其自动创建的索引名
+func (p *sqliteProvider) GetTableConstraints(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableConstraint, error) {
+	query := `
+		SELECT constraint_name, constraint_type, column_name, referenced_table, referenced_column
+		FROM (
+			SELECT 'pk_' || ?1 AS constraint_name, 'PRIMARY KEY' AS constraint_type, name AS column_name,
+				NULL AS referenced_table, NULL AS referenced_column, pk AS seq
+			FROM pragma_table_info(?1)
+			WHERE pk > 0
+			UNION ALL
+			SELECT il.name, 'UNIQUE', ii.name, NULL, NULL, ii.seqno
+			FROM pragma_index_list(?1) il
+			JOIN pragma_index_info(il.name) ii
+			WHERE il."unique" = 1 AND il.origin = 'u'
+			UNION ALL
+			SELECT 'fk_' || ?1 || '_' || id, 'FOREIGN KEY', "from", "table", "to", seq
+			FROM pragma_foreign_key_list(?1)
+		)
+		ORDER BY constraint_name, seq
+	`
+
+	rows, err := db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableConstraints(rows)
+}
+
+// GetTableIndexes INTEGER PRIMARY KEY 即 rowid，没有单独的索引
+func (p *sqliteProvider) GetTableIndexes(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableIndex, error) {
+	query := `
+		SELECT
+			il.name,
+			COALESCE(ii.name, '<expression>'),
+			il."unique",
+			'BTREE',
+			CASE WHEN il.origin = 'pk' THEN 1 ELSE 0 END
+		FROM pragma_index_list(?) il
+		JOIN pragma_index_info(il.name) ii
+		ORDER BY il.name, ii.seqno
+	`
+
+	rows, err := db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableIndexes(rows)
+}
+
+func (p *sqliteProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", sampledTableRef(ctx, p, config, tableName))
+	var rowCount int64
+	if err := db.QueryRowContext(ctx, query).Scan(&rowCount); err != nil {
+		return 0, err
+	}
+	return rowCount, nil
+}
+
+// EstimateRowCount 读取 ANALYZE 写入 sqlite_stat1 的行数，stat 的第一项为表的行数；未执行过 ANALYZE 时为 -1
+func (p *sqliteProvider) EstimateRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
+	var analyzed int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'sqlite_stat1'").Scan(&analyzed)
+	if err != nil {
+		return 0, err
+	}
+	if analyzed == 0 {
+		return -1, nil
+	}
+
+	var stat string
+	err = db.QueryRowContext(ctx, `
+		SELECT stat
+		FROM sqlite_stat1
+		WHERE tbl = ?
+		ORDER BY idx IS NOT NULL
+		LIMIT 1
+	`, tableName).Scan(&stat)
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(stat)
+	if len(fields) == 0 {
+		return -1, nil
+	}
+	rowCount, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return -1, nil
+	}
+	return rowCount, nil
+}
+
+func (p *sqliteProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
+	return executeNonNullRate(ctx, db, config, p, tableName)
+}
+
+func (p *sqliteProvider) ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error) {
+	return executeDistinctCount(ctx, db, config, p, tableName)
+}
+
+func (p *sqliteProvider) ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error) {
+	return executeNearestRankNumericStats(ctx, db, config, p, tableName)
+}
+
+func (p *sqliteProvider) ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	temporal := isTemporalColumnType(column.ColumnType)
+	valueExpr := fmt.Sprintf("CAST(%s AS REAL)", col)
+	if temporal {
+		valueExpr = fmt.Sprintf("CAST(strftime('%%s', %s) AS REAL)", col)
+	}
+
+	dialect := histogramDialect{
+		tableRef:  sampledTableRef(ctx, p, config, tableName),
+		column:    col,
+		valueExpr: valueExpr,
+		// 没有 FLOOR，值不小于下界时截断取整即为向下取整；多参数 MIN 为标量函数
+		bucketExpr: func(lower, upper float64, bins int) string {
+			return fmt.Sprintf("MIN(CAST((%s - %s) * %d / (%s - %s) AS INTEGER), %d)",
+				valueExpr, sqlFloat(lower), bins, sqlFloat(upper), sqlFloat(lower), bins-1)
+		},
+	}
+	return executeHistogram(ctx, db, dialect, bins, mode, temporal)
+}
+
+func (p *sqliteProvider) ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error) {
+	return executeStringStats(ctx, db, config, p, tableName)
+}
+
+func (p *sqliteProvider) AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	switch metric {
+	case AggregateRowCount:
+		return []string{"COUNT(*)"}, true
+	case AggregateNonNullRate:
+		return []string{fmt.Sprintf("1 - AVG(CASE WHEN %s IS NULL THEN 1.0 ELSE 0.0 END)", col)}, true
+	case AggregateDistinctCount:
+		return []string{fmt.Sprintf("COUNT(DISTINCT %s)", col)}, true
+	case AggregateNumericMoments:
+		return []string{
+			fmt.Sprintf("MIN(%s)", col),
+			fmt.Sprintf("MAX(%s)", col),
+			fmt.Sprintf("AVG(%s)", col),
+			fmt.Sprintf("stddev_samp(%s)", col),
+			fmt.Sprintf("COUNT(%s)", col),
+		}, true
+	case AggregateStringStats:
+		// 没有正则，比较去除首尾空白前后的长度；SQLite 不以空格补齐定长字符
+		return []string{
+			fmt.Sprintf("COUNT(%s)", col),
+			fmt.Sprintf("MIN(LENGTH(%s))", col),
+			fmt.Sprintf("AVG(LENGTH(%s))", col),
+			fmt.Sprintf("MAX(LENGTH(%s))", col),
+			fmt.Sprintf("SUM(CASE WHEN LENGTH(%s) = 0 THEN 1 ELSE 0 END)", col),
+			fmt.Sprintf("SUM(CASE WHEN LENGTH(LTRIM(%s, %s)) < LENGTH(%s) THEN 1 ELSE 0 END)", col, sqliteWhitespace, col),
+			fmt.Sprintf("SUM(CASE WHEN LENGTH(RTRIM(%s, %s)) < LENGTH(%s) THEN 1 ELSE 0 END)", col, sqliteWhitespace, col),
+			fmt.Sprintf("SUM(CASE WHEN LENGTH(%s) > 0 AND LENGTH(TRIM(%s, %s)) = 0 THEN 1 ELSE 0 END)", col, col, sqliteWhitespace),
+		}, true
+	}
+	// 分位数需要窗口函数单独扫描
+	return nil, false
+}
+
+func (p *sqliteProvider) QuoteIdentifier(name string) string {
+	replaced := strings.ReplaceAll(name, "\"", "\"\"")
+	return fmt.Sprintf("\"%s\"", replaced)
+}
+
+// QuoteTableName GetTables 返回的表名不带库名，表名中的点号属于表名本身
+func (p *sqliteProvider) QuoteTableName(_ *DatabaseConfig, tableName string) string {
+	return p.QuoteIdentifier(tableName)
+}
+
+// TableSource SQLite 没有 TABLESAMPLE 且 random() 不能指定种子，按 rowid 与种子的乘法散列过滤行，保证各查询读取相同的样本
+// WITHOUT ROWID 表没有 rowid，不支持采样
+func (p *sqliteProvider) TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string {
+	return fmt.Sprintf("(SELECT * FROM %s WHERE ((rowid * 2654435761 + %d) %% 4294967296) %% 10000 < %g) sampled_rows",
+		p.QuoteTableName(config, tableName), sampling.seed(), sampling.Percent*100)
+}
+
+// TimestampLiteral SQLite 的日期时间列可能保存任意格式的文本或 Unix 时间戳，字面量只按驱动写入的格式生成，不用于范围比较
+func (p *sqliteProvider) TimestampLiteral(t time.Time) string {
+	return fmt.Sprintf("'%s'", t.Format("2006-01-02 15:04:05.999999999"))
+}
+
+// sqliteLOBColumnTypes SQLite 的任何值都可以分组，二进制内容不适合作为取值展示
+var sqliteLOBColumnTypes = map[string]bool{
+	"blob": true,
+}
+
+// Capabilities SQLite 默认的 SQLITE_MAX_COLUMN 为 2000，同样限制结果列数；
+// 日期时间列按文本比较时 ISO 格式的 'T' 分隔符与空格顺序不同，整数时间戳与文本也不可比
+func (p *sqliteProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{MaxSelectExpressions: 2000, UntypedTemporalColumns: true, LOBColumnTypes: sqliteLOBColumnTypes}
+}
+
+func (p *sqliteProvider) LimitQuery(query string, limit int) string {
+	return fmt.Sprintf("%s LIMIT %d", query, limit)
+}
@@ -0,0 +1,166 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// openSQLiteFixture 在临时目录中建库并执行 statements，返回以只读方式打开的连接
+func openSQLiteFixture(t *testing.T, statements ...string) (*sql.DB, *DatabaseConfig, DatabaseProvider) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.db")
+	writer, err := sql.Open(sqliteDriverName, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	for _, statement := range statements {
+		if _, err := writer.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+
+	provider := &sqliteProvider{}
+	config := &DatabaseConfig{Type: "sqlite", FilePath: path}
+	dsn, err := provider.BuildDSN(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open(provider.DriverName(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, config, provider
+}
+
+// sqliteEventsTable 日期时间列混合了空格分隔、'T' 分隔的文本和 Unix 时间戳
+var sqliteEventsTable = []string{
+	`CREATE TABLE events (id INTEGER PRIMARY KEY, seq INTEGER, created_at DATETIME)`,
+	`INSERT INTO events VALUES (1, 10, '2024-01-01 09:00:00')`,
+	`INSERT INTO events VALUES (2, 20, '2024-01-01T10:00:00')`,
+	`INSERT INTO events VALUES (3, NULL, 1704103200)`,
+	`INSERT INTO events VALUES (4, 40, '2024-01-02 08:30:00')`,
+	`INSERT INTO events VALUES (5, 55, NULL)`,
+	`INSERT INTO events VALUES (6, 61, '2024-01-03T00:00:00Z')`,
+}
+
+// countRows 返回满足 filter 条件的行数
+func countRows(t *testing.T, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, filter rowFilter) int {
+	t.Helper()
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", provider.QuoteTableName(config, "events"), filter.condition(provider))
+	if err := db.QueryRow(query).Scan(&count); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return count
+}
+
+func TestSQLiteTemporalColumnsNotOrdered(t *testing.T) {
+	db, config, provider := openSQLiteFixture(t, sqliteEventsTable...)
+	ctx := context.Background()
+	columns, err := provider.GetTableColumns(ctx, db, config, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := findOrderedColumn(provider, columns, "created_at"); err == nil {
+		t.Error("temporal column accepted as watermark column")
+	}
+	if _, err := resolvePartitionColumn(ctx, db, config, provider, "events", columns, "created_at"); err == nil {
+		t.Error("temporal column accepted as partition column")
+	}
+	column, err := resolvePartitionColumn(ctx, db, config, provider, "events", columns, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if column.ColumnName != "id" {
+		t.Errorf("partition column %s, want primary key id", column.ColumnName)
+	}
+}
+
+func TestSQLitePartitionRanges(t *testing.T) {
+	db, config, provider := openSQLiteFixture(t, sqliteEventsTable...)
+	ctx := context.Background()
+	columns, err := provider.GetTableColumns(ctx, db, config, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	column, err := findOrderedColumn(provider, columns, "seq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ranges, err := planPartitionRanges(ctx, db, config, provider, "events", column, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 3 {
+		t.Fatalf("planned %d partitions, want 3", len(ranges))
+	}
+	// 边界为 27、44，首个分区包含 NULL
+	want := []int{3, 1, 2}
+	for i := range ranges {
+		if got := countRows(t, db, config, provider, &ranges[i]); got != want[i] {
+			t.Errorf("partition %d (%s): %d rows, want %d", i, ranges[i].condition(provider), got, want[i])
+		}
+	}
+}
+
+func TestSQLiteWatermark(t *testing.T) {
+	db, config, provider := openSQLiteFixture(t, sqliteEventsTable...)
+	ctx := context.Background()
+	columns, err := provider.GetTableColumns(ctx, db, config, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	column, err := findOrderedColumn(provider, columns, "seq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watermark, err := queryWatermark(ctx, db, config, provider, "events", column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watermark != "61" {
+		t.Fatalf("watermark %q, want 61", watermark)
+	}
+	if got := countRows(t, db, config, provider, &watermarkRange{column: "seq", upper: watermark}); got != 6 {
+		t.Errorf("first range: %d rows, want 6", got)
+	}
+	if got := countRows(t, db, config, provider, &watermarkRange{column: "seq", lower: "20", upper: watermark}); got != 3 {
+		t.Errorf("range (20, 61]: %d rows, want 3", got)
+	}
+}
+
+func TestSQLiteTopValuesColumnErrors(t *testing.T) {
+	// 视图的 bad 列在读取时报错（JSON 路径非法），其余列应照常统计
+	db, config, provider := openSQLiteFixture(t,
+		`CREATE TABLE items (kind TEXT, payload BLOB, doc TEXT)`,
+		`INSERT INTO items VALUES ('a', x'00', '[1]'), ('a', x'01', '[2]'), ('b', x'02', '[3]')`,
+		`CREATE VIEW item_view AS SELECT kind, payload, json_extract(doc, '$[') AS bad FROM items`,
+	)
+
+	output, err := NewTopValuesRule(5).Execute(context.Background(), db, "item_view", config, provider, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := NewAnalysisResultSet()
+	result.SetRuleOutput("top_values", output)
+	rule := result.Rules["top_values"]
+
+	if _, ok := rule.Columns["payload"]; ok {
+		t.Error("blob column should be skipped")
+	}
+	if _, ok := rule.ColumnErrors["bad"]; !ok {
+		t.Errorf("missing error for column bad, errors: %v", rule.ColumnErrors)
+	}
+	kinds, ok := rule.Columns["kind"].([]ValueFrequency)
+	if !ok || len(kinds) != 2 || *kinds[0].Value != "a" || kinds[0].Count != 2 {
+		t.Errorf("kind top values: %+v", rule.Columns["kind"])
+	}
+}
@@ -3,9 +3,17 @@ package backend
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
+)
+
+// sqlServerWhitespaceClass / sqlServerNonWhitespaceClass 为 LIKE 使用的空白字符（空格、制表、换行、回车）集合
+const (
+	sqlServerWhitespaceClass    = "'[ ' + CHAR(9) + CHAR(10) + CHAR(13) + ']'"
+	sqlServerNonWhitespaceClass = "'[^ ' + CHAR(9) + CHAR(10) + CHAR(13) + ']'"
 )
 
 type sqlServerProvider struct {
@@ -65,11 +73,12 @@ func (p *sqlServerProvider) GetTableMetadata(ctx context.Context, db *sql.DB, _
 	schema, table := splitSchemaAndTable(tableName, "dbo")
 	metadata := make(map[string]interface{})
 
-	rowCount, err := p.ExecuteRowCount(ctx, db, nil, tableName)
+	rowCount, estimated, err := estimateRowCount(ctx, db, p, nil, tableName)
 	if err != nil {
 		return nil, err
 	}
 	metadata["row_count"] = rowCount
+	metadata["row_count_estimated"] = estimated
 
 	var columnCount int
 	err = db.QueryRowContext(ctx, `
@@ -94,7 +103,12 @@ func (p *sqlServerProvider) GetTableColumns(ctx context.Context, db *sql.DB, _ *
 			c.COLUMN_NAME,
 			'',
 			c.ORDINAL_POSITION,
-			c.DATA_TYPE
+			c.DATA_TYPE,
+			c.IS_NULLABLE,
+			c.COLUMN_DEFAULT,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			CAST(c.NUMERIC_PRECISION AS INT),
+			c.NUMERIC_SCALE
 		FROM INFORMATION_SCHEMA.COLUMNS c
 		WHERE c.TABLE_SCHEMA = @p1 AND c.TABLE_NAME = @p2
 		ORDER BY c.ORDINAL_POSITION
@@ -109,22 +123,93 @@ func (p *sqlServerProvider) GetTableColumns(ctx context.Context, db *sql.DB, _ *
 	var columns []ColumnMetadata
 	for rows.Next() {
 		var column ColumnMetadata
-		if err := rows.Scan(
+		var details columnDetails
+		if err := rows.Scan(append([]interface{}{
 			&column.ColumnName,
 			&column.ColumnComment,
 			&column.ColumnOrdinal,
 			&column.ColumnType,
-		); err != nil {
+		}, details.scanArgs()...)...); err != nil {
 			return nil, err
 		}
+		details.apply(&column)
 		columns = append(columns, column)
 	}
 
 	return columns, nil
 }
 
-func (p *sqlServerProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", p.QuoteTableName(nil, tableName))
+func (p *sqlServerProvider) GetTableConstraints(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableConstraint, error) {
+	schema, table := splitSchemaAndTable(tableName, "dbo")
+	query := `
+		SELECT constraint_name, constraint_type, column_name, referenced_table, referenced_column
+		FROM (
+			SELECT
+				kc.name AS constraint_name,
+				kc.type AS constraint_type,
+				c.name AS column_name,
+				CAST(NULL AS NVARCHAR(257)) AS referenced_table,
+				CAST(NULL AS SYSNAME) AS referenced_column,
+				ic.key_ordinal AS ord
+			FROM sys.key_constraints kc
+			JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+			JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE kc.parent_object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2))
+			UNION ALL
+			SELECT
+				fk.name,
+				'F',
+				pc.name,
+				SCHEMA_NAME(rt.schema_id) + '.' + rt.name,
+				rc.name,
+				fkc.constraint_column_id
+			FROM sys.foreign_keys fk
+			JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+			JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+			JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+			JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+			WHERE fk.parent_object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2))
+		) constraints
+		ORDER BY constraint_name, ord
+	`
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableConstraints(rows)
+}
+
+func (p *sqlServerProvider) GetTableIndexes(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) ([]TableIndex, error) {
+	schema, table := splitSchemaAndTable(tableName, "dbo")
+	query := `
+		SELECT
+			i.name,
+			c.name,
+			CAST(i.is_unique AS INT),
+			i.type_desc,
+			CAST(i.is_primary_key AS INT)
+		FROM sys.indexes i
+		JOIN sys.index_columns ic
+			ON ic.object_id = i.object_id AND ic.index_id = i.index_id AND ic.is_included_column = 0
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2)) AND i.name IS NOT NULL
+		ORDER BY i.name, ic.key_ordinal, ic.index_column_id
+	`
+
+	rows, err := db.QueryContext(ctx, query, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTableIndexes(rows)
+}
+
+func (p *sqlServerProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", sampledTableRef(ctx, p, config, tableName))
 	var rowCount int64
 	if err := db.QueryRowContext(ctx, query).Scan(&rowCount); err != nil {
 		return 0, err
@@ -132,44 +217,153 @@ func (p *sqlServerProvider) ExecuteRowCount(ctx context.Context, db *sql.DB, _ *
 	return rowCount, nil
 }
 
+// EstimateRowCount 汇总堆或聚集索引各分区的行数，该值由存储引擎维护，通常接近准确值
+func (p *sqlServerProvider) EstimateRowCount(ctx context.Context, db *sql.DB, _ *DatabaseConfig, tableName string) (int64, error) {
+	schema, table := splitSchemaAndTable(tableName, "dbo")
+	var rowCount sql.NullInt64
+	err := db.QueryRowContext(ctx, `
+		SELECT SUM(p.rows)
+		FROM sys.partitions p
+		WHERE p.object_id = OBJECT_ID(QUOTENAME(@p1) + '.' + QUOTENAME(@p2)) AND p.index_id IN (0, 1)
+	`, schema, table).Scan(&rowCount)
+	if err != nil {
+		return 0, err
+	}
+	if !rowCount.Valid {
+		return -1, nil
+	}
+	return rowCount.Int64, nil
+}
+
 func (p *sqlServerProvider) ExecuteNonNullRate(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]float64, error) {
-	columns, err := p.GetTableColumns(ctx, db, config, tableName)
+	return executeNonNullRate(ctx, db, config, p, tableName)
+}
+
+func (p *sqlServerProvider) ExecuteDistinctCount(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]ColumnDistinctStats, error) {
+	return executeDistinctCount(ctx, db, config, p, tableName)
+}
+
+func (p *sqlServerProvider) ExecuteNumericStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]NumericColumnStats, error) {
+	columns, err := tableColumns(ctx, db, config, p, tableName)
 	if err != nil {
 		return nil, err
 	}
+	columns = filterColumns(columns, func(column ColumnMetadata) bool {
+		return isNumericColumnType(column.ColumnType)
+	})
 	if len(columns) == 0 {
-		return map[string]float64{}, nil
+		return map[string]NumericColumnStats{}, nil
 	}
 
-	selectParts := make([]string, 0, len(columns))
-	for _, column := range columns {
-		col := p.QuoteIdentifier(column.ColumnName)
-		selectParts = append(selectParts, fmt.Sprintf("1.0 - AVG(CASE WHEN %s IS NULL THEN 1.0 ELSE 0.0 END) AS %s", col, col))
+	width := numericStatsWidth()
+	values := make([]sql.NullFloat64, len(columns)*width)
+	tableRef := sampledTableRef(ctx, p, config, tableName)
+
+	for _, chunk := range columnChunks(len(columns), width, p.Capabilities().MaxSelectExpressions) {
+		// 基础聚合：AVG 对整数列做整数运算，统一转换为 FLOAT
+		aggregateParts := make([]string, 0, (chunk[1]-chunk[0])*4)
+		aggregateArgs := make([]interface{}, 0, (chunk[1]-chunk[0])*4)
+		for i := chunk[0]; i < chunk[1]; i++ {
+			col := fmt.Sprintf("CAST(%s AS FLOAT)", p.QuoteIdentifier(columns[i].ColumnName))
+			aggregateParts = append(aggregateParts,
+				fmt.Sprintf("MIN(%s)", col),
+				fmt.Sprintf("MAX(%s)", col),
+				fmt.Sprintf("AVG(%s)", col),
+				fmt.Sprintf("STDEV(%s)", col),
+			)
+			for j := 0; j < 4; j++ {
+				aggregateArgs = append(aggregateArgs, &values[i*width+j])
+			}
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(aggregateParts, ", "), tableRef)
+		if err := db.QueryRowContext(ctx, query).Scan(aggregateArgs...); err != nil {
+			return nil, err
+		}
+
+		// SQL Server 的 PERCENTILE_CONT 只能作为窗口函数使用，单独一次扫描计算
+		percentileParts := make([]string, 0, (chunk[1]-chunk[0])*len(numericPercentiles))
+		percentileArgs := make([]interface{}, 0, (chunk[1]-chunk[0])*len(numericPercentiles))
+		for i := chunk[0]; i < chunk[1]; i++ {
+			col := fmt.Sprintf("CAST(%s AS FLOAT)", p.QuoteIdentifier(columns[i].ColumnName))
+			for j, percentile := range numericPercentiles {
+				percentileParts = append(percentileParts, fmt.Sprintf("PERCENTILE_CONT(%g) WITHIN GROUP (ORDER BY %s) OVER ()", percentile, col))
+				percentileArgs = append(percentileArgs, &values[i*width+4+j])
+			}
+		}
+
+		query = fmt.Sprintf("SELECT TOP 1 %s FROM %s", strings.Join(percentileParts, ", "), tableRef)
+		err = db.QueryRowContext(ctx, query).Scan(percentileArgs...)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectParts, ", "), p.QuoteTableName(config, tableName))
-	row := db.QueryRowContext(ctx, query)
+	return buildNumericStats(columns, values, false), nil
+}
 
-	values := make([]sql.NullFloat64, len(columns))
-	scanArgs := make([]interface{}, len(columns))
-	for i := range values {
-		scanArgs[i] = &values[i]
+func (p *sqlServerProvider) ExecuteHistogram(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string, column ColumnMetadata, bins int, mode HistogramMode) (*ColumnHistogram, error) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	temporal := isTemporalColumnType(column.ColumnType)
+	valueExpr := fmt.Sprintf("CAST(%s AS FLOAT)", col)
+	if temporal {
+		valueExpr = fmt.Sprintf("CAST(DATEDIFF_BIG(SECOND, '1970-01-01', %s) AS FLOAT)", col)
 	}
 
-	if err := row.Scan(scanArgs...); err != nil {
-		return nil, err
+	dialect := histogramDialect{
+		tableRef:  sampledTableRef(ctx, p, config, tableName),
+		column:    col,
+		valueExpr: valueExpr,
+		// SQL Server 2022 之前没有 LEAST，用 CASE 处理等于上界的值
+		bucketExpr: func(lower, upper float64, bins int) string {
+			return fmt.Sprintf("CASE WHEN %s >= %s THEN %d ELSE FLOOR((%s - %s) * %d / (%s - %s)) END",
+				valueExpr, sqlFloat(upper), bins-1, valueExpr, sqlFloat(lower), bins, sqlFloat(upper), sqlFloat(lower))
+		},
 	}
+	return executeHistogram(ctx, db, dialect, bins, mode, temporal)
+}
+
+func (p *sqlServerProvider) ExecuteStringStats(ctx context.Context, db *sql.DB, config *DatabaseConfig, tableName string) (map[string]StringColumnStats, error) {
+	return executeStringStats(ctx, db, config, p, tableName)
+}
 
-	result := make(map[string]float64, len(columns))
-	for i, column := range columns {
-		val := 0.0
-		if values[i].Valid {
-			val = clampRatio(values[i].Float64)
+func (p *sqlServerProvider) AggregateExpressions(metric AggregateMetric, column ColumnMetadata) ([]string, bool) {
+	col := p.QuoteIdentifier(column.ColumnName)
+	switch metric {
+	case AggregateRowCount:
+		return []string{"COUNT_BIG(*)"}, true
+	case AggregateNonNullRate:
+		return []string{fmt.Sprintf("1.0 - AVG(CASE WHEN %s IS NULL THEN 1.0 ELSE 0.0 END)", col)}, true
+	case AggregateDistinctCount:
+		return []string{fmt.Sprintf("COUNT_BIG(DISTINCT %s)", col)}, true
+	case AggregateNumericMoments:
+		value := fmt.Sprintf("CAST(%s AS FLOAT)", col)
+		return []string{
+			fmt.Sprintf("MIN(%s)", value),
+			fmt.Sprintf("MAX(%s)", value),
+			fmt.Sprintf("AVG(%s)", value),
+			fmt.Sprintf("STDEV(%s)", value),
+			fmt.Sprintf("COUNT_BIG(%s)", col),
+		}, true
+	case AggregateStringStats:
+		trailing := fmt.Sprintf("SUM(CASE WHEN %s LIKE '%%' + %s THEN 1 ELSE 0 END)", col, sqlServerWhitespaceClass)
+		if isFixedCharColumnType(column.ColumnType) {
+			trailing = "0"
 		}
-		result[column.ColumnName] = val
+		return []string{
+			fmt.Sprintf("COUNT(%s)", col),
+			// LEN 不计尾部空格
+			fmt.Sprintf("MIN(LEN(%s))", col),
+			fmt.Sprintf("AVG(CAST(LEN(%s) AS FLOAT))", col),
+			fmt.Sprintf("MAX(LEN(%s))", col),
+			fmt.Sprintf("SUM(CASE WHEN DATALENGTH(%s) = 0 THEN 1 ELSE 0 END)", col),
+			fmt.Sprintf("SUM(CASE WHEN %s LIKE %s + '%%' THEN 1 ELSE 0 END)", col, sqlServerWhitespaceClass),
+			trailing,
+			fmt.Sprintf("SUM(CASE WHEN DATALENGTH(%s) > 0 AND %s NOT LIKE '%%' + %s + '%%' THEN 1 ELSE 0 END)", col, col, sqlServerNonWhitespaceClass),
+		}, true
 	}
-
-	return result, nil
+	// PERCENTILE_CONT 只能作为窗口函数使用，需要单独扫描
+	return nil, false
 }
 
 func (p *sqlServerProvider) QuoteIdentifier(name string) string {
@@ -182,6 +376,41 @@ func (p *sqlServerProvider) QuoteTableName(_ *DatabaseConfig, tableName string)
 	return fmt.Sprintf("%s.%s", p.QuoteIdentifier(schema), p.QuoteIdentifier(table))
 }
 
+// TableSource 使用按页采样的 TABLESAMPLE，REPEATABLE 保证各查询读取相同的样本
+func (p *sqlServerProvider) TableSource(config *DatabaseConfig, tableName string, sampling *SamplingConfig) string {
+	return fmt.Sprintf("%s TABLESAMPLE SYSTEM (%g PERCENT) REPEATABLE (%d)", p.QuoteTableName(config, tableName), sampling.Percent, sampling.seed())
+}
+
+// TimestampLiteral 使用 DATETIME2 保留 100 纳秒精度，与 DATETIME、DATE 列比较时隐式转换
+func (p *sqlServerProvider) TimestampLiteral(t time.Time) string {
+	return fmt.Sprintf("CAST('%s' AS DATETIME2)", t.Format("2006-01-02T15:04:05.9999999"))
+}
+
+// Capabilities SQL Server 的 SELECT 列表虽可达 4096 列，但每项都是聚合表达式的宽查询远未到该上限就会失败：
+// 表达式服务达到上限（错误 8632）或查询处理器资源不足无法生成计划（错误 8623），约 1500 个聚合时已出现，因此按 1024 拆分
+func (p *sqlServerProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{MaxSelectExpressions: 1024, LOBColumnTypes: sqlServerLOBColumnTypes}
+}
+
+// sqlServerLOBColumnTypes 旧式的 text、ntext、image 及 xml、空间类型不能比较或排序，不能参与 DISTINCT / GROUP BY
+// varchar(max) 等可以分组，去除长度修饰后与普通字符类型相同
+var sqlServerLOBColumnTypes = map[string]bool{
+	"text":      true,
+	"ntext":     true,
+	"image":     true,
+	"xml":       true,
+	"geography": true,
+	"geometry":  true,
+}
+
+// LimitQuery 使用 OFFSET/FETCH 限制行数，该语法要求查询带有 ORDER BY
+func (p *sqlServerProvider) LimitQuery(query string, limit int) string {
+	if !strings.Contains(strings.ToUpper(query), "ORDER BY") {
+		query += " ORDER BY (SELECT NULL)"
+	}
+	return fmt.Sprintf("%s OFFSET 0 ROWS FETCH NEXT %d ROWS ONLY", query, limit)
+}
+
 func splitSchemaAndTable(fullName, defaultSchema string) (string, string) {
 	parts := strings.Split(fullName, ".")
 	if len(parts) == 1 {
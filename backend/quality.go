@@ -0,0 +1,339 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ExpectationOperator 期望的比较方式
+type ExpectationOperator string
+
+const (
+	OpGreaterOrEqual ExpectationOperator = ">="
+	OpLessOrEqual    ExpectationOperator = "<="
+	OpGreater        ExpectationOperator = ">"
+	OpLess           ExpectationOperator = "<"
+	OpEqual          ExpectationOperator = "=="
+	OpNotEqual       ExpectationOperator = "!="
+	OpBetween        ExpectationOperator = "between"
+	OpInSet          ExpectationOperator = "in" // 列的取值均在 Values 中，只适用于 values 指标
+)
+
+// ExpectationSeverity 期望不满足时的判定级别
+type ExpectationSeverity string
+
+const (
+	SeverityError   ExpectationSeverity = "error"   // 不满足时判定为 fail
+	SeverityWarning ExpectationSeverity = "warning" // 不满足时判定为 warn
+)
+
+// VerdictStatus 期望的判定结果
+type VerdictStatus string
+
+const (
+	VerdictPass    VerdictStatus = "pass"
+	VerdictWarn    VerdictStatus = "warn"
+	VerdictFail    VerdictStatus = "fail"
+	VerdictSkipped VerdictStatus = "skipped" // 所需规则未执行或失败，不计入质量评分
+)
+
+// metricValuesInSet 取值集合指标，基于 top_values 结果判定
+const metricValuesInSet = "values"
+
+// expectationMetricAliases 常用指标到 规则名.字段 的映射
+// 未列出的指标按 规则名 或 规则名.字段 解析，以便引用后续新增的规则和自定义规则
+var expectationMetricAliases = map[string]string{
+	"distinct_ratio":       "distinct_count.distinct_ratio",
+	"min":                  "numeric_stats.min",
+	"max":                  "numeric_stats.max",
+	"mean":                 "numeric_stats.mean",
+	"stddev":               "numeric_stats.stddev",
+	"empty_rate":           "string_stats.empty_rate",
+	"whitespace_only_rate": "string_stats.whitespace_only_rate",
+	"min_length":           "string_stats.min_length",
+	"max_length":           "string_stats.max_length",
+	"duplicate_rate":       "duplicates.full_row.duplicate_rate",
+}
+
+// Expectation 数据质量期望，ColumnName 为空时为表级期望（如 row_count、duplicate_rate）
+type Expectation struct {
+	ID         string              `json:"id"`
+	TableID    string              `json:"tableId"`
+	ColumnName string              `json:"columnName"`
+	Metric     string              `json:"metric"`
+	Operator   ExpectationOperator `json:"operator"`
+	Threshold  float64             `json:"threshold"`
+	Upper      float64             `json:"upper"`            // between 的上限
+	Values     []string            `json:"values,omitempty"` // in 的取值集合
+	Severity   ExpectationSeverity `json:"severity"`
+}
+
+// Validate 校验期望定义
+func (e *Expectation) Validate() error {
+	if strings.TrimSpace(e.Metric) == "" {
+		return fmt.Errorf("expectation metric is required")
+	}
+
+	switch e.Operator {
+	case OpGreaterOrEqual, OpLessOrEqual, OpGreater, OpLess, OpEqual, OpNotEqual:
+	case OpBetween:
+		if e.Threshold > e.Upper {
+			return fmt.Errorf("threshold must not be greater than upper")
+		}
+	case OpInSet:
+		if e.Metric != metricValuesInSet {
+			return fmt.Errorf("operator %s only applies to metric %s", OpInSet, metricValuesInSet)
+		}
+		if e.ColumnName == "" || len(e.Values) == 0 {
+			return fmt.Errorf("operator %s requires a column and a value set", OpInSet)
+		}
+	default:
+		return fmt.Errorf("unsupported operator: %s", e.Operator)
+	}
+	if e.Metric == metricValuesInSet && e.Operator != OpInSet {
+		return fmt.Errorf("metric %s requires operator %s", metricValuesInSet, OpInSet)
+	}
+
+	switch e.Severity {
+	case SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("unsupported severity: %s", e.Severity)
+	}
+	return nil
+}
+
+// ExpectationVerdict 单个期望的判定结果
+type ExpectationVerdict struct {
+	ExpectationID string              `json:"expectationId"`
+	ColumnName    string              `json:"columnName"`
+	Metric        string              `json:"metric"`
+	Operator      ExpectationOperator `json:"operator"`
+	Status        VerdictStatus       `json:"status"`
+	Observed      *float64            `json:"observed,omitempty"`
+	Message       string              `json:"message,omitempty"`
+}
+
+// QualityReport 单次表分析的质量判定
+type QualityReport struct {
+	Score    float64              `json:"score"`  // 0-100，warn 计半分，skipped 不计入
+	Status   VerdictStatus        `json:"status"` // 最差的判定结果
+	Passed   int                  `json:"passed"`
+	Warned   int                  `json:"warned"`
+	Failed   int                  `json:"failed"`
+	Skipped  int                  `json:"skipped"`
+	Verdicts []ExpectationVerdict `json:"verdicts"`
+}
+
+// EvaluateExpectations 基于分析结果判定期望，没有期望时返回 nil
+func EvaluateExpectations(results *AnalysisResultSet, expectations []*Expectation) *QualityReport {
+	if len(expectations) == 0 || results == nil {
+		return nil
+	}
+
+	report := &QualityReport{
+		Status:   VerdictPass,
+		Verdicts: make([]ExpectationVerdict, 0, len(expectations)),
+	}
+	for _, expectation := range expectations {
+		verdict := evaluateExpectation(results, expectation)
+		switch verdict.Status {
+		case VerdictPass:
+			report.Passed++
+		case VerdictWarn:
+			report.Warned++
+			if report.Status == VerdictPass {
+				report.Status = VerdictWarn
+			}
+		case VerdictFail:
+			report.Failed++
+			report.Status = VerdictFail
+		default:
+			report.Skipped++
+		}
+		report.Verdicts = append(report.Verdicts, verdict)
+	}
+
+	evaluated := report.Passed + report.Warned + report.Failed
+	if evaluated == 0 {
+		report.Status = VerdictSkipped
+		return report
+	}
+	report.Score = math.Round((float64(report.Passed)+0.5*float64(report.Warned))/float64(evaluated)*10000) / 100
+	return report
+}
+
+// evaluateExpectation 判定单个期望
+func evaluateExpectation(results *AnalysisResultSet, expectation *Expectation) ExpectationVerdict {
+	verdict := ExpectationVerdict{
+		ExpectationID: expectation.ID,
+		ColumnName:    expectation.ColumnName,
+		Metric:        expectation.Metric,
+		Operator:      expectation.Operator,
+	}
+	violated := VerdictFail
+	if expectation.Severity == SeverityWarning {
+		violated = VerdictWarn
+	}
+
+	if expectation.Operator == OpInSet {
+		return evaluateValueSet(results, expectation, verdict, violated)
+	}
+
+	observed, err := lookupMetric(results, expectation.ColumnName, expectation.Metric)
+	if err != nil {
+		verdict.Status = VerdictSkipped
+		verdict.Message = err.Error()
+		return verdict
+	}
+	verdict.Observed = &observed
+
+	if compareMetric(observed, expectation) {
+		verdict.Status = VerdictPass
+	} else {
+		verdict.Status = violated
+		verdict.Message = fmt.Sprintf("observed %v, expected %s", observed, describeExpectation(expectation))
+	}
+	return verdict
+}
+
+// evaluateValueSet 根据高频值判定取值集合，高频值均在集合内但未覆盖全部取值时判定为 warn
+func evaluateValueSet(results *AnalysisResultSet, expectation *Expectation, verdict ExpectationVerdict, violated VerdictStatus) ExpectationVerdict {
+	raw, err := lookupRuleOutput(results, "top_values", expectation.ColumnName)
+	if err != nil {
+		verdict.Status = VerdictSkipped
+		verdict.Message = err.Error()
+		return verdict
+	}
+	var frequencies []ValueFrequency
+	if err := json.Unmarshal(raw, &frequencies); err != nil {
+		verdict.Status = VerdictSkipped
+		verdict.Message = fmt.Sprintf("invalid top_values result: %s", err.Error())
+		return verdict
+	}
+
+	allowed := make(map[string]bool, len(expectation.Values))
+	for _, value := range expectation.Values {
+		allowed[value] = true
+	}
+
+	var outside []string
+	var outsidePercentage float64
+	for _, frequency := range frequencies {
+		// NULL 由非空率期望约束，这里不参与判定
+		if frequency.Value == nil || allowed[*frequency.Value] {
+			continue
+		}
+		outside = append(outside, *frequency.Value)
+		outsidePercentage += frequency.Percentage
+	}
+	observed := outsidePercentage / 100
+	verdict.Observed = &observed
+
+	if len(outside) > 0 {
+		verdict.Status = violated
+		verdict.Message = fmt.Sprintf("values outside set: %s", strings.Join(outside, ", "))
+		return verdict
+	}
+
+	verdict.Status = VerdictPass
+	if distinct, err := lookupMetric(results, expectation.ColumnName, "distinct_count.distinct_count"); err != nil || int(distinct) > len(frequencies) {
+		verdict.Status = VerdictWarn
+		verdict.Message = "only the most frequent values were checked"
+	}
+	return verdict
+}
+
+// lookupRuleOutput 返回规则在表级或指定列上的输出（JSON）
+func lookupRuleOutput(results *AnalysisResultSet, ruleName, columnName string) (json.RawMessage, error) {
+	rule, ok := results.Rules[ruleName]
+	if !ok {
+		return nil, fmt.Errorf("rule %s was not executed", ruleName)
+	}
+	if rule.Status != RuleStatusSuccess {
+		return nil, fmt.Errorf("rule %s %s: %s", ruleName, rule.Status, rule.Error)
+	}
+
+	output := rule.Table
+	if columnName != "" {
+		value, ok := rule.Columns[columnName]
+		if message, failed := rule.ColumnErrors[columnName]; !ok && failed {
+			return nil, fmt.Errorf("rule %s failed on column %s: %s", ruleName, columnName, message)
+		}
+		if !ok {
+			return nil, fmt.Errorf("rule %s has no result for column %s", ruleName, columnName)
+		}
+		output = value
+	}
+	return json.Marshal(output)
+}
+
+// lookupMetric 按 规则名[.字段...] 解析指标值，布尔值视为 0/1
+func lookupMetric(results *AnalysisResultSet, columnName, metric string) (float64, error) {
+	if alias, ok := expectationMetricAliases[metric]; ok {
+		metric = alias
+	}
+	path := strings.Split(metric, ".")
+
+	raw, err := lookupRuleOutput(results, path[0], columnName)
+	if err != nil {
+		return 0, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, err
+	}
+
+	for _, field := range path[1:] {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, fmt.Errorf("metric %s not found", metric)
+		}
+		if value, ok = object[field]; !ok {
+			return 0, fmt.Errorf("metric %s not found", metric)
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case nil:
+		return 0, fmt.Errorf("metric %s is null", metric)
+	}
+	return 0, fmt.Errorf("metric %s is not numeric", metric)
+}
+
+// compareMetric 判断指标是否满足期望
+func compareMetric(observed float64, expectation *Expectation) bool {
+	switch expectation.Operator {
+	case OpGreaterOrEqual:
+		return observed >= expectation.Threshold
+	case OpLessOrEqual:
+		return observed <= expectation.Threshold
+	case OpGreater:
+		return observed > expectation.Threshold
+	case OpLess:
+		return observed < expectation.Threshold
+	case OpEqual:
+		return observed == expectation.Threshold
+	case OpNotEqual:
+		return observed != expectation.Threshold
+	case OpBetween:
+		return observed >= expectation.Threshold && observed <= expectation.Upper
+	}
+	return false
+}
+
+// describeExpectation 生成期望的可读描述
+func describeExpectation(expectation *Expectation) string {
+	if expectation.Operator == OpBetween {
+		return fmt.Sprintf("between %v and %v", expectation.Threshold, expectation.Upper)
+	}
+	return fmt.Sprintf("%s %v", expectation.Operator, expectation.Threshold)
+}
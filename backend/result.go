@@ -0,0 +1,166 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// AnalysisResultVersion 当前分析结果结构版本，未带版本号的历史结果视为版本 1
+const AnalysisResultVersion = 2
+
+// RuleStatus 单个规则的执行状态
+type RuleStatus string
+
+const (
+	RuleStatusSuccess RuleStatus = "success"
+	RuleStatusFailed  RuleStatus = "failed"
+	RuleStatusSkipped RuleStatus = "skipped" // 规则不存在等原因未执行
+)
+
+// RuleResult 单个规则的执行结果
+// 按列统计的规则结果放在 Columns 中，其余（如行数、重复行、外键孤儿）放在 Table 中
+type RuleResult struct {
+	Status  RuleStatus             `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Table   interface{}            `json:"table,omitempty"`   // 表级指标
+	Columns map[string]interface{} `json:"columns,omitempty"` // 列级指标，按列名索引
+	Sampled bool                   `json:"sampled,omitempty"` // 是否基于采样数据计算
+	// ColumnErrors 逐列执行的规则中失败的列及错误信息，这些列不在 Columns 中
+	ColumnErrors map[string]string `json:"columnErrors,omitempty"`
+}
+
+// columnOutput 逐列执行、允许部分列失败的规则输出，Values 为成功列的映射
+type columnOutput struct {
+	Values interface{}
+	Errors map[string]string
+}
+
+// AnalysisResultSet 一次表分析的结果
+type AnalysisResultSet struct {
+	Version int                    `json:"version"`
+	Error   string                 `json:"error,omitempty"` // 整体失败（如连接失败、超时）时的错误信息
+	Rules   map[string]*RuleResult `json:"rules"`
+	// Sampling 采样分析时的样本信息，全表分析时为空
+	Sampling *SamplingInfo `json:"sampling,omitempty"`
+	// Partitioning 分区分析时的分区信息，整表分析时为空
+	Partitioning *PartitionInfo `json:"partitioning,omitempty"`
+	// Incremental 增量分析时的水位信息
+	Incremental *IncrementalInfo `json:"incremental,omitempty"`
+}
+
+// NewAnalysisResultSet 创建当前版本的空结果
+func NewAnalysisResultSet() *AnalysisResultSet {
+	return &AnalysisResultSet{
+		Version: AnalysisResultVersion,
+		Rules:   make(map[string]*RuleResult),
+	}
+}
+
+// SetRuleOutput 记录规则执行成功的输出，以字符串为键的映射视为列级指标
+func (s *AnalysisResultSet) SetRuleOutput(ruleName string, output interface{}) {
+	result := &RuleResult{Status: RuleStatusSuccess}
+	if partial, ok := output.(*columnOutput); ok {
+		result.ColumnErrors = partial.Errors
+		output = partial.Values
+	}
+
+	value := reflect.ValueOf(output)
+	if value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String {
+		result.Columns = make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.Columns[iter.Key().String()] = iter.Value().Interface()
+		}
+	} else {
+		result.Table = output
+	}
+
+	s.Rules[ruleName] = result
+}
+
+// SetRuleError 记录规则的失败或跳过状态
+func (s *AnalysisResultSet) SetRuleError(ruleName string, status RuleStatus, err error) {
+	s.Rules[ruleName] = &RuleResult{
+		Status: status,
+		Error:  err.Error(),
+	}
+}
+
+// legacyTableScopedRules 历史结果中输出为对象但属于表级指标的规则
+var legacyTableScopedRules = map[string]bool{
+	"duplicates": true,
+}
+
+// decodeAnalysisResultSet 解析存储的结果，兼容版本 1 的 规则名 -> 原始输出 结构
+func decodeAnalysisResultSet(data []byte) (*AnalysisResultSet, error) {
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Version >= 2 {
+		var set AnalysisResultSet
+		if err := json.Unmarshal(data, &set); err != nil {
+			return nil, err
+		}
+		if set.Rules == nil {
+			set.Rules = make(map[string]*RuleResult)
+		}
+		return &set, nil
+	}
+
+	var legacy map[string]json.RawMessage
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	set := NewAnalysisResultSet()
+	for name, raw := range legacy {
+		// 版本 1 中整体失败的结果只有顶层 error 字段
+		if name == "error" {
+			var message string
+			if err := json.Unmarshal(raw, &message); err != nil {
+				return nil, fmt.Errorf("invalid legacy error: %w", err)
+			}
+			set.Error = message
+			continue
+		}
+
+		result, err := decodeLegacyRuleResult(name, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid legacy result %s: %w", name, err)
+		}
+		set.Rules[name] = result
+	}
+	return set, nil
+}
+
+// decodeLegacyRuleResult 解析版本 1 中单个规则的输出
+func decodeLegacyRuleResult(ruleName string, raw json.RawMessage) (*RuleResult, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		var table interface{}
+		if err := json.Unmarshal(raw, &table); err != nil {
+			return nil, err
+		}
+		return &RuleResult{Status: RuleStatusSuccess, Table: table}, nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	// 版本 1 中失败的规则记录为 {"error": "..."}
+	if message, ok := object["error"].(string); ok && len(object) == 1 {
+		return &RuleResult{Status: RuleStatusFailed, Error: message}, nil
+	}
+	if legacyTableScopedRules[ruleName] {
+		return &RuleResult{Status: RuleStatusSuccess, Table: object}, nil
+	}
+	return &RuleResult{Status: RuleStatusSuccess, Columns: object}, nil
+}
@@ -0,0 +1,271 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RuleParameterType 规则参数类型
+type RuleParameterType string
+
+const (
+	ParamInteger    RuleParameterType = "integer"
+	ParamNumber     RuleParameterType = "number"
+	ParamBoolean    RuleParameterType = "boolean"
+	ParamString     RuleParameterType = "string"
+	ParamStringList RuleParameterType = "string_list"
+	ParamEnum       RuleParameterType = "enum"
+	ParamJSON       RuleParameterType = "json" // 结构化参数，由规则自行解码
+)
+
+// RuleParameter 规则参数定义，前端据此渲染表单
+type RuleParameter struct {
+	Name        string            `json:"name"`
+	Type        RuleParameterType `json:"type"`
+	Description string            `json:"description"`
+	Default     interface{}       `json:"default,omitempty"`
+	Required    bool              `json:"required"`
+	Min         *float64          `json:"min,omitempty"`     // 数值参数的下限
+	Max         *float64          `json:"max,omitempty"`     // 数值参数的上限
+	Options     []string          `json:"options,omitempty"` // 枚举参数的可选值
+}
+
+// RuleSchema 规则及其参数定义
+type RuleSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  []RuleParameter `json:"parameters"`
+}
+
+// paramBound 构造数值参数的上下限
+func paramBound(value float64) *float64 {
+	return &value
+}
+
+// RuleParams 校验并补全默认值后的规则参数
+type RuleParams map[string]interface{}
+
+// Int 读取整数参数，缺失时返回 fallback
+func (p RuleParams) Int(name string, fallback int) int {
+	if value, ok := p[name].(int); ok {
+		return value
+	}
+	return fallback
+}
+
+// Float 读取数值参数，缺失时返回 fallback
+func (p RuleParams) Float(name string, fallback float64) float64 {
+	if value, ok := p[name].(float64); ok {
+		return value
+	}
+	return fallback
+}
+
+// Bool 读取布尔参数，缺失时返回 fallback
+func (p RuleParams) Bool(name string, fallback bool) bool {
+	if value, ok := p[name].(bool); ok {
+		return value
+	}
+	return fallback
+}
+
+// String 读取字符串或枚举参数，缺失时返回 fallback
+func (p RuleParams) String(name string, fallback string) string {
+	if value, ok := p[name].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// StringList 读取字符串列表参数，缺失时返回 fallback
+func (p RuleParams) StringList(name string, fallback []string) []string {
+	if value, ok := p[name].([]string); ok && len(value) > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Decode 将结构化参数解码到 target，参数缺失时不修改 target
+func (p RuleParams) Decode(name string, target interface{}) error {
+	value, ok := p[name]
+	if !ok || value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid parameter %s: %w", name, err)
+	}
+	return nil
+}
+
+// ValidateRuleParams 按参数定义校验原始参数：拒绝未知参数，转换类型，检查范围并补全默认值
+func ValidateRuleParams(schema []RuleParameter, raw map[string]interface{}) (RuleParams, error) {
+	definitions := make(map[string]RuleParameter, len(schema))
+	for _, definition := range schema {
+		definitions[definition.Name] = definition
+	}
+
+	var unknown []string
+	for name := range raw {
+		if _, ok := definitions[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parameters: %s", strings.Join(unknown, ", "))
+	}
+
+	params := make(RuleParams, len(schema))
+	for _, definition := range schema {
+		value, ok := raw[definition.Name]
+		if !ok || value == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("parameter %s is required", definition.Name)
+			}
+			if definition.Default != nil {
+				params[definition.Name] = definition.Default
+			}
+			continue
+		}
+
+		converted, err := convertRuleParam(definition, value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", definition.Name, err)
+		}
+		params[definition.Name] = converted
+	}
+
+	return params, nil
+}
+
+// convertRuleParam 将前端或 JSON 解码得到的值转换为参数类型
+func convertRuleParam(definition RuleParameter, value interface{}) (interface{}, error) {
+	switch definition.Type {
+	case ParamInteger:
+		number, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		if number != math.Trunc(number) {
+			return nil, fmt.Errorf("expected integer, got %v", value)
+		}
+		if err := checkParamRange(definition, number); err != nil {
+			return nil, err
+		}
+		return int(number), nil
+	case ParamNumber:
+		number, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkParamRange(definition, number); err != nil {
+			return nil, err
+		}
+		return number, nil
+	case ParamBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("expected boolean, got %q", v)
+			}
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("expected boolean, got %T", value)
+	case ParamString:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+		return v, nil
+	case ParamEnum:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+		for _, option := range definition.Options {
+			if v == option {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("value %q not in %s", v, strings.Join(definition.Options, ", "))
+	case ParamStringList:
+		return toStringList(value)
+	case ParamJSON:
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %s", definition.Type)
+}
+
+// toFloat 将数值或数字字符串转换为 float64
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected number, got %q", v)
+		}
+		return parsed, nil
+	}
+	return 0, fmt.Errorf("expected number, got %T", value)
+}
+
+// toStringList 接受字符串数组或逗号分隔的字符串，去除空白项
+func toStringList(value interface{}) ([]string, error) {
+	var items []string
+	switch v := value.(type) {
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string list, got element %T", item)
+			}
+			items = append(items, s)
+		}
+	case string:
+		items = strings.Split(v, ",")
+	default:
+		return nil, fmt.Errorf("expected string list, got %T", value)
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// checkParamRange 检查数值参数是否在上下限内
+func checkParamRange(definition RuleParameter, value float64) error {
+	if definition.Min != nil && value < *definition.Min {
+		return fmt.Errorf("must be >= %v", *definition.Min)
+	}
+	if definition.Max != nil && value > *definition.Max {
+		return fmt.Errorf("must be <= %v", *definition.Max)
+	}
+	return nil
+}
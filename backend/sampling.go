@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+)
+
+// defaultSamplingSeed 未指定种子时使用的固定种子，保证同一次分析的各个查询读取相同的样本
+const defaultSamplingSeed = 42
+
+// SamplingConfig 任务的采样配置，为 nil 时全表扫描
+type SamplingConfig struct {
+	Percent float64 `json:"percent"`        // 采样百分比 (0, 100)
+	Seed    int64   `json:"seed,omitempty"` // 随机种子，0 表示使用默认种子
+}
+
+// Validate 校验采样配置
+func (c *SamplingConfig) Validate() error {
+	if c.Percent <= 0 || c.Percent >= 100 {
+		return fmt.Errorf("sampling percent must be between 0 and 100")
+	}
+	if c.Seed < 0 || c.Seed > math.MaxUint32 {
+		return fmt.Errorf("sampling seed must be between 0 and %d", uint32(math.MaxUint32))
+	}
+	return nil
+}
+
+// seed 返回实际使用的随机种子
+func (c *SamplingConfig) seed() int64 {
+	if c.Seed == 0 {
+		return defaultSamplingSeed
+	}
+	return c.Seed
+}
+
+// SamplingInfo 采样分析结果的说明
+type SamplingInfo struct {
+	Percent    float64 `json:"percent"`
+	Seed       int64   `json:"seed"`
+	SampleRows int64   `json:"sampleRows"`
+	// EstimatedError 比例类指标（非空率、占比等）95% 置信度下的最大误差（绝对值，0-1）
+	// 按简单随机抽样估算，块采样（PostgreSQL/SQL Server 的 SYSTEM）的实际误差可能更大
+	EstimatedError float64 `json:"estimatedError"`
+}
+
+// samplingRule 支持在采样数据上执行的规则
+// 未实现该接口的规则（如重复检测、外键孤儿、自定义 SQL）始终全表执行
+type samplingRule interface {
+	SupportsSampling() bool
+}
+
+type samplingContextKey struct{}
+
+// WithSampling 将采样配置附加到 context，sampling 为 nil 时取消采样
+func WithSampling(ctx context.Context, sampling *SamplingConfig) context.Context {
+	return context.WithValue(ctx, samplingContextKey{}, sampling)
+}
+
+// samplingFromContext 返回 context 中的采样配置
+func samplingFromContext(ctx context.Context) *SamplingConfig {
+	sampling, _ := ctx.Value(samplingContextKey{}).(*SamplingConfig)
+	return sampling
+}
+
+// sampledTableRef 返回查询使用的表引用，context 中带采样配置时返回方言的采样表达式，带行过滤条件（分区范围、水位区间）时只读取满足条件的行
+// 返回的表达式可直接跟在 FROM 之后，但不能再附加别名
+func sampledTableRef(ctx context.Context, provider DatabaseProvider, config *DatabaseConfig, tableName string) string {
+	ref := provider.QuoteTableName(config, tableName)
+	if sampling := samplingFromContext(ctx); sampling != nil {
+		ref = provider.TableSource(config, tableName, sampling)
+	}
+	if filter := rowFilterFromContext(ctx); filter != nil {
+		ref = fmt.Sprintf("(SELECT * FROM %s WHERE %s) filtered_rows", ref, filter.condition(provider))
+	}
+	return ref
+}
+
+// scaleSampledRowCount 采样时按比例将样本行数换算为全表行数的估计
+func scaleSampledRowCount(ctx context.Context, rowCount int64) int64 {
+	if sampling := samplingFromContext(ctx); sampling != nil {
+		return int64(math.Round(float64(rowCount) * 100 / sampling.Percent))
+	}
+	return rowCount
+}
+
+// collectSamplingInfo 统计样本行数并估算误差
+func collectSamplingInfo(ctx context.Context, db *sql.DB, config *DatabaseConfig, provider DatabaseProvider, tableName string) (*SamplingInfo, error) {
+	sampling := samplingFromContext(ctx)
+	if sampling == nil {
+		return nil, nil
+	}
+
+	sampleRows, err := provider.ExecuteRowCount(ctx, db, config, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &SamplingInfo{
+		Percent:        sampling.Percent,
+		Seed:           sampling.seed(),
+		SampleRows:     sampleRows,
+		EstimatedError: 1,
+	}
+	if sampleRows > 0 {
+		// 1.96 * sqrt(p(1-p)/n) 在 p=0.5 时取最大值，并做有限总体校正
+		info.EstimatedError = 1.96 * math.Sqrt(0.25/float64(sampleRows)) * math.Sqrt(1-sampling.Percent/100)
+	}
+	return info, nil
+}
@@ -16,15 +16,15 @@ import (
 
 // AnalysisResult 分析结果
 type AnalysisResult struct {
-	ID          string                 `json:"id"`
-	DatabaseID  string                 `json:"databaseId"`
-	TableName   string                 `json:"tableName"`
-	Rules       []string               `json:"rules"`
-	Results     map[string]interface{} `json:"results"`
-	Status      string                 `json:"status"`
-	StartedAt   time.Time              `json:"startedAt"`
-	CompletedAt *time.Time             `json:"completedAt,omitempty"`
-	Duration    time.Duration          `json:"duration"`
+	ID          string             `json:"id"`
+	DatabaseID  string             `json:"databaseId"`
+	TableName   string             `json:"tableName"`
+	Rules       []string           `json:"rules"`
+	Results     *AnalysisResultSet `json:"results"`
+	Status      string             `json:"status"`
+	StartedAt   time.Time          `json:"startedAt"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty"`
+	Duration    time.Duration      `json:"duration"`
 }
 
 // StorageManager 存储管理器
@@ -113,6 +113,7 @@ func createTables(db *sql.DB) error {
 		username TEXT NOT NULL,
 		password TEXT NOT NULL,
 		database TEXT NOT NULL,
+		file_path TEXT NOT NULL DEFAULT '',
 		concurrency INTEGER DEFAULT 5,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
@@ -146,6 +147,20 @@ func createTables(db *sql.DB) error {
 		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
 	);
 
+	-- 增量分析水位表，与 analysis_results 一样按任务和表记录
+	CREATE TABLE IF NOT EXISTS analysis_watermarks (
+		task_id TEXT NOT NULL,
+		table_id TEXT NOT NULL,
+		watermark_column TEXT NOT NULL,
+		watermark TEXT, -- 已分析到的水位（SQL 字面量），NULL 表示尚未分析到任何行
+		signature TEXT NOT NULL, -- 累计规则与列结构的指纹
+		partials TEXT NOT NULL, -- 累计的部分聚合值 JSON
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (task_id, table_id),
+		FOREIGN KEY (task_id) REFERENCES tasks_info(id) ON DELETE CASCADE,
+		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
+	);
+
 	-- 元数据-表
 	CREATE TABLE IF NOT EXISTS metadata_tables (
 		id TEXT PRIMARY KEY,
@@ -169,17 +184,55 @@ func createTables(db *sql.DB) error {
 		column_comment TEXT,
 		column_ordinal INTEGER NOT NULL,
 		column_type TEXT,
+		nullable BOOLEAN NOT NULL DEFAULT 1,
+		default_value TEXT,
+		char_length INTEGER,
+		numeric_precision INTEGER,
+		numeric_scale INTEGER,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		UNIQUE(table_id, column_name),
 		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
 	);
+
+	-- 元数据-约束（主键、唯一约束、外键），列名以 JSON 数组存储
+	CREATE TABLE IF NOT EXISTS metadata_constraints (
+		id TEXT PRIMARY KEY,
+		table_id TEXT NOT NULL,
+		constraint_name TEXT NOT NULL,
+		constraint_type TEXT NOT NULL,
+		column_names TEXT NOT NULL,
+		referenced_table TEXT,
+		referenced_columns TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(table_id, constraint_type, constraint_name),
+		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
+	);
+
+	-- 元数据-索引，列名以 JSON 数组存储
+	CREATE TABLE IF NOT EXISTS metadata_indexes (
+		id TEXT PRIMARY KEY,
+		table_id TEXT NOT NULL,
+		index_name TEXT NOT NULL,
+		index_type TEXT,
+		is_unique BOOLEAN NOT NULL DEFAULT 0,
+		is_primary BOOLEAN NOT NULL DEFAULT 0,
+		is_partial BOOLEAN NOT NULL DEFAULT 0,
+		column_names TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(table_id, index_name),
+		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
+	);
 	-- 任务信息表
 	CREATE TABLE IF NOT EXISTS tasks_info (
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		description TEXT,
 		status TEXT NOT NULL DEFAULT 'active',
+		rules TEXT,
+		sampling TEXT, -- 采样配置 JSON，NULL 表示全表分析
+		partitioning TEXT, -- 分区扫描配置 JSON，NULL 表示整表分析
+		watermark TEXT, -- 增量分析配置 JSON，NULL 表示每次分析全表
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -189,10 +242,61 @@ func createTables(db *sql.DB) error {
 		task_id TEXT NOT NULL,
 		table_id TEXT NOT NULL,
 		tbl_status TEXT NOT NULL DEFAULT '待分析',
+		rules TEXT,
+		quality_status TEXT, -- 最近一次分析的质量判定 pass/warn/fail，NULL 表示未判定
+		quality_score REAL,
 		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (task_id) REFERENCES tasks_info(id) ON DELETE CASCADE,
 		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
 	);
+	-- 自定义格式模式表
+	CREATE TABLE IF NOT EXISTS custom_patterns (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT,
+		expression TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	-- 数据质量期望表
+	CREATE TABLE IF NOT EXISTS expectations (
+		id TEXT PRIMARY KEY,
+		table_id TEXT NOT NULL,
+		column_name TEXT NOT NULL DEFAULT '',
+		metric TEXT NOT NULL,
+		operator TEXT NOT NULL,
+		threshold REAL NOT NULL DEFAULT 0,
+		upper REAL NOT NULL DEFAULT 0,
+		value_set TEXT, -- JSON 数组
+		severity TEXT NOT NULL DEFAULT 'error',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (table_id) REFERENCES metadata_tables(id) ON DELETE CASCADE
+	);
+	-- 质量判定结果表，与 analysis_results 一一对应
+	CREATE TABLE IF NOT EXISTS quality_results (
+		result_id TEXT PRIMARY KEY,
+		task_id TEXT NOT NULL,
+		table_id TEXT NOT NULL,
+		score REAL NOT NULL,
+		status TEXT NOT NULL,
+		report TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(task_id, table_id),
+		FOREIGN KEY (result_id) REFERENCES analysis_results(id) ON DELETE CASCADE
+	);
+	-- 自定义 SQL 断言规则表
+	CREATE TABLE IF NOT EXISTS custom_rules (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT,
+		sql_template TEXT NOT NULL,
+		expectation TEXT NOT NULL,
+		min_value REAL,
+		max_value REAL,
+		column_names TEXT, -- JSON 数组
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 	`
 
 	_, err := db.Exec(createTableSQL)
@@ -207,6 +311,41 @@ func createTables(db *sql.DB) error {
 	// 忽略错误，因为字段可能已经存在
 	db.Exec(alterTableSQL)
 
+	// 确保元数据列的扩展字段存在（对于已存在的表）
+	alterColumnSQLs := []string{
+		`ALTER TABLE metadata_columns ADD COLUMN nullable BOOLEAN NOT NULL DEFAULT 1`,
+		`ALTER TABLE metadata_columns ADD COLUMN default_value TEXT`,
+		`ALTER TABLE metadata_columns ADD COLUMN char_length INTEGER`,
+		`ALTER TABLE metadata_columns ADD COLUMN numeric_precision INTEGER`,
+		`ALTER TABLE metadata_columns ADD COLUMN numeric_scale INTEGER`,
+	}
+	for _, alterSQL := range alterColumnSQLs {
+		db.Exec(alterSQL)
+	}
+
+	// 确保任务规则选择字段存在（JSON 数组，NULL 表示执行全部规则）
+	db.Exec(`ALTER TABLE tasks_info ADD COLUMN rules TEXT`)
+	db.Exec(`ALTER TABLE tasks_tbls ADD COLUMN rules TEXT`)
+
+	// 确保质量判定字段存在
+	db.Exec(`ALTER TABLE tasks_tbls ADD COLUMN quality_status TEXT`)
+	db.Exec(`ALTER TABLE tasks_tbls ADD COLUMN quality_score REAL`)
+
+	// 确保任务采样配置字段存在
+	db.Exec(`ALTER TABLE tasks_info ADD COLUMN sampling TEXT`)
+
+	// 确保任务分区扫描配置字段存在
+	db.Exec(`ALTER TABLE tasks_info ADD COLUMN partitioning TEXT`)
+
+	// 确保任务增量分析配置字段存在
+	db.Exec(`ALTER TABLE tasks_info ADD COLUMN watermark TEXT`)
+
+	// 确保文件类数据源的路径字段存在
+	db.Exec(`ALTER TABLE database_connections ADD COLUMN file_path TEXT NOT NULL DEFAULT ''`)
+
+	// 确保部分索引标记字段存在
+	db.Exec(`ALTER TABLE metadata_indexes ADD COLUMN is_partial BOOLEAN NOT NULL DEFAULT 0`)
+
 	return nil
 }
 
@@ -214,8 +353,8 @@ func createTables(db *sql.DB) error {
 func (sm *StorageManager) SaveConnection(config DatabaseConfig) error {
 	query := `
 	INSERT OR REPLACE INTO database_connections
-	(id, name, type, host, port, username, password, database, concurrency, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	(id, name, type, host, port, username, password, database, file_path, concurrency, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	`
 
 	_, err := sm.db.Exec(query,
@@ -227,6 +366,7 @@ func (sm *StorageManager) SaveConnection(config DatabaseConfig) error {
 		config.Username,
 		config.Password,
 		config.Database,
+		config.FilePath,
 		config.Concurrency,
 	)
 
@@ -236,7 +376,7 @@ func (sm *StorageManager) SaveConnection(config DatabaseConfig) error {
 // GetConnections 获取所有数据库连接配置
 func (sm *StorageManager) GetConnections() ([]DatabaseConfig, error) {
 	query := `
-	SELECT id, name, type, host, port, username, password, database, concurrency
+	SELECT id, name, type, host, port, username, password, database, file_path, concurrency
 	FROM database_connections
 	ORDER BY name
 	`
@@ -259,6 +399,7 @@ func (sm *StorageManager) GetConnections() ([]DatabaseConfig, error) {
 			&config.Username,
 			&config.Password,
 			&config.Database,
+			&config.FilePath,
 			&config.Concurrency,
 		)
 		if err != nil {
@@ -411,7 +552,7 @@ func (sm *StorageManager) GetAnalysisResults(connectionID string) ([]*AnalysisRe
 		if err := json.Unmarshal([]byte(rulesJSON), &result.Rules); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
 		}
-		if err := json.Unmarshal([]byte(resultsJSON), &result.Results); err != nil {
+		if result.Results, err = decodeAnalysisResultSet([]byte(resultsJSON)); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal results: %w", err)
 		}
 
@@ -461,7 +602,7 @@ func (sm *StorageManager) GetAllAnalysisResults() ([]*AnalysisResult, error) {
 		if err := json.Unmarshal([]byte(rulesJSON), &result.Rules); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
 		}
-		if err := json.Unmarshal([]byte(resultsJSON), &result.Results); err != nil {
+		if result.Results, err = decodeAnalysisResultSet([]byte(resultsJSON)); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal results: %w", err)
 		}
 
@@ -503,7 +644,7 @@ func (sm *StorageManager) GetAnalysisResult(resultID string) (*AnalysisResult, e
 	if err := json.Unmarshal([]byte(rulesJSON), &result.Rules); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
 	}
-	if err := json.Unmarshal([]byte(resultsJSON), &result.Results); err != nil {
+	if result.Results, err = decodeAnalysisResultSet([]byte(resultsJSON)); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal results: %w", err)
 	}
 
@@ -513,11 +654,71 @@ func (sm *StorageManager) GetAnalysisResult(resultID string) (*AnalysisResult, e
 
 // DeleteAnalysisResult 删除分析结果
 func (sm *StorageManager) DeleteAnalysisResult(resultID string) error {
+	if _, err := sm.db.Exec(`DELETE FROM quality_results WHERE result_id = ?`, resultID); err != nil {
+		return err
+	}
 	query := `DELETE FROM analysis_results WHERE id = ?`
 	_, err := sm.db.Exec(query, resultID)
 	return err
 }
 
+// GetAnalysisWatermark 获取任务中表的增量分析水位，尚未增量分析过时返回 nil
+func (sm *StorageManager) GetAnalysisWatermark(taskID, tableID string) (*AnalysisWatermark, error) {
+	query := `
+	SELECT task_id, table_id, watermark_column, watermark, signature, partials, datetime(updated_at)
+	FROM analysis_watermarks
+	WHERE task_id = ? AND table_id = ?
+	`
+
+	var watermark AnalysisWatermark
+	var value sql.NullString
+	var partialsJSON string
+	err := sm.db.QueryRow(query, taskID, tableID).Scan(
+		&watermark.TaskID,
+		&watermark.TableID,
+		&watermark.Column,
+		&value,
+		&watermark.Signature,
+		&partialsJSON,
+		&watermark.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	watermark.Watermark = value.String
+	if err := json.Unmarshal([]byte(partialsJSON), &watermark.Partials); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal partials: %w", err)
+	}
+	return &watermark, nil
+}
+
+// SaveAnalysisWatermark 保存增量分析的水位和累计值
+func (sm *StorageManager) SaveAnalysisWatermark(watermark *AnalysisWatermark) error {
+	partials, err := json.Marshal(watermark.Partials)
+	if err != nil {
+		return fmt.Errorf("failed to marshal partials: %w", err)
+	}
+	value := sql.NullString{String: watermark.Watermark, Valid: watermark.Watermark != ""}
+
+	query := `
+	INSERT OR REPLACE INTO analysis_watermarks
+	(task_id, table_id, watermark_column, watermark, signature, partials, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`
+	_, err = sm.db.Exec(query, watermark.TaskID, watermark.TableID, watermark.Column, value, watermark.Signature, string(partials))
+	return err
+}
+
+// DeleteAnalysisWatermark 删除任务中表的增量分析水位，下次分析从头累计
+func (sm *StorageManager) DeleteAnalysisWatermark(taskID, tableID string) error {
+	_, err := sm.db.Exec(`DELETE FROM analysis_watermarks WHERE task_id = ? AND table_id = ?`, taskID, tableID)
+	return err
+}
+
 // EnhancedAnalysisResult 增强的分析结果，包含完整的元数据信息
 type EnhancedAnalysisResult struct {
 	*AnalysisResult
@@ -634,7 +835,7 @@ func (sm *StorageManager) GetTaskTableAnalysisResult(taskID, tableID string) (*A
 	if err := json.Unmarshal([]byte(rulesJSON), &result.Rules); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
 	}
-	if err := json.Unmarshal([]byte(resultsJSON), &result.Results); err != nil {
+	if result.Results, err = decodeAnalysisResultSet([]byte(resultsJSON)); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal results: %w", err)
 	}
 
@@ -655,12 +856,35 @@ type MetadataTableInfo struct {
 
 // MetadataColumnInfo 元数据列信息
 type MetadataColumnInfo struct {
-	ID            string `json:"id"`
-	TableID       string `json:"tableId"`
-	ColumnName    string `json:"columnName"`
-	ColumnComment string `json:"columnComment"`
-	ColumnOrdinal int    `json:"columnOrdinal"`
-	ColumnType    string `json:"columnType"`
+	ID               string  `json:"id"`
+	TableID          string  `json:"tableId"`
+	ColumnName       string  `json:"columnName"`
+	ColumnComment    string  `json:"columnComment"`
+	ColumnOrdinal    int     `json:"columnOrdinal"`
+	ColumnType       string  `json:"columnType"`
+	Nullable         bool    `json:"nullable"`
+	DefaultValue     *string `json:"defaultValue"`
+	CharLength       *int64  `json:"charLength"`
+	NumericPrecision *int64  `json:"numericPrecision"`
+	NumericScale     *int64  `json:"numericScale"`
+}
+
+// MetadataIndexInfo 元数据索引信息
+type MetadataIndexInfo struct {
+	ID      string `json:"id"`
+	TableID string `json:"tableId"`
+	TableIndex
+}
+
+// MetadataConstraintInfo 元数据约束信息
+type MetadataConstraintInfo struct {
+	ID                string   `json:"id"`
+	TableID           string   `json:"tableId"`
+	ConstraintName    string   `json:"constraintName"`
+	ConstraintType    string   `json:"constraintType"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referencedTable"`
+	ReferencedColumns []string `json:"referencedColumns"`
 }
 
 // UpdateDatabaseMetadata 更新数据库元数据
@@ -700,6 +924,20 @@ func (sm *StorageManager) UpdateDatabaseMetadata(connectionID string, tables []*
 			if err != nil {
 				return fmt.Errorf("failed to update columns for table %s: %w", table.TableName, err)
 			}
+			// 更新约束信息，获取失败时保留已有记录
+			if !table.ConstraintsUnknown {
+				err = sm.replaceConstraintsMetadata(tx, existingTable.ID, table.Constraints)
+				if err != nil {
+					return fmt.Errorf("failed to update constraints for table %s: %w", table.TableName, err)
+				}
+			}
+			// 更新索引信息，获取失败时保留已有记录
+			if !table.IndexesUnknown {
+				err = sm.replaceIndexesMetadata(tx, existingTable.ID, table.Indexes)
+				if err != nil {
+					return fmt.Errorf("failed to update indexes for table %s: %w", table.TableName, err)
+				}
+			}
 			// 从映射中移除已处理的表
 			delete(existingTableMap, tableNameLower)
 		} else {
@@ -713,6 +951,16 @@ func (sm *StorageManager) UpdateDatabaseMetadata(connectionID string, tables []*
 			if err != nil {
 				return fmt.Errorf("failed to insert columns for table %s: %w", table.TableName, err)
 			}
+			// 插入约束信息
+			err = sm.replaceConstraintsMetadata(tx, tableID, table.Constraints)
+			if err != nil {
+				return fmt.Errorf("failed to insert constraints for table %s: %w", table.TableName, err)
+			}
+			// 插入索引信息
+			err = sm.replaceIndexesMetadata(tx, tableID, table.Indexes)
+			if err != nil {
+				return fmt.Errorf("failed to insert indexes for table %s: %w", table.TableName, err)
+			}
 		}
 	}
 
@@ -866,7 +1114,9 @@ func (sm *StorageManager) updateColumnsMetadata(tx *sql.Tx, tableID string, colu
 			// 更新现有列
 			query := `
 				UPDATE metadata_columns
-				SET column_comment = ?, column_ordinal = ?, column_type = ?, updated_at = CURRENT_TIMESTAMP
+				SET column_comment = ?, column_ordinal = ?, column_type = ?,
+				    nullable = ?, default_value = ?, char_length = ?, numeric_precision = ?, numeric_scale = ?,
+				    updated_at = CURRENT_TIMESTAMP
 				WHERE id = ?
 			`
 
@@ -874,6 +1124,11 @@ func (sm *StorageManager) updateColumnsMetadata(tx *sql.Tx, tableID string, colu
 				column.ColumnComment,
 				column.ColumnOrdinal,
 				column.ColumnType,
+				column.Nullable,
+				column.DefaultValue,
+				column.CharLength,
+				column.NumericPrecision,
+				column.NumericScale,
 				existingColumn.ID,
 			)
 			if err != nil {
@@ -925,8 +1180,9 @@ func (sm *StorageManager) insertColumnMetadata(tx *sql.Tx, tableID string, colum
 
 	query := `
 		INSERT INTO metadata_columns
-		(id, table_id, column_name, column_comment, column_ordinal, column_type)
-		VALUES (?, ?, ?, ?, ?, ?)
+		(id, table_id, column_name, column_comment, column_ordinal, column_type,
+		 nullable, default_value, char_length, numeric_precision, numeric_scale)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := tx.Exec(query,
@@ -936,11 +1192,93 @@ func (sm *StorageManager) insertColumnMetadata(tx *sql.Tx, tableID string, colum
 		comment,
 		column.ColumnOrdinal,
 		column.ColumnType,
+		column.Nullable,
+		column.DefaultValue,
+		column.CharLength,
+		column.NumericPrecision,
+		column.NumericScale,
 	)
 
 	return err
 }
 
+// replaceConstraintsMetadata 用最新的约束替换表的约束元数据
+func (sm *StorageManager) replaceConstraintsMetadata(tx *sql.Tx, tableID string, constraints []TableConstraint) error {
+	_, err := tx.Exec(`DELETE FROM metadata_constraints WHERE table_id = ?`, tableID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO metadata_constraints
+		(id, table_id, constraint_name, constraint_type, column_names, referenced_table, referenced_columns)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	for _, constraint := range constraints {
+		columnsJSON, err := json.Marshal(constraint.Columns)
+		if err != nil {
+			return err
+		}
+		referencedJSON, err := json.Marshal(constraint.ReferencedColumns)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(query,
+			uuid.New().String(),
+			tableID,
+			constraint.ConstraintName,
+			string(constraint.ConstraintType),
+			string(columnsJSON),
+			constraint.ReferencedTable,
+			string(referencedJSON),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceIndexesMetadata 用最新的索引替换表的索引元数据
+func (sm *StorageManager) replaceIndexesMetadata(tx *sql.Tx, tableID string, indexes []TableIndex) error {
+	_, err := tx.Exec(`DELETE FROM metadata_indexes WHERE table_id = ?`, tableID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO metadata_indexes
+		(id, table_id, index_name, index_type, is_unique, is_primary, is_partial, column_names)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	for _, index := range indexes {
+		columnsJSON, err := json.Marshal(index.Columns)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(query,
+			uuid.New().String(),
+			tableID,
+			index.IndexName,
+			index.IndexType,
+			index.Unique,
+			index.Primary,
+			index.Partial,
+			string(columnsJSON),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // deleteTableMetadata 删除表元数据（级联删除列）
 func (sm *StorageManager) deleteTableMetadata(tx *sql.Tx, tableID string) error {
 	// 删除列信息
@@ -950,6 +1288,20 @@ func (sm *StorageManager) deleteTableMetadata(tx *sql.Tx, tableID string) error
 		return err
 	}
 
+	// 删除约束信息
+	query = `DELETE FROM metadata_constraints WHERE table_id = ?`
+	_, err = tx.Exec(query, tableID)
+	if err != nil {
+		return err
+	}
+
+	// 删除索引信息
+	query = `DELETE FROM metadata_indexes WHERE table_id = ?`
+	_, err = tx.Exec(query, tableID)
+	if err != nil {
+		return err
+	}
+
 	// 删除表信息
 	query = `DELETE FROM metadata_tables WHERE id = ?`
 	_, err = tx.Exec(query, tableID)
@@ -995,7 +1347,8 @@ func (sm *StorageManager) GetMetadataTables(connectionID string) ([]*MetadataTab
 // GetMetadataColumns 获取指定表的列信息
 func (sm *StorageManager) GetMetadataColumns(tableID string) ([]*MetadataColumnInfo, error) {
 	query := `
-		SELECT id, table_id, column_name, column_comment, column_ordinal, column_type
+		SELECT id, table_id, column_name, column_comment, column_ordinal, column_type,
+		       nullable, default_value, char_length, numeric_precision, numeric_scale
 		FROM metadata_columns
 		WHERE table_id = ?
 		ORDER BY column_ordinal
@@ -1010,6 +1363,8 @@ func (sm *StorageManager) GetMetadataColumns(tableID string) ([]*MetadataColumnI
 	var columns []*MetadataColumnInfo
 	for rows.Next() {
 		var column MetadataColumnInfo
+		var defaultValue sql.NullString
+		var charLength, numericPrecision, numericScale sql.NullInt64
 		err := rows.Scan(
 			&column.ID,
 			&column.TableID,
@@ -1017,24 +1372,124 @@ func (sm *StorageManager) GetMetadataColumns(tableID string) ([]*MetadataColumnI
 			&column.ColumnComment,
 			&column.ColumnOrdinal,
 			&column.ColumnType,
+			&column.Nullable,
+			&defaultValue,
+			&charLength,
+			&numericPrecision,
+			&numericScale,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if defaultValue.Valid {
+			column.DefaultValue = &defaultValue.String
+		}
+		column.CharLength = nullableInt(charLength)
+		column.NumericPrecision = nullableInt(numericPrecision)
+		column.NumericScale = nullableInt(numericScale)
 		columns = append(columns, &column)
 	}
 
 	return columns, nil
 }
 
+// GetMetadataConstraints 获取指定表的约束信息
+func (sm *StorageManager) GetMetadataConstraints(tableID string) ([]*MetadataConstraintInfo, error) {
+	query := `
+		SELECT id, table_id, constraint_name, constraint_type, column_names,
+		       COALESCE(referenced_table, ''), COALESCE(referenced_columns, 'null')
+		FROM metadata_constraints
+		WHERE table_id = ?
+		ORDER BY constraint_type, constraint_name
+	`
+
+	rows, err := sm.db.Query(query, tableID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var constraints []*MetadataConstraintInfo
+	for rows.Next() {
+		var constraint MetadataConstraintInfo
+		var columnsJSON, referencedJSON string
+		err := rows.Scan(
+			&constraint.ID,
+			&constraint.TableID,
+			&constraint.ConstraintName,
+			&constraint.ConstraintType,
+			&columnsJSON,
+			&constraint.ReferencedTable,
+			&referencedJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(columnsJSON), &constraint.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal constraint columns: %w", err)
+		}
+		if err := json.Unmarshal([]byte(referencedJSON), &constraint.ReferencedColumns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal referenced columns: %w", err)
+		}
+		constraints = append(constraints, &constraint)
+	}
+
+	return constraints, rows.Err()
+}
+
+// GetMetadataIndexes 获取指定表的索引信息
+func (sm *StorageManager) GetMetadataIndexes(tableID string) ([]*MetadataIndexInfo, error) {
+	query := `
+		SELECT id, table_id, index_name, COALESCE(index_type, ''), is_unique, is_primary, is_partial, column_names
+		FROM metadata_indexes
+		WHERE table_id = ?
+		ORDER BY index_name
+	`
+
+	rows, err := sm.db.Query(query, tableID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []*MetadataIndexInfo
+	for rows.Next() {
+		var index MetadataIndexInfo
+		var columnsJSON string
+		err := rows.Scan(
+			&index.ID,
+			&index.TableID,
+			&index.IndexName,
+			&index.IndexType,
+			&index.Unique,
+			&index.Primary,
+			&index.Partial,
+			&columnsJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(columnsJSON), &index.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal index columns: %w", err)
+		}
+		indexes = append(indexes, &index)
+	}
+
+	return indexes, rows.Err()
+}
+
 // TaskInfo 任务信息结构
 type TaskInfo struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	ID           string           `json:"id"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Status       string           `json:"status"`
+	Rules        []TaskRuleConfig `json:"rules"`        // 为空时执行全部规则
+	Sampling     *SamplingConfig  `json:"sampling"`     // 为空时全表分析
+	Partitioning *PartitionConfig `json:"partitioning"` // 为空时整表分析
+	Watermark    *WatermarkConfig `json:"watermark"`    // 为空时每次分析全表
+	CreatedAt    string           `json:"createdAt"`
+	UpdatedAt    string           `json:"updatedAt"`
 }
 
 // TaskTable 任务表关联结构
@@ -1047,39 +1502,155 @@ type TaskTable struct {
 
 // TaskTableDetail 任务表详细信息（包含连接和表信息）
 type TaskTableDetail struct {
-	ID             string `json:"id"`
-	TaskID         string `json:"taskId"`
-	TableID        string `json:"tableId"`
-	AddedAt        string `json:"addedAt"`
-	TblStatus      string `json:"tblStatus"`
-	ConnectionID   string `json:"connectionId"`
-	ConnectionName string `json:"connectionName"`
-	TableName      string `json:"tableName"`
-	TableComment   string `json:"tableComment"`
-	RowCount       int64  `json:"rowCount"`
-	TableSize      int64  `json:"tableSize"`
-	ColumnCount    int    `json:"columnCount"`
+	ID             string           `json:"id"`
+	TaskID         string           `json:"taskId"`
+	TableID        string           `json:"tableId"`
+	AddedAt        string           `json:"addedAt"`
+	TblStatus      string           `json:"tblStatus"`
+	ConnectionID   string           `json:"connectionId"`
+	ConnectionName string           `json:"connectionName"`
+	TableName      string           `json:"tableName"`
+	TableComment   string           `json:"tableComment"`
+	RowCount       int64            `json:"rowCount"`
+	TableSize      int64            `json:"tableSize"`
+	ColumnCount    int              `json:"columnCount"`
+	Rules          []TaskRuleConfig `json:"rules"`         // 为空时沿用任务的规则选择
+	QualityStatus  string           `json:"qualityStatus"` // 最近一次分析的质量判定，空表示未判定
+	QualityScore   *float64         `json:"qualityScore"`
+}
+
+// marshalRuleConfigs 将规则选择序列化为 JSON，未选择时存储 NULL
+func marshalRuleConfigs(rules []TaskRuleConfig) (sql.NullString, error) {
+	if len(rules) == 0 {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalRuleConfigs 解析存储的规则选择，NULL 或空字符串返回 nil
+func unmarshalRuleConfigs(value sql.NullString) ([]TaskRuleConfig, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var rules []TaskRuleConfig
+	if err := json.Unmarshal([]byte(value.String), &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	return rules, nil
+}
+
+// marshalSamplingConfig 将采样配置序列化为 JSON，全表分析时存储 NULL
+func marshalSamplingConfig(sampling *SamplingConfig) (sql.NullString, error) {
+	if sampling == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(sampling)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalSamplingConfig 解析存储的采样配置，NULL 或空字符串返回 nil
+func unmarshalSamplingConfig(value sql.NullString) (*SamplingConfig, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var sampling SamplingConfig
+	if err := json.Unmarshal([]byte(value.String), &sampling); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sampling: %w", err)
+	}
+	return &sampling, nil
+}
+
+// marshalPartitionConfig 将分区扫描配置序列化为 JSON，整表分析时存储 NULL
+func marshalPartitionConfig(partitioning *PartitionConfig) (sql.NullString, error) {
+	if partitioning == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(partitioning)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalPartitionConfig 解析存储的分区扫描配置，NULL 或空字符串返回 nil
+func unmarshalPartitionConfig(value sql.NullString) (*PartitionConfig, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var partitioning PartitionConfig
+	if err := json.Unmarshal([]byte(value.String), &partitioning); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal partitioning: %w", err)
+	}
+	return &partitioning, nil
+}
+
+// marshalWatermarkConfig 将增量分析配置序列化为 JSON，全表分析时存储 NULL
+func marshalWatermarkConfig(watermark *WatermarkConfig) (sql.NullString, error) {
+	if watermark == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(watermark)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalWatermarkConfig 解析存储的增量分析配置，NULL 或空字符串返回 nil
+func unmarshalWatermarkConfig(value sql.NullString) (*WatermarkConfig, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var watermark WatermarkConfig
+	if err := json.Unmarshal([]byte(value.String), &watermark); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watermark: %w", err)
+	}
+	return &watermark, nil
 }
 
 // SaveTask 保存任务
 func (sm *StorageManager) SaveTask(task *TaskInfo) error {
 	query := `
-		INSERT OR REPLACE INTO tasks_info (id, name, description, status, updated_at)
-		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		INSERT OR REPLACE INTO tasks_info (id, name, description, status, rules, sampling, partitioning, watermark, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 	`
 
 	if task.Description == "" {
 		task.Description = "任务描述"
 	}
 
-	_, err := sm.db.Exec(query, task.ID, task.Name, task.Description, task.Status)
+	rules, err := marshalRuleConfigs(task.Rules)
+	if err != nil {
+		return err
+	}
+	sampling, err := marshalSamplingConfig(task.Sampling)
+	if err != nil {
+		return err
+	}
+	partitioning, err := marshalPartitionConfig(task.Partitioning)
+	if err != nil {
+		return err
+	}
+	watermark, err := marshalWatermarkConfig(task.Watermark)
+	if err != nil {
+		return err
+	}
+
+	_, err = sm.db.Exec(query, task.ID, task.Name, task.Description, task.Status, rules, sampling, partitioning, watermark)
 	return err
 }
 
 // GetAllTasks 获取所有任务
 func (sm *StorageManager) GetAllTasks() ([]*TaskInfo, error) {
 	query := `
-		SELECT id, name, COALESCE(description, ''), status,
+		SELECT id, name, COALESCE(description, ''), status, rules, sampling, partitioning, watermark,
 		       datetime(created_at) as created_at,
 		       datetime(updated_at) as updated_at
 		FROM tasks_info
@@ -1095,17 +1666,34 @@ func (sm *StorageManager) GetAllTasks() ([]*TaskInfo, error) {
 	var tasks []*TaskInfo
 	for rows.Next() {
 		var task TaskInfo
+		var rules, sampling, partitioning, watermark sql.NullString
 		err := rows.Scan(
 			&task.ID,
 			&task.Name,
 			&task.Description,
 			&task.Status,
+			&rules,
+			&sampling,
+			&partitioning,
+			&watermark,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if task.Rules, err = unmarshalRuleConfigs(rules); err != nil {
+			return nil, err
+		}
+		if task.Sampling, err = unmarshalSamplingConfig(sampling); err != nil {
+			return nil, err
+		}
+		if task.Partitioning, err = unmarshalPartitionConfig(partitioning); err != nil {
+			return nil, err
+		}
+		if task.Watermark, err = unmarshalWatermarkConfig(watermark); err != nil {
+			return nil, err
+		}
 		tasks = append(tasks, &task)
 	}
 
@@ -1115,7 +1703,7 @@ func (sm *StorageManager) GetAllTasks() ([]*TaskInfo, error) {
 // GetTask 根据ID获取任务
 func (sm *StorageManager) GetTask(taskID string) (*TaskInfo, error) {
 	query := `
-		SELECT id, name, COALESCE(description, ''), status,
+		SELECT id, name, COALESCE(description, ''), status, rules, sampling, partitioning, watermark,
 		       datetime(created_at) as created_at,
 		       datetime(updated_at) as updated_at
 		FROM tasks_info
@@ -1123,11 +1711,16 @@ func (sm *StorageManager) GetTask(taskID string) (*TaskInfo, error) {
 	`
 
 	var task TaskInfo
+	var rules, sampling, partitioning, watermark sql.NullString
 	err := sm.db.QueryRow(query, taskID).Scan(
 		&task.ID,
 		&task.Name,
 		&task.Description,
 		&task.Status,
+		&rules,
+		&sampling,
+		&partitioning,
+		&watermark,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -1135,6 +1728,18 @@ func (sm *StorageManager) GetTask(taskID string) (*TaskInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	if task.Rules, err = unmarshalRuleConfigs(rules); err != nil {
+		return nil, err
+	}
+	if task.Sampling, err = unmarshalSamplingConfig(sampling); err != nil {
+		return nil, err
+	}
+	if task.Partitioning, err = unmarshalPartitionConfig(partitioning); err != nil {
+		return nil, err
+	}
+	if task.Watermark, err = unmarshalWatermarkConfig(watermark); err != nil {
+		return nil, err
+	}
 
 	return &task, nil
 }
@@ -1146,6 +1751,66 @@ func (sm *StorageManager) DeleteTask(taskID string) error {
 	return err
 }
 
+// UpdateTaskRules 更新任务的规则选择，rules 为空时恢复为执行全部规则
+func (sm *StorageManager) UpdateTaskRules(taskID string, rules []TaskRuleConfig) error {
+	value, err := marshalRuleConfigs(rules)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks_info SET rules = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err = sm.db.Exec(query, value, taskID)
+	return err
+}
+
+// UpdateTaskSampling 更新任务的采样配置，sampling 为空时恢复为全表分析
+func (sm *StorageManager) UpdateTaskSampling(taskID string, sampling *SamplingConfig) error {
+	value, err := marshalSamplingConfig(sampling)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks_info SET sampling = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err = sm.db.Exec(query, value, taskID)
+	return err
+}
+
+// UpdateTaskPartitioning 更新任务的分区扫描配置，partitioning 为空时恢复为整表分析
+func (sm *StorageManager) UpdateTaskPartitioning(taskID string, partitioning *PartitionConfig) error {
+	value, err := marshalPartitionConfig(partitioning)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks_info SET partitioning = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err = sm.db.Exec(query, value, taskID)
+	return err
+}
+
+// UpdateTaskWatermark 更新任务的增量分析配置，watermark 为空时恢复为每次分析全表
+func (sm *StorageManager) UpdateTaskWatermark(taskID string, watermark *WatermarkConfig) error {
+	value, err := marshalWatermarkConfig(watermark)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks_info SET watermark = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err = sm.db.Exec(query, value, taskID)
+	return err
+}
+
+// UpdateTaskTableRules 更新任务中单张表的规则选择，rules 为空时沿用任务的规则选择
+func (sm *StorageManager) UpdateTaskTableRules(taskID, taskTableID string, rules []TaskRuleConfig) error {
+	value, err := marshalRuleConfigs(rules)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tasks_tbls SET rules = ? WHERE task_id = ? AND id = ?`
+	_, err = sm.db.Exec(query, value, taskID, taskTableID)
+	return err
+}
+
 // AddTablesToTask 添加表到任务
 func (sm *StorageManager) AddTablesToTask(taskID string, tableIDs []string) error {
 	if len(tableIDs) == 0 {
@@ -1178,7 +1843,8 @@ func (sm *StorageManager) AddTablesToTask(taskID string, tableIDs []string) erro
 func (sm *StorageManager) GetTaskTables(taskID string) ([]*TaskTableDetail, error) {
 	query := `
 		SELECT
-			tt.id, tt.task_id, tt.table_id, COALESCE(tt.tbl_status, '待分析') as tbl_status, datetime(tt.added_at) as added_at,
+			tt.id, tt.task_id, tt.table_id, COALESCE(tt.tbl_status, '待分析') as tbl_status, datetime(tt.added_at) as added_at, tt.rules,
+			COALESCE(tt.quality_status, ''), tt.quality_score,
 			mt.connection_id, dc.name as connection_name,
 			mt.table_name, COALESCE(mt.table_comment, ''),
 			COALESCE(mt.row_count, 0), COALESCE(mt.table_size, 0),
@@ -1199,12 +1865,17 @@ func (sm *StorageManager) GetTaskTables(taskID string) ([]*TaskTableDetail, erro
 	var tables []*TaskTableDetail
 	for rows.Next() {
 		var table TaskTableDetail
+		var rules sql.NullString
+		var qualityScore sql.NullFloat64
 		err := rows.Scan(
 			&table.ID,
 			&table.TaskID,
 			&table.TableID,
 			&table.TblStatus,
 			&table.AddedAt,
+			&rules,
+			&table.QualityStatus,
+			&qualityScore,
 			&table.ConnectionID,
 			&table.ConnectionName,
 			&table.TableName,
@@ -1216,6 +1887,12 @@ func (sm *StorageManager) GetTaskTables(taskID string) ([]*TaskTableDetail, erro
 		if err != nil {
 			return nil, err
 		}
+		if table.Rules, err = unmarshalRuleConfigs(rules); err != nil {
+			return nil, err
+		}
+		if qualityScore.Valid {
+			table.QualityScore = &qualityScore.Float64
+		}
 		tables = append(tables, &table)
 	}
 
@@ -1254,7 +1931,8 @@ func (sm *StorageManager) UpdateTaskTableStatus(taskID, taskTableID, status stri
 func (sm *StorageManager) GetTaskTablesByStatus(taskID, status string) ([]*TaskTableDetail, error) {
 	query := `
 		SELECT
-			tt.id, tt.task_id, tt.table_id, COALESCE(tt.tbl_status, '待分析') as tbl_status, datetime(tt.added_at) as added_at,
+			tt.id, tt.task_id, tt.table_id, COALESCE(tt.tbl_status, '待分析') as tbl_status, datetime(tt.added_at) as added_at, tt.rules,
+			COALESCE(tt.quality_status, ''), tt.quality_score,
 			mt.connection_id, dc.name as connection_name,
 			mt.table_name, COALESCE(mt.table_comment, ''),
 			COALESCE(mt.row_count, 0), COALESCE(mt.table_size, 0),
@@ -1275,12 +1953,17 @@ func (sm *StorageManager) GetTaskTablesByStatus(taskID, status string) ([]*TaskT
 	var tables []*TaskTableDetail
 	for rows.Next() {
 		var table TaskTableDetail
+		var rules sql.NullString
+		var qualityScore sql.NullFloat64
 		err := rows.Scan(
 			&table.ID,
 			&table.TaskID,
 			&table.TableID,
 			&table.TblStatus,
 			&table.AddedAt,
+			&rules,
+			&table.QualityStatus,
+			&qualityScore,
 			&table.ConnectionID,
 			&table.ConnectionName,
 			&table.TableName,
@@ -1292,6 +1975,12 @@ func (sm *StorageManager) GetTaskTablesByStatus(taskID, status string) ([]*TaskT
 		if err != nil {
 			return nil, err
 		}
+		if table.Rules, err = unmarshalRuleConfigs(rules); err != nil {
+			return nil, err
+		}
+		if qualityScore.Valid {
+			table.QualityScore = &qualityScore.Float64
+		}
 		tables = append(tables, &table)
 	}
 
@@ -1363,6 +2052,385 @@ func (sm *StorageManager) GetAllConnectionsWithMetadata() ([]map[string]interfac
 	return result, nil
 }
 
+// SavePattern 保存自定义格式模式，名称已被其他模式使用时返回错误
+func (sm *StorageManager) SavePattern(pattern *PatternDefinition) error {
+	return sm.saveNamedRow("custom_patterns", pattern.ID, pattern.Name, `
+		UPDATE custom_patterns
+		SET name = ?, description = ?, expression = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, `
+		INSERT INTO custom_patterns (name, description, expression, id)
+		VALUES (?, ?, ?, ?)
+	`, pattern.Name, pattern.Description, pattern.Expression, pattern.ID)
+}
+
+// GetPatterns 获取所有自定义格式模式
+func (sm *StorageManager) GetPatterns() ([]*PatternDefinition, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), expression
+		FROM custom_patterns
+		ORDER BY name
+	`
+
+	rows, err := sm.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patterns []*PatternDefinition
+	for rows.Next() {
+		var pattern PatternDefinition
+		if err := rows.Scan(&pattern.ID, &pattern.Name, &pattern.Description, &pattern.Expression); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, &pattern)
+	}
+
+	return patterns, rows.Err()
+}
+
+// GetPattern 根据ID获取自定义格式模式
+func (sm *StorageManager) GetPattern(patternID string) (*PatternDefinition, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), expression
+		FROM custom_patterns
+		WHERE id = ?
+	`
+
+	var pattern PatternDefinition
+	err := sm.db.QueryRow(query, patternID).Scan(&pattern.ID, &pattern.Name, &pattern.Description, &pattern.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pattern, nil
+}
+
+// DeletePattern 删除自定义格式模式
+func (sm *StorageManager) DeletePattern(patternID string) error {
+	query := `DELETE FROM custom_patterns WHERE id = ?`
+	_, err := sm.db.Exec(query, patternID)
+	return err
+}
+
+// SaveExpectation 新建或更新数据质量期望
+func (sm *StorageManager) SaveExpectation(expectation *Expectation) error {
+	valuesJSON, err := json.Marshal(expectation.Values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	query := `
+		INSERT OR REPLACE INTO expectations (id, table_id, column_name, metric, operator, threshold, upper, value_set, severity)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = sm.db.Exec(query,
+		expectation.ID,
+		expectation.TableID,
+		expectation.ColumnName,
+		expectation.Metric,
+		string(expectation.Operator),
+		expectation.Threshold,
+		expectation.Upper,
+		string(valuesJSON),
+		string(expectation.Severity),
+	)
+	return err
+}
+
+// GetExpectations 获取表的数据质量期望
+func (sm *StorageManager) GetExpectations(tableID string) ([]*Expectation, error) {
+	query := `
+		SELECT id, table_id, column_name, metric, operator, threshold, upper, value_set, severity
+		FROM expectations
+		WHERE table_id = ?
+		ORDER BY column_name, created_at
+	`
+
+	rows, err := sm.db.Query(query, tableID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expectations []*Expectation
+	for rows.Next() {
+		var expectation Expectation
+		var operator, severity string
+		var valuesJSON sql.NullString
+		err := rows.Scan(
+			&expectation.ID,
+			&expectation.TableID,
+			&expectation.ColumnName,
+			&expectation.Metric,
+			&operator,
+			&expectation.Threshold,
+			&expectation.Upper,
+			&valuesJSON,
+			&severity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		expectation.Operator = ExpectationOperator(operator)
+		expectation.Severity = ExpectationSeverity(severity)
+		if valuesJSON.Valid && valuesJSON.String != "" {
+			if err := json.Unmarshal([]byte(valuesJSON.String), &expectation.Values); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal values: %w", err)
+			}
+		}
+		expectations = append(expectations, &expectation)
+	}
+
+	return expectations, rows.Err()
+}
+
+// DeleteExpectation 删除数据质量期望
+func (sm *StorageManager) DeleteExpectation(expectationID string) error {
+	query := `DELETE FROM expectations WHERE id = ?`
+	_, err := sm.db.Exec(query, expectationID)
+	return err
+}
+
+// SaveQualityReport 保存分析结果的质量判定，并同步任务表的质量状态；report 为 nil 时清除质量状态
+func (sm *StorageManager) SaveQualityReport(taskID, taskTableID, tableID, resultID string, report *QualityReport) error {
+	tx, err := sm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if report == nil {
+		if _, err := tx.Exec(`DELETE FROM quality_results WHERE task_id = ? AND table_id = ?`, taskID, tableID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE tasks_tbls SET quality_status = NULL, quality_score = NULL WHERE task_id = ? AND id = ?`, taskID, taskTableID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quality report: %w", err)
+	}
+
+	query := `
+		INSERT OR REPLACE INTO quality_results (result_id, task_id, table_id, score, status, report)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query, resultID, taskID, tableID, report.Score, string(report.Status), string(reportJSON)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE tasks_tbls SET quality_status = ?, quality_score = ? WHERE task_id = ? AND id = ?`, string(report.Status), report.Score, taskID, taskTableID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetQualityReport 获取分析结果的质量判定，未判定时返回 nil
+func (sm *StorageManager) GetQualityReport(resultID string) (*QualityReport, error) {
+	var reportJSON string
+	err := sm.db.QueryRow(`SELECT report FROM quality_results WHERE result_id = ?`, resultID).Scan(&reportJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report QualityReport
+	if err := json.Unmarshal([]byte(reportJSON), &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quality report: %w", err)
+	}
+	return &report, nil
+}
+
+// SaveCustomRule 新建或更新自定义 SQL 断言规则，名称已被其他规则使用时返回错误
+func (sm *StorageManager) SaveCustomRule(rule *CustomRuleDefinition) error {
+	columnsJSON, err := json.Marshal(rule.Columns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal columns: %w", err)
+	}
+
+	return sm.saveNamedRow("custom_rules", rule.ID, rule.Name, `
+		UPDATE custom_rules
+		SET name = ?, description = ?, sql_template = ?, expectation = ?, min_value = ?, max_value = ?, column_names = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, `
+		INSERT INTO custom_rules (name, description, sql_template, expectation, min_value, max_value, column_names, id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, rule.Name, rule.Description, rule.SQL, string(rule.Expectation), rule.Min, rule.Max, string(columnsJSON), rule.ID)
+}
+
+// saveNamedRow 按 id 更新或插入名称唯一的记录，名称属于其他记录时返回错误而不覆盖
+// updateQuery 与 insertQuery 使用相同的参数 args
+func (sm *StorageManager) saveNamedRow(table, id, name, updateQuery, insertQuery string, args ...interface{}) error {
+	tx, err := sm.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE name = ? AND id <> ?`, table), name, id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("name %s already exists", name)
+	}
+
+	result, err := tx.Exec(updateQuery, args...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		if _, err := tx.Exec(insertQuery, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCustomRules 获取所有自定义 SQL 断言规则
+func (sm *StorageManager) GetCustomRules() ([]*CustomRuleDefinition, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), sql_template, expectation, min_value, max_value, column_names
+		FROM custom_rules
+		ORDER BY name
+	`
+
+	rows, err := sm.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*CustomRuleDefinition
+	for rows.Next() {
+		rule, err := scanCustomRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// GetCustomRule 根据ID获取自定义 SQL 断言规则
+func (sm *StorageManager) GetCustomRule(ruleID string) (*CustomRuleDefinition, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), sql_template, expectation, min_value, max_value, column_names
+		FROM custom_rules
+		WHERE id = ?
+	`
+
+	return scanCustomRule(sm.db.QueryRow(query, ruleID))
+}
+
+// scanCustomRule 读取一行自定义规则
+func scanCustomRule(row interface{ Scan(...interface{}) error }) (*CustomRuleDefinition, error) {
+	var rule CustomRuleDefinition
+	var expectation string
+	var minValue, maxValue sql.NullFloat64
+	var columnsJSON sql.NullString
+
+	if err := row.Scan(&rule.ID, &rule.Name, &rule.Description, &rule.SQL, &expectation, &minValue, &maxValue, &columnsJSON); err != nil {
+		return nil, err
+	}
+
+	rule.Expectation = AssertionExpectation(expectation)
+	if minValue.Valid {
+		rule.Min = &minValue.Float64
+	}
+	if maxValue.Valid {
+		rule.Max = &maxValue.Float64
+	}
+	if columnsJSON.Valid && columnsJSON.String != "" {
+		if err := json.Unmarshal([]byte(columnsJSON.String), &rule.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal columns: %w", err)
+		}
+	}
+	return &rule, nil
+}
+
+// DeleteCustomRule 删除自定义 SQL 断言规则
+func (sm *StorageManager) DeleteCustomRule(ruleID string) error {
+	query := `DELETE FROM custom_rules WHERE id = ?`
+	_, err := sm.db.Exec(query, ruleID)
+	return err
+}
+
+// RuleReferences 返回按名称引用规则的任务、任务表的规则选择及质量期望，用于拒绝重命名仍在使用的规则
+func (sm *StorageManager) RuleReferences(ruleName string) ([]string, error) {
+	var references []string
+
+	rows, err := sm.db.Query(`
+		SELECT ti.name, COALESCE(mt.table_name, ''), tt.rules
+		FROM tasks_tbls tt
+		JOIN tasks_info ti ON ti.id = tt.task_id
+		LEFT JOIN metadata_tables mt ON mt.id = tt.table_id
+		WHERE tt.rules IS NOT NULL
+		UNION ALL
+		SELECT name, '', rules FROM tasks_info WHERE rules IS NOT NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskName, tableName string
+		var value sql.NullString
+		if err := rows.Scan(&taskName, &tableName, &value); err != nil {
+			return nil, err
+		}
+		rules, err := unmarshalRuleConfigs(value)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if rule.Name != ruleName {
+				continue
+			}
+			if tableName != "" {
+				references = append(references, fmt.Sprintf("task %s table %s", taskName, tableName))
+			} else {
+				references = append(references, fmt.Sprintf("task %s", taskName))
+			}
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 期望指标为 规则名 或 规则名.字段
+	expectationRows, err := sm.db.Query(`
+		SELECT DISTINCT COALESCE(mt.table_name, e.table_id), e.metric
+		FROM expectations e
+		LEFT JOIN metadata_tables mt ON mt.id = e.table_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer expectationRows.Close()
+	for expectationRows.Next() {
+		var tableName, metric string
+		if err := expectationRows.Scan(&tableName, &metric); err != nil {
+			return nil, err
+		}
+		if metric == ruleName || strings.HasPrefix(metric, ruleName+".") {
+			references = append(references, fmt.Sprintf("expectation %s of table %s", metric, tableName))
+		}
+	}
+	return references, expectationRows.Err()
+}
+
 // Close 关闭存储管理器
 func (sm *StorageManager) Close() error {
 	if sm.db != nil {
@@ -20,6 +20,12 @@ const (
 	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
+// TaskRuleConfig 任务选择的规则及其参数
+type TaskRuleConfig struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
 // AnalysisTask 分析任务
 type AnalysisTask struct {
 	ID             string             `json:"id"`
@@ -36,8 +42,37 @@ type AnalysisTask struct {
 	TaskID         string             `json:"task_id"`       // 新增：任务ID
 	TableID        string             `json:"table_id"`      // 新增：表ID
 	TaskTableID    string             `json:"task_table_id"` // 新增：任务表关联ID
+	Rules          []TaskRuleConfig   `json:"rules"`         // 要执行的规则，为空时执行全部规则
+	Sampling       *SamplingConfig    `json:"sampling"`      // 采样配置，为空时全表分析
+	Partitioning   *PartitionConfig   `json:"partitioning"`  // 分区扫描配置，为空时整表分析
+	Watermark      *WatermarkConfig   `json:"watermark"`     // 增量分析配置，为空时每次分析全表
+	ParentID       string             `json:"parent_id"`     // 分区子任务所属的表分析任务
+	Partition      *PartitionRange    `json:"partition"`     // 分区子任务的键范围，整表规则子任务为空
 	ctx            context.Context    `json:"-"`
 	cancel         context.CancelFunc `json:"-"`
+	parent         *AnalysisTask      `json:"-"`
+	partitions     *partitionRun      `json:"-"` // 分区执行状态，只在父任务上
+}
+
+// partitionRun 分区执行的表分析任务的状态
+type partitionRun struct {
+	provider  DatabaseProvider
+	columns   []ColumnMetadata
+	ruleNames []string
+	partial   []TaskRuleConfig // 按分区执行后合并的规则
+	info      *PartitionInfo
+	tasks     []*AnalysisTask // 各分区的子任务，整表规则子任务在最后
+	states    []partialState  // 各分区的部分聚合值
+	whole     *AnalysisResultSet
+}
+
+// PartitionStatus 分区子任务的执行状态
+type PartitionStatus struct {
+	Index        int             `json:"index"`
+	Range        *PartitionRange `json:"range,omitempty"` // 整表规则子任务为空
+	Status       TaskStatus      `json:"status"`
+	ErrorMessage string          `json:"error_message"`
+	Duration     int64           `json:"duration"` // 毫秒
 }
 
 // TaskManager 任务管理器
@@ -116,8 +151,12 @@ func (tm *TaskManager) AddTask(task *AnalysisTask) error {
 		return fmt.Errorf("task with ID %s already exists", task.ID)
 	}
 
-	// 为每个任务创建独立的context
-	task.ctx, task.cancel = context.WithCancel(context.Background())
+	// 为每个任务创建独立的context，分区子任务随父任务一起取消
+	parentCtx := context.Background()
+	if task.parent != nil {
+		parentCtx = task.parent.ctx
+	}
+	task.ctx, task.cancel = context.WithCancel(parentCtx)
 	task.Status = TaskStatusPending
 	tm.tasks[task.ID] = task
 
@@ -152,7 +191,7 @@ func (tm *TaskManager) GetTasksByDatabase(databaseID string) []*AnalysisTask {
 
 	var tasks []*AnalysisTask
 	for _, task := range tm.tasks {
-		if task.DatabaseID == databaseID {
+		if task.DatabaseID == databaseID && task.ParentID == "" {
 			tasks = append(tasks, task)
 		}
 	}
@@ -228,6 +267,19 @@ func (tm *TaskManager) executeTask(task *AnalysisTask) {
 	}()
 
 	tm.mu.Lock()
+	// 排队期间被取消的任务，以及父任务已结束（取消或失败）的分区子任务不再执行
+	if task.Status == TaskStatusCancelled || (task.parent != nil && task.parent.Status != TaskStatusRunning) {
+		if task.Status != TaskStatusCancelled {
+			task.Status = TaskStatusCancelled
+			task.ErrorMessage = "所属任务已结束"
+		}
+		if task.cancel != nil {
+			task.cancel()
+			task.cancel = nil
+		}
+		tm.mu.Unlock()
+		return
+	}
 	task.Status = TaskStatusRunning
 	now := time.Now()
 	task.StartedAt = &now
@@ -239,7 +291,36 @@ func (tm *TaskManager) executeTask(task *AnalysisTask) {
 	}
 
 	// 执行真正的分析任务
-	tm.performTableAnalysis(task)
+	switch {
+	case task.parent != nil:
+		tm.performPartitionAnalysis(task)
+	case task.Partitioning != nil:
+		tm.startPartitionedAnalysis(task)
+	case task.Watermark != nil:
+		tm.performIncrementalAnalysis(task)
+	default:
+		tm.performTableAnalysis(task)
+	}
+}
+
+// openTaskDatabase 为任务创建临时的数据库连接，调用方负责关闭
+func (tm *TaskManager) openTaskDatabase(task *AnalysisTask) (*DatabaseManager, error) {
+	tempDBManager := NewDatabaseManager()
+	if err := tempDBManager.Connect(task.DatabaseConfig); err != nil {
+		fmt.Printf("Failed to connect to database for task %s: %v\n", task.ID, err)
+		return nil, fmt.Errorf("数据库连接失败: %s", err.Error())
+	}
+	if tempDBManager.GetDB() == nil {
+		fmt.Printf("Failed to get DB connection for task %s\n", task.ID)
+		tempDBManager.Close()
+		return nil, fmt.Errorf("数据库连接不可用")
+	}
+	if tempDBManager.GetProvider() == nil {
+		fmt.Printf("Failed to resolve database provider for task %s\n", task.ID)
+		tempDBManager.Close()
+		return nil, fmt.Errorf("数据库提供者不可用")
+	}
+	return tempDBManager, nil
 }
 
 // performTableAnalysis 执行真正的表分析
@@ -250,50 +331,39 @@ func (tm *TaskManager) performTableAnalysis(task *AnalysisTask) {
 	// 执行真正的分析
 	if tm.analysisEngine != nil && task.DatabaseConfig != nil {
 		// 为这个任务创建一个临时的数据库连接
-		tempDBManager := NewDatabaseManager()
-		err := tempDBManager.Connect(task.DatabaseConfig)
+		tempDBManager, err := tm.openTaskDatabase(task)
 		if err != nil {
-			fmt.Printf("Failed to connect to database for task %s: %v\n", task.ID, err)
 			tm.mu.Lock()
 			task.Status = TaskStatusFailed
-			task.ErrorMessage = fmt.Sprintf("数据库连接失败: %s", err.Error())
+			task.ErrorMessage = err.Error()
 			tm.mu.Unlock()
 			return
 		}
 		defer tempDBManager.Close()
 
 		db := tempDBManager.GetDB()
-		if db == nil {
-			fmt.Printf("Failed to get DB connection for task %s\n", task.ID)
-			tm.mu.Lock()
-			task.Status = TaskStatusFailed
-			task.ErrorMessage = "数据库连接不可用"
-			tm.mu.Unlock()
-			return
-		}
-
 		provider := tempDBManager.GetProvider()
-		if provider == nil {
-			fmt.Printf("Failed to resolve database provider for task %s\n", task.ID)
-			tm.mu.Lock()
-			task.Status = TaskStatusFailed
-			task.ErrorMessage = "数据库提供者不可用"
-			tm.mu.Unlock()
-			return
-		}
 
 		// 获取分析规则
-		ruleNames := tm.analysisEngine.GetAvailableRules()
-		if len(ruleNames) > 0 {
+		rules := tm.resolveRules(task)
+		if len(rules) > 0 {
+			ruleNames := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				ruleNames = append(ruleNames, rule.Name)
+			}
+
 			// 更新进度为30%
 			tm.updateTaskProgress(task.ID, 30)
 
 			// 为任务创建带120秒超时的context
 			timeoutCtx, timeoutCancel := context.WithTimeout(task.ctx, 120*time.Second)
 			defer timeoutCancel()
+			if task.Sampling != nil {
+				timeoutCtx = WithSampling(timeoutCtx, task.Sampling)
+			}
 
 			// 使用带超时的context执行分析
-			analysisResults, err := tm.analysisEngine.ExecuteAnalysis(timeoutCtx, db, task.TableName, task.DatabaseConfig, provider, ruleNames)
+			analysisResults, err := tm.analysisEngine.ExecuteAnalysis(timeoutCtx, db, task.TableName, task.DatabaseConfig, provider, rules)
 
 			// 更新进度为80%
 			tm.updateTaskProgress(task.ID, 80)
@@ -301,80 +371,15 @@ func (tm *TaskManager) performTableAnalysis(task *AnalysisTask) {
 			tm.mu.Lock()
 			defer tm.mu.Unlock()
 
-			now := time.Now()
-			task.CompletedAt = &now
-			task.Duration = now.Sub(*task.StartedAt)
-
-			// 清理任务context资源
-			if task.cancel != nil {
-				task.cancel()
-				task.cancel = nil
-			}
-
+			errorMessage := ""
 			if err != nil {
-				task.Status = TaskStatusFailed
-				errorMessage := err.Error()
+				errorMessage = err.Error()
 				// 检查是否是超时错误
 				if timeoutCtx.Err() == context.DeadlineExceeded {
 					errorMessage = "分析任务超时（120秒限制）"
 				}
-				task.ErrorMessage = errorMessage
-				task.Result = map[string]interface{}{
-					"table_name": task.TableName,
-					"status":     "failed",
-					"error":      errorMessage,
-				}
-
-				// 保存失败的分析结果到存储管理器
-				if tm.storageManager != nil {
-					result := &AnalysisResult{
-						ID:          fmt.Sprintf("result_%s_%s", task.TableName, now.Format("20060102150405")),
-						DatabaseID:  task.DatabaseID,
-						TableName:   task.TableName,
-						Rules:       ruleNames,
-						Results:     map[string]interface{}{"error": err.Error()},
-						Status:      "failed",
-						StartedAt:   *task.StartedAt,
-						CompletedAt: &now,
-						Duration:    task.Duration,
-					}
-					tm.storageManager.SaveAnalysisResult(task.TaskID, task.TableID, result)
-				}
-
-				// 更新任务表状态为"待分析"
-				if task.TaskID != "" && task.TaskTableID != "" {
-					tm.UpdateTaskTableStatus(task.TaskID, task.TaskTableID, "待分析")
-				}
-			} else {
-				task.Status = TaskStatusCompleted
-				task.Progress = 100
-				task.Result = map[string]interface{}{
-					"table_name": task.TableName,
-					"status":     "completed",
-					"results":    analysisResults,
-				}
-
-				// 保存分析结果到存储管理器
-				if tm.storageManager != nil {
-					result := &AnalysisResult{
-						ID:          fmt.Sprintf("result_%s_%s", task.TableName, now.Format("20060102150405")),
-						DatabaseID:  task.DatabaseID,
-						TableName:   task.TableName,
-						Rules:       ruleNames,
-						Results:     analysisResults,
-						Status:      "completed",
-						StartedAt:   *task.StartedAt,
-						CompletedAt: &now,
-						Duration:    task.Duration,
-					}
-					tm.storageManager.SaveAnalysisResult(task.TaskID, task.TableID, result)
-				}
-
-				// 更新任务表状态为"分析完成"
-				if task.TaskID != "" && task.TaskTableID != "" {
-					tm.UpdateTaskTableStatus(task.TaskID, task.TaskTableID, "分析完成")
-				}
 			}
+			tm.finishTableAnalysis(task, ruleNames, analysisResults, err, errorMessage)
 			return
 		}
 	} else {
@@ -395,6 +400,386 @@ func (tm *TaskManager) performTableAnalysis(task *AnalysisTask) {
 	}
 }
 
+// finishTableAnalysis 记录表分析的最终结果，保存到存储管理器并更新任务表状态，调用方需持有 tm.mu
+// err 不为空时任务失败，errorMessage 为展示给用户的错误信息
+func (tm *TaskManager) finishTableAnalysis(task *AnalysisTask, ruleNames []string, analysisResults *AnalysisResultSet, err error, errorMessage string) {
+	now := time.Now()
+	task.CompletedAt = &now
+	task.Duration = now.Sub(*task.StartedAt)
+
+	// 清理任务context资源
+	if task.cancel != nil {
+		task.cancel()
+		task.cancel = nil
+	}
+
+	if err != nil {
+		task.Status = TaskStatusFailed
+		task.ErrorMessage = errorMessage
+		task.Result = map[string]interface{}{
+			"table_name": task.TableName,
+			"status":     "failed",
+			"error":      errorMessage,
+		}
+
+		// 保存失败的分析结果到存储管理器
+		if tm.storageManager != nil {
+			result := &AnalysisResult{
+				ID:          fmt.Sprintf("result_%s_%s", task.TableName, now.Format("20060102150405")),
+				DatabaseID:  task.DatabaseID,
+				TableName:   task.TableName,
+				Rules:       ruleNames,
+				Results:     &AnalysisResultSet{Version: AnalysisResultVersion, Error: err.Error(), Rules: map[string]*RuleResult{}},
+				Status:      "failed",
+				StartedAt:   *task.StartedAt,
+				CompletedAt: &now,
+				Duration:    task.Duration,
+			}
+			tm.storageManager.SaveAnalysisResult(task.TaskID, task.TableID, result)
+		}
+
+		// 更新任务表状态为"待分析"
+		if task.TaskID != "" && task.TaskTableID != "" {
+			tm.UpdateTaskTableStatus(task.TaskID, task.TaskTableID, "待分析")
+		}
+	} else {
+		task.Status = TaskStatusCompleted
+		task.Progress = 100
+		taskResult := map[string]interface{}{
+			"table_name": task.TableName,
+			"status":     "completed",
+			"results":    analysisResults,
+		}
+		task.Result = taskResult
+
+		// 保存分析结果到存储管理器
+		if tm.storageManager != nil {
+			result := &AnalysisResult{
+				ID:          fmt.Sprintf("result_%s_%s", task.TableName, now.Format("20060102150405")),
+				DatabaseID:  task.DatabaseID,
+				TableName:   task.TableName,
+				Rules:       ruleNames,
+				Results:     analysisResults,
+				Status:      "completed",
+				StartedAt:   *task.StartedAt,
+				CompletedAt: &now,
+				Duration:    task.Duration,
+			}
+			if err := tm.storageManager.SaveAnalysisResult(task.TaskID, task.TableID, result); err == nil {
+				if report := tm.evaluateQuality(task, result); report != nil {
+					taskResult["quality"] = report
+				}
+			}
+		}
+
+		// 更新任务表状态为"分析完成"
+		if task.TaskID != "" && task.TaskTableID != "" {
+			tm.UpdateTaskTableStatus(task.TaskID, task.TaskTableID, "分析完成")
+		}
+	}
+}
+
+// startPartitionedAnalysis 按分区列的键范围将表分析拆分为子任务加入队列，可合并的规则每个分区一个子任务，其余规则一个整表子任务
+// 各子任务完成后由 completePartition 合并结果；没有可合并的规则时按整表分析执行
+func (tm *TaskManager) startPartitionedAnalysis(task *AnalysisTask) {
+	if tm.analysisEngine == nil || task.DatabaseConfig == nil {
+		tm.performTableAnalysis(task)
+		return
+	}
+	rules := tm.resolveRules(task)
+	partial, whole := tm.analysisEngine.SplitPartitionRules(rules)
+	if len(partial) == 0 {
+		tm.performTableAnalysis(task)
+		return
+	}
+	ruleNames := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleNames = append(ruleNames, rule.Name)
+	}
+
+	logger := GetLogger()
+	logger.SetModuleName("TASK_MANAGER")
+
+	fail := func(err error) {
+		logger.LogError("PARTITION", fmt.Sprintf("分区规划失败 - %s: %s", task.TableName, err.Error()))
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		tm.finishTableAnalysis(task, ruleNames, nil, err, fmt.Sprintf("分区规划失败: %s", err.Error()))
+	}
+
+	tempDBManager, err := tm.openTaskDatabase(task)
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer tempDBManager.Close()
+	db := tempDBManager.GetDB()
+	provider := tempDBManager.GetProvider()
+
+	planCtx, planCancel := context.WithTimeout(task.ctx, 120*time.Second)
+	defer planCancel()
+	columns, err := provider.GetTableColumns(planCtx, db, task.DatabaseConfig, task.TableName)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if err := prepareTable(provider, task.DatabaseConfig, task.TableName, rules); err != nil {
+		fail(err)
+		return
+	}
+	column, err := resolvePartitionColumn(planCtx, db, task.DatabaseConfig, provider, task.TableName, columns, task.Partitioning.Column)
+	if err != nil {
+		fail(err)
+		return
+	}
+	ranges, err := planPartitionRanges(planCtx, db, task.DatabaseConfig, provider, task.TableName, column, task.Partitioning.Count)
+	if err != nil {
+		fail(err)
+		return
+	}
+	logger.LogInfo("PARTITION", fmt.Sprintf("分区规划完成 - 表: %s, 分区: %s", task.TableName, describePartitions(provider, ranges)))
+
+	run := &partitionRun{
+		provider:  provider,
+		columns:   columns,
+		ruleNames: ruleNames,
+		partial:   partial,
+		states:    make([]partialState, len(ranges)),
+		info:      &PartitionInfo{Column: column.ColumnName, Count: len(ranges)},
+	}
+	for _, rule := range partial {
+		run.info.MergedRules = append(run.info.MergedRules, rule.Name)
+	}
+	for i := range ranges {
+		run.tasks = append(run.tasks, newPartitionTask(task, fmt.Sprintf("%s_p%d", task.ID, i), &ranges[i], partial))
+	}
+	if len(whole) > 0 {
+		run.tasks = append(run.tasks, newPartitionTask(task, fmt.Sprintf("%s_whole", task.ID), nil, whole))
+	}
+
+	tm.mu.Lock()
+	task.partitions = run
+	task.Progress = 0
+	tm.mu.Unlock()
+
+	for _, subtask := range run.tasks {
+		if err := tm.AddTask(subtask); err != nil {
+			tm.completePartition(subtask, nil, nil, err)
+		}
+	}
+}
+
+// newPartitionTask 创建分区子任务，子任务不关联任务表，结果由父任务统一保存
+func newPartitionTask(parent *AnalysisTask, id string, partition *PartitionRange, rules []TaskRuleConfig) *AnalysisTask {
+	return &AnalysisTask{
+		ID:             id,
+		TableName:      parent.TableName,
+		DatabaseID:     parent.DatabaseID,
+		DatabaseConfig: parent.DatabaseConfig,
+		Rules:          rules,
+		ParentID:       parent.ID,
+		Partition:      partition,
+		parent:         parent,
+	}
+}
+
+// performPartitionAnalysis 执行分区子任务：分区子任务计算可合并规则的部分聚合值，整表规则子任务执行常规分析
+func (tm *TaskManager) performPartitionAnalysis(task *AnalysisTask) {
+	tm.mu.RLock()
+	run := task.parent.partitions
+	tm.mu.RUnlock()
+
+	tempDBManager, err := tm.openTaskDatabase(task)
+	if err != nil {
+		tm.completePartition(task, nil, nil, err)
+		return
+	}
+	defer tempDBManager.Close()
+	db := tempDBManager.GetDB()
+	provider := tempDBManager.GetProvider()
+
+	// 每个子任务单独计算超时，单个分区的查询不受整表数据量影响
+	timeoutCtx, timeoutCancel := context.WithTimeout(task.ctx, 120*time.Second)
+	defer timeoutCancel()
+
+	var state partialState
+	var results *AnalysisResultSet
+	if task.Partition != nil {
+		state, err = tm.analysisEngine.ExecutePartition(WithPartition(timeoutCtx, task.Partition), db, task.TableName, task.DatabaseConfig, provider, run.columns, task.Rules)
+	} else {
+		results, err = tm.analysisEngine.ExecuteAnalysis(timeoutCtx, db, task.TableName, task.DatabaseConfig, provider, task.Rules)
+	}
+	if err != nil && timeoutCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("分区分析超时（120秒限制）: %w", err)
+	}
+	tm.completePartition(task, state, results, err)
+}
+
+// completePartition 记录分区子任务的结果并更新父任务进度，全部子任务结束后合并结果或将父任务标记为失败
+func (tm *TaskManager) completePartition(task *AnalysisTask, state partialState, results *AnalysisResultSet, err error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	now := time.Now()
+	task.CompletedAt = &now
+	if task.StartedAt != nil {
+		task.Duration = now.Sub(*task.StartedAt)
+	}
+	if task.cancel != nil {
+		task.cancel()
+		task.cancel = nil
+	}
+
+	parent := task.parent
+	run := parent.partitions
+	if err != nil {
+		task.Status = TaskStatusFailed
+		task.ErrorMessage = err.Error()
+		GetLogger().LogError("PARTITION", fmt.Sprintf("分区子任务失败 - %s: %s", task.ID, err.Error()))
+	} else {
+		task.Status = TaskStatusCompleted
+		task.Progress = 100
+		if task.Partition != nil {
+			run.states[task.Partition.Index] = state
+		} else {
+			run.whole = results
+		}
+	}
+
+	// 父任务已被取消时不再合并
+	if parent.Status != TaskStatusRunning {
+		return
+	}
+
+	completed, failed := 0, 0
+	for _, subtask := range run.tasks {
+		switch subtask.Status {
+		case TaskStatusCompleted:
+			completed++
+		case TaskStatusFailed, TaskStatusCancelled:
+			failed++
+		}
+	}
+	parent.Progress = float64(completed) * 100 / float64(len(run.tasks))
+	if completed+failed < len(run.tasks) {
+		return
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d partitions failed", failed, len(run.tasks))
+		tm.finishTableAnalysis(parent, run.ruleNames, nil, err, fmt.Sprintf("%d/%d 个分区执行失败，可单独重试失败的分区", failed, len(run.tasks)))
+		return
+	}
+
+	merged := run.whole
+	if merged == nil {
+		merged = NewAnalysisResultSet()
+	}
+	tm.analysisEngine.MergePartitions(parent.ctx, run.provider, run.columns, run.partial, run.states, merged)
+	merged.Partitioning = run.info
+	tm.finishTableAnalysis(parent, run.ruleNames, merged, nil, "")
+}
+
+// RetryTaskPartition 重新执行分区分析中失败的子任务，index 为 GetTaskPartitions 返回的序号
+// 父任务已因分区失败结束时恢复为运行中，重试的子任务全部完成后重新合并结果
+func (tm *TaskManager) RetryTaskPartition(taskID string, index int) error {
+	tm.mu.Lock()
+	parent, exists := tm.tasks[taskID]
+	if !exists || parent.partitions == nil {
+		tm.mu.Unlock()
+		return fmt.Errorf("partitioned task not found")
+	}
+	run := parent.partitions
+	if index < 0 || index >= len(run.tasks) {
+		tm.mu.Unlock()
+		return fmt.Errorf("partition %d not found", index)
+	}
+	subtask := run.tasks[index]
+	if subtask.Status != TaskStatusFailed && subtask.Status != TaskStatusCancelled {
+		tm.mu.Unlock()
+		return fmt.Errorf("partition %d is %s", index, subtask.Status)
+	}
+	switch parent.Status {
+	case TaskStatusCancelled:
+		tm.mu.Unlock()
+		return fmt.Errorf("task was cancelled")
+	case TaskStatusFailed:
+		parent.Status = TaskStatusRunning
+		parent.ErrorMessage = ""
+		parent.CompletedAt = nil
+		parent.ctx, parent.cancel = context.WithCancel(context.Background())
+	}
+
+	// 先恢复为待执行，避免其他子任务结束时将父任务判定为失败
+	delete(tm.tasks, subtask.ID)
+	subtask.Status = TaskStatusPending
+	subtask.ErrorMessage = ""
+	subtask.Progress = 0
+	subtask.StartedAt = nil
+	subtask.CompletedAt = nil
+	tm.mu.Unlock()
+
+	if parent.TaskID != "" && parent.TaskTableID != "" {
+		tm.UpdateTaskTableStatus(parent.TaskID, parent.TaskTableID, "分析中")
+	}
+	if err := tm.AddTask(subtask); err != nil {
+		tm.completePartition(subtask, nil, nil, err)
+		return err
+	}
+	return nil
+}
+
+// GetTaskPartitions 返回分区分析任务各子任务的状态，整表分析的任务返回 nil
+func (tm *TaskManager) GetTaskPartitions(taskID string) []PartitionStatus {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	task, exists := tm.tasks[taskID]
+	if !exists || task.partitions == nil {
+		return nil
+	}
+	statuses := make([]PartitionStatus, 0, len(task.partitions.tasks))
+	for i, subtask := range task.partitions.tasks {
+		statuses = append(statuses, PartitionStatus{
+			Index:        i,
+			Range:        subtask.Partition,
+			Status:       subtask.Status,
+			ErrorMessage: subtask.ErrorMessage,
+			Duration:     subtask.Duration.Milliseconds(),
+		})
+	}
+	return statuses
+}
+
+// resolveRules 返回任务要执行的规则及参数，未选择规则时以默认参数执行全部已注册规则
+func (tm *TaskManager) resolveRules(task *AnalysisTask) []TaskRuleConfig {
+	if len(task.Rules) > 0 {
+		return task.Rules
+	}
+
+	ruleNames := tm.analysisEngine.GetAvailableRules()
+	rules := make([]TaskRuleConfig, 0, len(ruleNames))
+	for _, name := range ruleNames {
+		rules = append(rules, TaskRuleConfig{Name: name})
+	}
+	return rules
+}
+
+// evaluateQuality 按表的数据质量期望判定分析结果，保存判定并同步任务表的质量状态
+func (tm *TaskManager) evaluateQuality(task *AnalysisTask, result *AnalysisResult) *QualityReport {
+	expectations, err := tm.storageManager.GetExpectations(task.TableID)
+	if err != nil {
+		GetLogger().LogError("QUALITY", fmt.Sprintf("读取质量期望失败 - %s: %s", task.TableID, err.Error()))
+		return nil
+	}
+
+	report := EvaluateExpectations(result.Results, expectations)
+	if err := tm.storageManager.SaveQualityReport(task.TaskID, task.TaskTableID, task.TableID, result.ID, report); err != nil {
+		GetLogger().LogError("QUALITY", fmt.Sprintf("保存质量报告失败 - %s: %s", task.ID, err.Error()))
+	}
+	return report
+}
+
 // updateTaskProgress 更新任务进度
 func (tm *TaskManager) updateTaskProgress(taskID string, progress float64) {
 	tm.mu.Lock()
@@ -439,6 +824,10 @@ func (tm *TaskManager) GetTaskStats() map[string]int {
 	}
 
 	for _, task := range tm.tasks {
+		// 分区子任务属于父任务，不单独计数
+		if task.ParentID != "" {
+			continue
+		}
 		stats["total"]++
 		stats[string(task.Status)]++
 	}
@@ -468,7 +857,7 @@ func (tm *TaskManager) UpdateTaskTableStatus(taskID, taskTableID, status string)
 }
 
 // CreateAnalysisTasksForTable 为表创建分析任务
-func (tm *TaskManager) CreateAnalysisTasksForTable(taskID, taskTableID, tableID, tableName, databaseID string, databaseConfig *DatabaseConfig) error {
+func (tm *TaskManager) CreateAnalysisTasksForTable(taskID, taskTableID, tableID, tableName, databaseID string, databaseConfig *DatabaseConfig, rules []TaskRuleConfig, sampling *SamplingConfig, partitioning *PartitionConfig, watermark *WatermarkConfig) error {
 	logger := GetLogger()
 	logger.SetModuleName("TASK_MANAGER")
 
@@ -480,6 +869,10 @@ func (tm *TaskManager) CreateAnalysisTasksForTable(taskID, taskTableID, tableID,
 		TaskID:         taskID,
 		TableID:        tableID,
 		TaskTableID:    taskTableID,
+		Rules:          rules,
+		Sampling:       sampling,
+		Partitioning:   partitioning,
+		Watermark:      watermark,
 	}
 
 	return tm.AddTask(task)
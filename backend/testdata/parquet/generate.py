@@ -0,0 +1,489 @@
+"""Generates the Parquet fixtures used by parquet_reader_test.go.
+
+The writer below is a small, independent implementation of the Parquet format
+(thrift compact protocol, RLE/bit-packing, delta encodings, byte stream split,
+v1 and v2 data pages) so the fixtures do not depend on the reader under test.
+Values are simple functions of the row number; the Go tests recompute them.
+
+Run from this directory: python3 generate.py
+"""
+import gzip
+import struct
+
+# ---------- thrift compact protocol ----------
+STOP, TRUE, FALSE, BYTE, I16, I32, I64, DOUBLE, BINARY, LIST, SET, MAP, STRUCT = range(13)
+
+
+def uvarint(n):
+    out = bytearray()
+    while True:
+        b = n & 0x7f
+        n >>= 7
+        if n:
+            out.append(b | 0x80)
+        else:
+            out.append(b)
+            return bytes(out)
+
+
+def zigzag(n):
+    return ((n << 1) ^ (n >> 63)) & 0xffffffffffffffff
+
+
+class S:
+    """A thrift struct given as (field id, type, value) tuples; None entries are omitted."""
+
+    def __init__(self, *fields):
+        self.fields = [f for f in fields if f is not None]
+
+
+def enc_value(typ, v):
+    if typ in (TRUE, FALSE):
+        return bytes([1 if v else 2])
+    if typ == BYTE:
+        return bytes([v & 0xff])
+    if typ in (I16, I32, I64):
+        return uvarint(zigzag(v))
+    if typ == DOUBLE:
+        return struct.pack('<d', v)
+    if typ == BINARY:
+        if isinstance(v, str):
+            v = v.encode()
+        return uvarint(len(v)) + v
+    if typ == LIST:
+        etyp, items = v
+        n = len(items)
+        head = bytes([(n << 4) | etyp]) if n < 15 else bytes([0xf0 | etyp]) + uvarint(n)
+        return head + b''.join(enc_value(etyp, x) for x in items)
+    if typ == STRUCT:
+        return enc_struct(v)
+    raise ValueError(typ)
+
+
+def enc_struct(s):
+    out = bytearray()
+    last = 0
+    for fid, typ, v in sorted(s.fields, key=lambda f: f[0]):
+        t = typ
+        if typ == 'bool':
+            t = TRUE if v else FALSE
+        delta = fid - last
+        if 0 < delta <= 15:
+            out.append((delta << 4) | t)
+        else:
+            out.append(t)
+            out += uvarint(zigzag(fid))
+        last = fid
+        if typ != 'bool':
+            out += enc_value(typ, v)
+    out.append(STOP)
+    return bytes(out)
+
+
+# ---------- encodings ----------
+PLAIN, PLAIN_DICT, RLE, DBP, DLBA, DBA, RLE_DICT, BSS = 0, 2, 3, 5, 6, 7, 8, 9
+BOOLEAN, INT32, INT64, INT96, FLOAT, DOUBLE_T, BYTE_ARRAY, FIXED = range(8)
+REQUIRED, OPTIONAL, REPEATED = range(3)
+
+
+def bitpack(values, width):
+    acc = 0
+    nbits = 0
+    out = bytearray()
+    for v in values:
+        acc |= (v & ((1 << width) - 1)) << nbits
+        nbits += width
+        while nbits >= 8:
+            out.append(acc & 0xff)
+            acc >>= 8
+            nbits -= 8
+    if nbits:
+        out.append(acc & 0xff)
+    return bytes(out)
+
+
+def rle_hybrid(values, width):
+    """Runs of 8 or more equal values are RLE encoded, the rest bit-packed in groups of 8."""
+    out = bytearray()
+    i = 0
+    n = len(values)
+    byte_width = (width + 7) // 8
+    while i < n:
+        j = i
+        while j < n and values[j] == values[i]:
+            j += 1
+        if j - i >= 8:
+            out += uvarint((j - i) << 1)
+            out += values[i].to_bytes(byte_width, 'little') if byte_width else b''
+            i = j
+        else:
+            k = min(i + 8, n)
+            group = list(values[i:k]) + [0] * (8 - (k - i))
+            out += uvarint((1 << 1) | 1)
+            out += bitpack(group, width)
+            i = k
+    return bytes(out)
+
+
+def delta_binary_packed(values, block=128, miniblocks=4):
+    out = bytearray()
+    out += uvarint(block) + uvarint(miniblocks) + uvarint(len(values))
+    out += uvarint(zigzag(values[0] if values else 0))
+    per = block // miniblocks
+    deltas = [values[i] - values[i - 1] for i in range(1, len(values))]
+    for b in range(0, len(deltas), block):
+        blk = deltas[b:b + block]
+        min_delta = min(blk)
+        out += uvarint(zigzag(min_delta))
+        adjusted = [d - min_delta for d in blk]
+        widths = []
+        bodies = []
+        for m in range(miniblocks):
+            mb = adjusted[m * per:(m + 1) * per]
+            if not mb:
+                widths.append(0)
+                continue
+            w = max(x.bit_length() for x in mb)
+            widths.append(w)
+            bodies.append(bitpack(mb + [0] * (per - len(mb)), w) if w else b'')
+        out += bytes(widths)
+        for body in bodies:
+            out += body
+    return bytes(out)
+
+
+def plain(values, ptype):
+    if ptype == BOOLEAN:
+        return bitpack([1 if v else 0 for v in values], 1)
+    if ptype == INT32:
+        return b''.join(struct.pack('<i', v) for v in values)
+    if ptype == INT64:
+        return b''.join(struct.pack('<q', v) for v in values)
+    if ptype == FLOAT:
+        return b''.join(struct.pack('<f', v) for v in values)
+    if ptype == DOUBLE_T:
+        return b''.join(struct.pack('<d', v) for v in values)
+    if ptype == BYTE_ARRAY:
+        return b''.join(struct.pack('<I', len(v)) + v for v in values)
+    if ptype in (FIXED, INT96):
+        return b''.join(values)
+    raise ValueError(ptype)
+
+
+def encode_values(values, ptype, encoding):
+    if encoding == PLAIN:
+        return plain(values, ptype)
+    if encoding == RLE:
+        body = rle_hybrid([1 if v else 0 for v in values], 1)
+        return struct.pack('<I', len(body)) + body
+    if encoding == DBP:
+        return delta_binary_packed(values)
+    if encoding == DLBA:
+        return delta_binary_packed([len(v) for v in values]) + b''.join(values)
+    if encoding == DBA:
+        prefixes = []
+        suffixes = []
+        prev = b''
+        for v in values:
+            p = 0
+            while p < len(prev) and p < len(v) and prev[p] == v[p]:
+                p += 1
+            prefixes.append(p)
+            suffixes.append(v[p:])
+            prev = v
+        return delta_binary_packed(prefixes) + delta_binary_packed([len(s) for s in suffixes]) + b''.join(suffixes)
+    if encoding == BSS:
+        raw = plain(values, ptype)
+        width = len(raw) // len(values) if values else 0
+        return bytes(raw[i * width + k] for k in range(width) for i in range(len(values)))
+    raise ValueError(encoding)
+
+
+# ---------- compression ----------
+UNCOMPRESSED, SNAPPY, GZIP, ZSTD, LZ4_RAW = 0, 1, 2, 6, 7
+
+
+def snappy_literal(data):
+    out = bytearray(uvarint(len(data)))
+    for i in range(0, len(data), 60000):
+        chunk = data[i:i + 60000]
+        n = len(chunk) - 1
+        if n < 60:
+            out.append(n << 2)
+        else:
+            out.append(61 << 2)
+            out += struct.pack('<H', n)
+        out += chunk
+    return bytes(out)
+
+
+def lz4_literal(data):
+    n = len(data)
+    out = bytearray()
+    if n >= 15:
+        out.append(0xf0)
+        rest = n - 15
+        while rest >= 255:
+            out.append(255)
+            rest -= 255
+        out.append(rest)
+    else:
+        out.append(n << 4)
+    out += data
+    return bytes(out)
+
+
+def zstd_raw(data):
+    """A single-segment zstd frame made of raw (stored) blocks."""
+    out = bytearray(struct.pack('<I', 0xFD2FB528))
+    out += bytes([0xA0]) + struct.pack('<I', len(data))
+    blocks = [data[i:i + (1 << 17)] for i in range(0, len(data), 1 << 17)] or [b'']
+    for i, block in enumerate(blocks):
+        last = 1 if i == len(blocks) - 1 else 0
+        out += (last | len(block) << 3).to_bytes(3, 'little') + block
+    return bytes(out)
+
+
+def compress(codec, data):
+    if codec == UNCOMPRESSED:
+        return data
+    if codec == SNAPPY:
+        return snappy_literal(data)
+    if codec == GZIP:
+        return gzip.compress(data, mtime=0)
+    if codec == ZSTD:
+        return zstd_raw(data)
+    if codec == LZ4_RAW:
+        return lz4_literal(data)
+    raise ValueError(codec)
+
+
+# ---------- file layout ----------
+class Null:
+    """A null whose definition level is below the column's maximum minus one (e.g. a null parent struct)."""
+
+    def __init__(self, level):
+        self.level = level
+
+
+class Column:
+    def __init__(self, name, ptype, repetition=OPTIONAL, converted=None, logical=None, type_length=None,
+                 encoding=PLAIN, dictionary=False, page_version=1, page_size=None, write_stats=True):
+        self.name, self.ptype, self.repetition = name, ptype, repetition
+        self.converted, self.logical, self.type_length = converted, logical, type_length
+        self.encoding, self.dictionary = encoding, dictionary
+        self.page_version, self.page_size, self.write_stats = page_version, page_size, write_stats
+
+    def element(self):
+        return S((1, I32, self.ptype),
+                 (2, I32, self.type_length) if self.type_length else None,
+                 (3, I32, self.repetition),
+                 (4, BINARY, self.name),
+                 (6, I32, self.converted) if self.converted is not None else None,
+                 (10, STRUCT, self.logical) if self.logical is not None else None)
+
+
+def group(name, children, repetition=OPTIONAL, converted=None):
+    return S((3, I32, repetition), (4, BINARY, name), (5, I32, children),
+             (6, I32, converted) if converted is not None else None)
+
+
+def string_logical():
+    return S((1, STRUCT, S()))
+
+
+def definition_level(v, max_def):
+    if isinstance(v, Null):
+        return v.level
+    return max_def if v is not None else max_def - 1
+
+
+def write_chunk(out, col, values, codec, max_def, max_rep, path):
+    """values holds one entry per row; None and Null mark nulls. Repeated columns hold at most one value per row."""
+    start = len(out)
+    present = [v for v in values if v is not None and not isinstance(v, Null)]
+    dict_offset = None
+    dictionary = None
+    if col.dictionary:
+        dictionary = []
+        index = {}
+        for v in present:
+            if v not in index:
+                index[v] = len(dictionary)
+                dictionary.append(v)
+        raw = plain(dictionary, col.ptype)
+        comp = compress(codec, raw)
+        header = S((1, I32, 2), (2, I32, len(raw)), (3, I32, len(comp)),
+                   (7, STRUCT, S((1, I32, len(dictionary)), (2, I32, PLAIN))))
+        dict_offset = len(out)
+        out += enc_struct(header) + comp
+    data_offset = len(out)
+    page_size = col.page_size or max(len(values), 1)
+    for p in range(0, max(len(values), 1), page_size):
+        page = values[p:p + page_size]
+        page_present = [v for v in page if v is not None and not isinstance(v, Null)]
+        if dictionary is not None:
+            width = (len(dictionary) - 1).bit_length()
+            body = bytes([width]) + rle_hybrid([index[v] for v in page_present], width)
+            encoding = RLE_DICT if col.page_version == 2 else PLAIN_DICT
+        else:
+            body = encode_values(page_present, col.ptype, col.encoding)
+            encoding = col.encoding
+        def_levels = rle_hybrid([definition_level(v, max_def) for v in page], max_def.bit_length()) if max_def > 0 else b''
+        rep_levels = rle_hybrid([0] * len(page), max_rep.bit_length()) if max_rep > 0 else b''
+        if col.page_version == 1:
+            raw = b''
+            if max_rep > 0:
+                raw += struct.pack('<I', len(rep_levels)) + rep_levels
+            if max_def > 0:
+                raw += struct.pack('<I', len(def_levels)) + def_levels
+            raw += body
+            comp = compress(codec, raw)
+            header = S((1, I32, 0), (2, I32, len(raw)), (3, I32, len(comp)),
+                       (5, STRUCT, S((1, I32, len(page)), (2, I32, encoding), (3, I32, RLE), (4, I32, RLE))))
+            out += enc_struct(header) + comp
+        else:
+            levels = rep_levels + def_levels
+            comp = compress(codec, body)
+            header = S((1, I32, 3), (2, I32, len(levels) + len(body)), (3, I32, len(levels) + len(comp)),
+                       (8, STRUCT, S((1, I32, len(page)), (2, I32, len(page) - len(page_present)), (3, I32, len(page)),
+                                     (4, I32, encoding), (5, I32, len(def_levels)), (6, I32, len(rep_levels)),
+                                     (7, 'bool', codec != UNCOMPRESSED))))
+            out += enc_struct(header) + levels + comp
+    total = len(out) - start
+    statistics = S((3, I64, len(values) - len(present))) if col.write_stats else None
+    meta = S((1, I32, col.ptype),
+             (2, LIST, (I32, [PLAIN, RLE])),
+             (3, LIST, (BINARY, path)),
+             (4, I32, codec),
+             (5, I64, len(values)),
+             (6, I64, total),
+             (7, I64, total),
+             (9, I64, data_offset),
+             (11, I64, dict_offset) if dict_offset is not None else None,
+             (12, STRUCT, statistics) if statistics is not None else None)
+    return S((2, I64, start), (3, STRUCT, meta))
+
+
+def write_file(name, schema, top_level, leaves, row_groups, codec=UNCOMPRESSED):
+    """schema: elements after the root in depth-first order; leaves: (Column, max_def, max_rep, path) tuples;
+    row_groups: per row group, one list of values for each leaf."""
+    out = bytearray(b'PAR1')
+    groups = []
+    total_rows = 0
+    for rg in row_groups:
+        chunks = [write_chunk(out, col, values, codec, max_def, max_rep, path)
+                  for (col, max_def, max_rep, path), values in zip(leaves, rg)]
+        rows = len(rg[0])
+        groups.append(S((1, LIST, (STRUCT, chunks)), (2, I64, 0), (3, I64, rows)))
+        total_rows += rows
+    root = S((4, BINARY, 'schema'), (5, I32, top_level))
+    meta = S((1, I32, 1), (2, LIST, (STRUCT, [root] + schema)), (3, I64, total_rows),
+             (4, LIST, (STRUCT, groups)), (6, BINARY, 'mole testdata'))
+    footer = enc_struct(meta)
+    out += footer + struct.pack('<I', len(footer)) + b'PAR1'
+    with open(name, 'wb') as f:
+        f.write(out)
+
+
+def flat_file(name, columns, rows, row_group_size, codec=UNCOMPRESSED):
+    """A file of top-level columns; rows maps each column name to a function of the row number."""
+    leaves = [(c, 1 if c.repetition == OPTIONAL else 0, 0, [c.name]) for c in columns]
+    row_groups = []
+    for start in range(0, rows, row_group_size):
+        numbers = range(start, min(start + row_group_size, rows))
+        row_groups.append([[VALUES[c.name](i) for i in numbers] for c in columns])
+    write_file(name, [c.element() for c in columns], len(columns), leaves, row_groups, codec)
+
+
+# column name -> value of row i
+VALUES = {}
+
+
+def utf8(s):
+    return None if s is None else s.encode()
+
+
+# ---------- fixtures ----------
+
+# codec_*.parquet: 40 rows in row groups of 25 and 15, pages of 10 rows; id and name use v1 pages, score v2
+VALUES.update({
+    'id': lambda i: i,
+    'name': lambda i: None if i % 4 == 0 else utf8(['alpha', 'beta', 'gamma'][i % 3]),
+    'score': lambda i: None if i % 5 == 0 else i * 1.5,
+})
+for codec, suffix in [(UNCOMPRESSED, 'uncompressed'), (SNAPPY, 'snappy'), (GZIP, 'gzip'),
+                      (ZSTD, 'zstd'), (LZ4_RAW, 'lz4_raw')]:
+    flat_file('codec_%s.parquet' % suffix, [
+        Column('id', INT64, repetition=REQUIRED, page_size=10),
+        Column('name', BYTE_ARRAY, logical=string_logical(), dictionary=True, page_size=10),
+        Column('score', DOUBLE_T, page_version=2, page_size=10),
+    ], 40, 25, codec)
+
+# encodings_v1.parquet / encodings_v2.parquet: 300 rows, pages of 150 rows; v2 pages are snappy compressed
+VALUES.update({
+    'plain_i32': lambda i: None if i % 7 == 0 else i * 3 - 100,
+    'delta_i32': lambda i: (i * i) % 1000 - 500,
+    'delta_i64': lambda i: None if i % 6 == 0 else (i - 150) * 10 ** 12,
+    'rle_bool': lambda i: None if i % 9 == 0 else (i // 10) % 2 == 0,
+    'plain_bool': lambda i: i % 3 == 0,
+    'dict_str': lambda i: None if i % 8 == 0 else utf8('k%d' % (i % 5)),
+    'dlba_str': lambda i: None if i % 10 == 0 else utf8('s' * (i % 7) + str(i)),
+    'dba_str': lambda i: utf8('prefix/%03d/%s' % (i // 20, 'x' * (i % 3))),
+    'bss_float': lambda i: None if i % 11 == 0 else i * 0.25,
+    'bss_double': lambda i: i / 8 - 10,
+    'fixed': lambda i: bytes([i % 256, i * 7 % 256, 255 - i % 256]),
+    'no_stats': lambda i: None if i % 2 == 0 else i,
+})
+for version, codec in [(1, UNCOMPRESSED), (2, SNAPPY)]:
+    flat_file('encodings_v%d.parquet' % version, [
+        Column('plain_i32', INT32, page_version=version, page_size=150),
+        Column('delta_i32', INT32, repetition=REQUIRED, encoding=DBP, page_version=version, page_size=150),
+        Column('delta_i64', INT64, encoding=DBP, page_version=version, page_size=150),
+        Column('rle_bool', BOOLEAN, encoding=RLE, page_version=version, page_size=150),
+        Column('plain_bool', BOOLEAN, repetition=REQUIRED, page_version=version, page_size=150),
+        Column('dict_str', BYTE_ARRAY, logical=string_logical(), dictionary=True, page_version=version, page_size=150),
+        Column('dlba_str', BYTE_ARRAY, logical=string_logical(), encoding=DLBA, page_version=version, page_size=150),
+        Column('dba_str', BYTE_ARRAY, repetition=REQUIRED, logical=string_logical(), encoding=DBA,
+               page_version=version, page_size=150),
+        Column('bss_float', FLOAT, encoding=BSS, page_version=version, page_size=150),
+        Column('bss_double', DOUBLE_T, repetition=REQUIRED, encoding=BSS, page_version=version, page_size=150),
+        Column('fixed', FIXED, repetition=REQUIRED, type_length=3, page_version=version, page_size=150),
+        Column('no_stats', INT64, page_version=version, page_size=150, write_stats=False),
+    ], 300, 300, codec)
+
+# nested.parquet: 30 rows
+#   id        required int64
+#   addr      optional group { city optional string; zip required int32 }
+#   tags      optional group (LIST) { list repeated group { element optional int32 } }
+#   meta      required group { source required string; score optional double }
+N = 30
+id_column = Column('id', INT64, repetition=REQUIRED, page_size=8)
+city = Column('city', BYTE_ARRAY, logical=string_logical(), page_size=8)
+zip_code = Column('zip', INT32, repetition=REQUIRED, page_size=8)
+element = Column('element', INT32, page_size=8)
+source = Column('source', BYTE_ARRAY, repetition=REQUIRED, logical=string_logical(), page_size=8)
+meta_score = Column('score', DOUBLE_T, page_version=2, page_size=8)
+schema = [
+    id_column.element(),
+    group('addr', 2), city.element(), zip_code.element(),
+    group('tags', 1, converted=3), group('list', 1, repetition=REPEATED), element.element(),
+    group('meta', 2, repetition=REQUIRED), source.element(), meta_score.element(),
+]
+leaves = [
+    (id_column, 0, 0, ['id']),
+    (city, 2, 0, ['addr', 'city']),
+    (zip_code, 1, 0, ['addr', 'zip']),
+    (element, 3, 1, ['tags', 'list', 'element']),
+    (source, 0, 0, ['meta', 'source']),
+    (meta_score, 1, 0, ['meta', 'score']),
+]
+rows = range(N)
+write_file('nested.parquet', schema, 4, leaves, [[
+    [i for i in rows],
+    [Null(0) if i % 5 == 0 else None if i % 3 == 0 else utf8(['Paris', 'Berlin'][i % 2]) for i in rows],
+    [None if i % 5 == 0 else 10000 + i for i in rows],
+    [Null(0) if i % 4 == 0 else i for i in rows],
+    [utf8('src%d' % (i % 2)) for i in rows],
+    [None if i % 2 else i / 2 for i in rows],
+]], GZIP)
@@ -22,6 +22,7 @@ import {
 	DATABASE_TYPES,
 	DEFAULT_DATABASE_TYPE,
 	getDefaultPort,
+	isFileDatabaseType,
 	normalizeDatabaseType,
 } from "@/lib/databaseTypes";
 import { createLogger } from "@/lib/logger";
@@ -50,6 +51,7 @@ export function ConnectionDialog({
 	const databaseId = useId();
 	const usernameId = useId();
 	const passwordId = useId();
+	const filePathId = useId();
 
 	const [formData, setFormData] = useState({
 		name: "",
@@ -59,6 +61,7 @@ export function ConnectionDialog({
 		database: "",
 		username: "",
 		password: "",
+		filePath: "",
 		concurrency: 5,
 	});
 
@@ -73,6 +76,7 @@ export function ConnectionDialog({
 				database: connection.database,
 				username: connection.username,
 				password: connection.password,
+				filePath: connection.filePath ?? "",
 				concurrency: connection.concurrency || 5,
 			});
 		} else {
@@ -84,11 +88,14 @@ export function ConnectionDialog({
 				database: "",
 				username: "",
 				password: "",
+				filePath: "",
 				concurrency: 5,
 			});
 		}
 	}, [connection]);
 
+	const fileBased = isFileDatabaseType(formData.type);
+
 	const handleTypeChange = (type: string) => {
 		const normalized = normalizeDatabaseType(type);
 		const defaultPort = getDefaultPort(normalized);
@@ -159,72 +166,89 @@ export function ConnectionDialog({
 						</Select>
 					</div>
 
-					<div className="grid grid-cols-3 gap-4">
-						<div className="col-span-2 space-y-2">
-							<Label htmlFor={hostId}>主机地址</Label>
-							<Input
-								id={hostId}
-								value={formData.host}
-								onChange={(e) =>
-									setFormData({ ...formData, host: e.target.value })
-								}
-								placeholder="例如: localhost"
-								required
-							/>
-						</div>
+					{fileBased ? (
 						<div className="space-y-2">
-							<Label htmlFor={portId}>端口</Label>
+							<Label htmlFor={filePathId}>文件路径</Label>
 							<Input
-								id={portId}
-								value={formData.port}
+								id={filePathId}
+								value={formData.filePath}
 								onChange={(e) =>
-									setFormData({ ...formData, port: Number(e.target.value) })
+									setFormData({ ...formData, filePath: e.target.value })
 								}
-								placeholder="3306"
+								placeholder="数据库文件，或 CSV/TSV、Parquet 文件所在目录的路径"
 								required
 							/>
 						</div>
-					</div>
+					) : (
+						<>
+							<div className="grid grid-cols-3 gap-4">
+								<div className="col-span-2 space-y-2">
+									<Label htmlFor={hostId}>主机地址</Label>
+									<Input
+										id={hostId}
+										value={formData.host}
+										onChange={(e) =>
+											setFormData({ ...formData, host: e.target.value })
+										}
+										placeholder="例如: localhost"
+										required
+									/>
+								</div>
+								<div className="space-y-2">
+									<Label htmlFor={portId}>端口</Label>
+									<Input
+										id={portId}
+										value={formData.port}
+										onChange={(e) =>
+											setFormData({ ...formData, port: Number(e.target.value) })
+										}
+										placeholder="3306"
+										required
+									/>
+								</div>
+							</div>
 
-					<div className="space-y-2">
-						<Label htmlFor={databaseId}>数据库名称</Label>
-						<Input
-							id={databaseId}
-							value={formData.database}
-							onChange={(e) =>
-								setFormData({ ...formData, database: e.target.value })
-							}
-							placeholder="例如: main_db"
-							required
-						/>
-					</div>
+							<div className="space-y-2">
+								<Label htmlFor={databaseId}>数据库名称</Label>
+								<Input
+									id={databaseId}
+									value={formData.database}
+									onChange={(e) =>
+										setFormData({ ...formData, database: e.target.value })
+									}
+									placeholder="例如: main_db"
+									required
+								/>
+							</div>
 
-					<div className="space-y-2">
-						<Label htmlFor={usernameId}>用户名</Label>
-						<Input
-							id={usernameId}
-							value={formData.username}
-							onChange={(e) =>
-								setFormData({ ...formData, username: e.target.value })
-							}
-							placeholder="数据库用户名"
-							required
-						/>
-					</div>
+							<div className="space-y-2">
+								<Label htmlFor={usernameId}>用户名</Label>
+								<Input
+									id={usernameId}
+									value={formData.username}
+									onChange={(e) =>
+										setFormData({ ...formData, username: e.target.value })
+									}
+									placeholder="数据库用户名"
+									required
+								/>
+							</div>
 
-					<div className="space-y-2">
-						<Label htmlFor={passwordId}>密码</Label>
-						<Input
-							id={passwordId}
-							type="password"
-							value={formData.password}
-							onChange={(e) =>
-								setFormData({ ...formData, password: e.target.value })
-							}
-							placeholder="数据库密码"
-							required
-						/>
-					</div>
+							<div className="space-y-2">
+								<Label htmlFor={passwordId}>密码</Label>
+								<Input
+									id={passwordId}
+									type="password"
+									value={formData.password}
+									onChange={(e) =>
+										setFormData({ ...formData, password: e.target.value })
+									}
+									placeholder="数据库密码"
+									required
+								/>
+							</div>
+						</>
+					)}
 
 					<DialogFooter>
 						<Button
@@ -15,6 +15,7 @@ import {
 import {
 	DATABASE_TYPES,
 	getDefaultPort,
+	isFileDatabaseType,
 	normalizeDatabaseType,
 } from "@/lib/databaseTypes";
 import type { DatabaseConfig } from "@/types";
@@ -39,6 +40,7 @@ export function DatabaseConfigForm({
 	connectionStatus,
 }: DatabaseConfigFormProps) {
 	const idPrefix = useId();
+	const fileBased = isFileDatabaseType(config.type);
 
 	const handleTypeChange = (value: string) => {
 		const normalized = normalizeDatabaseType(value);
@@ -96,68 +98,87 @@ export function DatabaseConfigForm({
 					</Select>
 				</div>
 
-				{/* 主机和端口 */}
-				<div className="grid grid-cols-3 gap-4">
-					<div className="col-span-2 space-y-2">
-						<Label htmlFor={`${idPrefix}-host`}>主机地址</Label>
-						<Input
-							id={`${idPrefix}-host`}
-							value={config.host}
-							onChange={(e) => onConfigChange("host", e.target.value)}
-							placeholder="例如: localhost"
-							required
-						/>
-					</div>
+				{/* 文件类数据源只需要路径 */}
+				{fileBased ? (
 					<div className="space-y-2">
-						<Label htmlFor={`${idPrefix}-port`}>端口</Label>
+						<Label htmlFor={`${idPrefix}-file-path`}>文件路径</Label>
 						<Input
-							id={`${idPrefix}-port`}
-							value={config.port}
-							onChange={(e) =>
-								onConfigChange("port", parseInt(e.target.value, 10) || 3306)
-							}
-							placeholder="3306"
+							id={`${idPrefix}-file-path`}
+							value={config.filePath ?? ""}
+							onChange={(e) => onConfigChange("filePath", e.target.value)}
+							placeholder="数据库文件，或 CSV/TSV、Parquet 文件所在目录的路径"
 							required
 						/>
 					</div>
-				</div>
+				) : (
+					<>
+						{/* 主机和端口 */}
+						<div className="grid grid-cols-3 gap-4">
+							<div className="col-span-2 space-y-2">
+								<Label htmlFor={`${idPrefix}-host`}>主机地址</Label>
+								<Input
+									id={`${idPrefix}-host`}
+									value={config.host}
+									onChange={(e) => onConfigChange("host", e.target.value)}
+									placeholder="例如: localhost"
+									required
+								/>
+							</div>
+							<div className="space-y-2">
+								<Label htmlFor={`${idPrefix}-port`}>端口</Label>
+								<Input
+									id={`${idPrefix}-port`}
+									value={config.port}
+									onChange={(e) =>
+										onConfigChange(
+											"port",
+											parseInt(e.target.value, 10) || 3306,
+										)
+									}
+									placeholder="3306"
+									required
+								/>
+							</div>
+						</div>
 
-				{/* 数据库名称 */}
-				<div className="space-y-2">
-					<Label htmlFor={`${idPrefix}-database`}>数据库名称</Label>
-					<Input
-						id={`${idPrefix}-database`}
-						value={config.database}
-						onChange={(e) => onConfigChange("database", e.target.value)}
-						placeholder="例如: main_db"
-						required
-					/>
-				</div>
+						{/* 数据库名称 */}
+						<div className="space-y-2">
+							<Label htmlFor={`${idPrefix}-database`}>数据库名称</Label>
+							<Input
+								id={`${idPrefix}-database`}
+								value={config.database}
+								onChange={(e) => onConfigChange("database", e.target.value)}
+								placeholder="例如: main_db"
+								required
+							/>
+						</div>
 
-				{/* 用户名和密码 */}
-				<div className="grid grid-cols-2 gap-4">
-					<div className="space-y-2">
-						<Label htmlFor={`${idPrefix}-username`}>用户名</Label>
-						<Input
-							id={`${idPrefix}-username`}
-							value={config.username}
-							onChange={(e) => onConfigChange("username", e.target.value)}
-							placeholder="数据库用户名"
-							required
-						/>
-					</div>
-					<div className="space-y-2">
-						<Label htmlFor={`${idPrefix}-password`}>密码</Label>
-						<Input
-							id={`${idPrefix}-password`}
-							type="password"
-							value={config.password}
-							onChange={(e) => onConfigChange("password", e.target.value)}
-							placeholder="数据库密码"
-							required
-						/>
-					</div>
-				</div>
+						{/* 用户名和密码 */}
+						<div className="grid grid-cols-2 gap-4">
+							<div className="space-y-2">
+								<Label htmlFor={`${idPrefix}-username`}>用户名</Label>
+								<Input
+									id={`${idPrefix}-username`}
+									value={config.username}
+									onChange={(e) => onConfigChange("username", e.target.value)}
+									placeholder="数据库用户名"
+									required
+								/>
+							</div>
+							<div className="space-y-2">
+								<Label htmlFor={`${idPrefix}-password`}>密码</Label>
+								<Input
+									id={`${idPrefix}-password`}
+									type="password"
+									value={config.password}
+									onChange={(e) => onConfigChange("password", e.target.value)}
+									placeholder="数据库密码"
+									required
+								/>
+							</div>
+						</div>
+					</>
+				)}
 
 				{/* 并发度配置 */}
 				<div className="space-y-2">
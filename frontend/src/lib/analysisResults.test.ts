@@ -0,0 +1,50 @@
+import { describe, expect, it } from "vitest";
+
+import { flattenAnalysisResults, getRuleErrors } from "./analysisResults";
+
+const typedResults = {
+	version: 2,
+	rules: {
+		row_count: { status: "success", table: 0 },
+		non_null_rate: { status: "success", columns: { email: 0.95 } },
+		top_values: { status: "failed", error: "timeout" },
+	},
+};
+
+describe("flattenAnalysisResults", () => {
+	it("flattens typed results into rule metrics", () => {
+		expect(flattenAnalysisResults(typedResults)).toEqual({
+			row_count: 0,
+			non_null_rate: { email: 0.95 },
+		});
+	});
+
+	it("returns legacy results unchanged", () => {
+		const legacy = { row_count: 50, non_null_rate: { status: 0.9 } };
+		expect(flattenAnalysisResults(legacy)).toBe(legacy);
+		expect(flattenAnalysisResults(undefined)).toEqual({});
+	});
+});
+
+describe("getRuleErrors", () => {
+	it("collects failed rules", () => {
+		expect(getRuleErrors(typedResults)).toEqual({ top_values: "timeout" });
+		expect(getRuleErrors({ row_count: 1 })).toEqual({});
+	});
+
+	it("collects failed columns of successful rules", () => {
+		const results = {
+			version: 2,
+			rules: {
+				top_values: {
+					status: "success",
+					columns: { kind: [] },
+					columnErrors: { doc: "operand type clash" },
+				},
+			},
+		};
+		expect(getRuleErrors(results)).toEqual({
+			"top_values.doc": "operand type clash",
+		});
+	});
+});
@@ -0,0 +1,88 @@
+export type RuleStatus = "success" | "failed" | "skipped";
+
+export interface RuleResult {
+	status: RuleStatus;
+	error?: string;
+	table?: unknown;
+	columns?: Record<string, unknown>;
+	sampled?: boolean;
+	columnErrors?: Record<string, string>;
+}
+
+// 采样分析的样本信息，estimatedError 为比例类指标 95% 置信度下的最大误差
+export interface SamplingInfo {
+	percent: number;
+	seed: number;
+	sampleRows: number;
+	estimatedError: number;
+}
+
+// 分区分析的分区信息，mergedRules 之外的规则整表执行
+export interface PartitionInfo {
+	column: string;
+	count: number;
+	mergedRules: string[];
+}
+
+// 增量分析的水位信息，mergedRules 为与历史累计值合并的规则
+export interface IncrementalInfo {
+	column: string;
+	fromWatermark?: string;
+	toWatermark?: string;
+	mergedRules: string[];
+}
+
+export interface AnalysisResultSet {
+	version: number;
+	error?: string;
+	rules: Record<string, RuleResult>;
+	sampling?: SamplingInfo;
+	partitioning?: PartitionInfo;
+	incremental?: IncrementalInfo;
+}
+
+export function isAnalysisResultSet(value: unknown): value is AnalysisResultSet {
+	return (
+		typeof value === "object" &&
+		value !== null &&
+		typeof (value as AnalysisResultSet).version === "number" &&
+		typeof (value as AnalysisResultSet).rules === "object"
+	);
+}
+
+// 将结果展开为 规则名 -> 指标 的结构，失败或跳过的规则不包含在内；未带版本号的旧结构原样返回
+export function flattenAnalysisResults(
+	results: unknown,
+): Record<string, any> {
+	if (!isAnalysisResultSet(results)) {
+		return (results as Record<string, any>) ?? {};
+	}
+
+	const flattened: Record<string, any> = {};
+	Object.entries(results.rules ?? {}).forEach(([name, rule]) => {
+		if (rule.status !== "success") {
+			return;
+		}
+		flattened[name] = rule.columns ?? rule.table;
+	});
+	return flattened;
+}
+
+// 收集失败或跳过的规则及其错误信息，部分列失败的规则以 规则名.列名 为键
+export function getRuleErrors(results: unknown): Record<string, string> {
+	if (!isAnalysisResultSet(results)) {
+		return {};
+	}
+
+	const errors: Record<string, string> = {};
+	Object.entries(results.rules ?? {}).forEach(([name, rule]) => {
+		if (rule.status !== "success") {
+			errors[name] = rule.error ?? rule.status;
+			return;
+		}
+		Object.entries(rule.columnErrors ?? {}).forEach(([column, error]) => {
+			errors[`${name}.${column}`] = error;
+		});
+	});
+	return errors;
+}
@@ -5,6 +5,7 @@ import {
 	DEFAULT_DATABASE_TYPE,
 	getDatabaseTypeLabel,
 	getDefaultPort,
+	isFileDatabaseType,
 	normalizeDatabaseType,
 } from "./databaseTypes";
 
@@ -48,4 +49,12 @@ describe("databaseTypes helpers", () => {
 	it("returns default port for unsupported type", () => {
 		expect(getDefaultPort("unknown")).toBe(DATABASE_TYPES[0].defaultPort);
 	});
+
+	it("identifies file based database types", () => {
+		expect(isFileDatabaseType("sqlite")).toBe(true);
+		expect(isFileDatabaseType("CSV")).toBe(true);
+		expect(isFileDatabaseType("parquet")).toBe(true);
+		expect(isFileDatabaseType("mysql")).toBe(false);
+		expect(isFileDatabaseType("unknown")).toBe(false);
+	});
 });
@@ -2,13 +2,21 @@ export type DatabaseTypeOption = {
 	value: string;
 	label: string;
 	defaultPort: number;
+	fileBased?: boolean; // 文件类数据源，连接时只需要文件或目录路径
 };
 
 export const DATABASE_TYPES: DatabaseTypeOption[] = [
 	{ value: "mysql", label: "MySQL", defaultPort: 3306 },
+	{ value: "mariadb", label: "MariaDB", defaultPort: 3306 },
+	{ value: "tidb", label: "TiDB", defaultPort: 4000 },
+	{ value: "doris", label: "Apache Doris", defaultPort: 9030 },
+	{ value: "starrocks", label: "StarRocks", defaultPort: 9030 },
 	{ value: "sqlserver", label: "SQL Server", defaultPort: 1433 },
 	{ value: "oracle", label: "Oracle", defaultPort: 1521 },
 	{ value: "postgresql", label: "PostgreSQL", defaultPort: 5432 },
+	{ value: "sqlite", label: "SQLite", defaultPort: 0, fileBased: true },
+	{ value: "csv", label: "CSV/TSV", defaultPort: 0, fileBased: true },
+	{ value: "parquet", label: "Parquet", defaultPort: 0, fileBased: true },
 ];
 
 export const DEFAULT_DATABASE_TYPE = DATABASE_TYPES[0].value;
@@ -34,6 +42,12 @@ export function getDatabaseTypeLabel(type: string): string {
 	return option ? option.label : type;
 }
 
+export function isFileDatabaseType(type: string): boolean {
+	const normalized = normalizeDatabaseType(type);
+	const option = DATABASE_TYPES.find((item) => item.value === normalized);
+	return option?.fileBased ?? false;
+}
+
 export function getDefaultPort(type: string): number {
 	const normalized = normalizeDatabaseType(type);
 	const option = DATABASE_TYPES.find((item) => item.value === normalized);
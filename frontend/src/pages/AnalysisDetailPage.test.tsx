@@ -29,6 +29,10 @@ describe("AnalysisDetailPage", () => {
 							email: 0.95,
 							age: 0.72,
 						},
+						distinct_count: {
+							email: { distinct_count: 1200, distinct_ratio: 0.97 },
+							age: { distinct_count: 80, distinct_ratio: 0.06 },
+						},
 					},
 					columns: [
 						{ name: "email", type: "varchar", comment: "邮箱", ordinal: 1 },
@@ -45,6 +49,7 @@ describe("AnalysisDetailPage", () => {
 		expect(screen.getByText("users")).toBeInTheDocument();
 		expect(screen.getByText("1,234")).toBeInTheDocument();
 		expect(screen.getByText("邮箱")).toBeInTheDocument();
+		expect(screen.getByText("1,200 / 97%")).toBeInTheDocument();
 
 		fireEvent.change(screen.getByPlaceholderText("搜索列名..."), {
 			target: { value: "email" },
@@ -13,6 +13,7 @@ import {
 	TableHeader,
 	TableRow,
 } from "@/components/ui/table";
+import { flattenAnalysisResults } from "@/lib/analysisResults";
 
 interface ColumnData {
 	name: string;
@@ -20,13 +21,53 @@ interface ColumnData {
 	comment: string;
 	ordinal: number;
 	nonNullRate?: number;
+	distinctCount?: number;
+	distinctRatio?: number;
+	numericStats?: NumericStats;
 }
 
+interface DistinctStats {
+	distinct_count: number;
+	distinct_ratio: number;
+}
+
+interface NumericStats {
+	min: number | null;
+	max: number | null;
+	mean: number | null;
+	stddev: number | null;
+	p25: number | null;
+	p50: number | null;
+	p75: number | null;
+	p95: number | null;
+	approximate: boolean;
+	percentilesUnavailable?: boolean;
+}
+
+// 数值格式化：最多保留4位小数
+const formatNumber = (value: number | null | undefined) =>
+	value === null || value === undefined
+		? "-"
+		: value.toLocaleString(undefined, { maximumFractionDigits: 4 });
+
+const describeNumericStats = (stats: NumericStats) =>
+	[
+		`标准差: ${formatNumber(stats.stddev)}`,
+		`P25: ${formatNumber(stats.p25)}`,
+		`P50: ${formatNumber(stats.p50)}`,
+		`P75: ${formatNumber(stats.p75)}`,
+		`P95: ${formatNumber(stats.p95)}`,
+		stats.approximate ? "（分位数为近似值）" : "",
+		stats.percentilesUnavailable ? "（分区或增量合并的结果不含分位数）" : "",
+	].join("\n");
+
 interface EnhancedAnalysisResult {
 	status: string;
 	results: {
 		row_count?: number;
 		non_null_rate?: Record<string, number>;
+		distinct_count?: Record<string, DistinctStats>;
+		numeric_stats?: Record<string, NumericStats>;
 	};
 	tableName: string;
 	tableComment: string;
@@ -88,10 +129,13 @@ export function AnalysisDetailPage({
 					// 检查是否已经是增强的结果（包含columns信息）
 					if (resultData.columns && Array.isArray(resultData.columns)) {
 						console.log("使用增强的分析结果");
-						setEnhancedResult(resultData as EnhancedAnalysisResult);
+						setEnhancedResult({
+							...resultData,
+							results: flattenAnalysisResults(resultData.results),
+						} as EnhancedAnalysisResult);
 					} else {
 						console.log("使用基本的分析结果");
-						setAnalysisData(resultData.results);
+						setAnalysisData(flattenAnalysisResults(resultData.results));
 					}
 				}
 			} catch (error) {
@@ -118,7 +162,10 @@ export function AnalysisDetailPage({
 	if (enhancedResult?.columns) {
 		// 使用增强结果中的列信息，并合并非空率数据
 		const nonNullRateData = enhancedResult.results?.non_null_rate || {};
+		const distinctData = enhancedResult.results?.distinct_count || {};
+		const numericData = enhancedResult.results?.numeric_stats || {};
 		enhancedResult.columns.forEach((column) => {
+			const distinct = distinctData[column.name];
 			columns.push({
 				name: column.name,
 				type: column.type,
@@ -127,23 +174,45 @@ export function AnalysisDetailPage({
 				nonNullRate: nonNullRateData[column.name]
 					? Math.round(nonNullRateData[column.name] * 100)
 					: undefined,
+				distinctCount: distinct?.distinct_count,
+				distinctRatio: distinct
+					? Math.round(distinct.distinct_ratio * 100)
+					: undefined,
+				numericStats: numericData[column.name],
 			});
 		});
 	} else if (analysisData?.non_null_rate) {
 		// 降级使用原有数据结构
+		const distinctData: Record<string, DistinctStats> =
+			analysisData.distinct_count || {};
 		Object.entries(analysisData.non_null_rate).forEach(
 			([name, nonNullRate]) => {
+				const distinct = distinctData[name];
 				columns.push({
 					name,
 					type: "未知",
 					comment: "",
 					ordinal: 0,
 					nonNullRate: Math.round((nonNullRate as number) * 100),
+					distinctCount: distinct?.distinct_count,
+					distinctRatio: distinct
+						? Math.round(distinct.distinct_ratio * 100)
+						: undefined,
 				});
 			},
 		);
 	}
 
+	// 是否包含去重统计
+	const hasDistinctStats = columns.some(
+		(column) => column.distinctCount !== undefined,
+	);
+
+	// 是否包含数值统计
+	const hasNumericStats = columns.some(
+		(column) => column.numericStats !== undefined,
+	);
+
 	// 搜索过滤
 	const filteredColumns = columns.filter((column) =>
 		column.name.toLowerCase().includes(searchQuery.toLowerCase()),
@@ -173,6 +242,13 @@ export function AnalysisDetailPage({
 			"字段中文名",
 			"字段类型",
 			"非空值率",
+			"去重值数量",
+			"去重率",
+			"最小值",
+			"最大值",
+			"均值",
+			"标准差",
+			"中位数",
 		].join("\t");
 
 		const columnRows = sortedColumns.map((column) =>
@@ -184,6 +260,15 @@ export function AnalysisDetailPage({
 				column.nonNullRate !== undefined
 					? `${column.nonNullRate}%`
 					: "",
+				column.distinctCount !== undefined
+					? column.distinctCount.toString()
+					: "",
+				column.distinctRatio !== undefined ? `${column.distinctRatio}%` : "",
+				column.numericStats?.min?.toString() ?? "",
+				column.numericStats?.max?.toString() ?? "",
+				column.numericStats?.mean?.toString() ?? "",
+				column.numericStats?.stddev?.toString() ?? "",
+				column.numericStats?.p50?.toString() ?? "",
 			].join("\t"),
 		);
 
@@ -236,7 +321,7 @@ export function AnalysisDetailPage({
 					<h1 className="text-3xl font-bold">分析结果详情</h1>
 				</div>
 				<p className="text-muted-foreground">
-					查看表数据质量分析结果，包括非空值率统计和可视化展示
+					查看表数据质量分析结果，包括非空值率、去重统计和可视化展示
 				</p>
 			</div>
 
@@ -349,6 +434,16 @@ export function AnalysisDetailPage({
 											非空值率
 										</TableHead>
 									)}
+									{hasDistinctStats && (
+										<TableHead className="text-right w-[160px]">
+											去重值数量 / 去重率
+										</TableHead>
+									)}
+									{hasNumericStats && (
+										<TableHead className="text-right w-[220px]">
+											最小值 ~ 最大值 / 均值
+										</TableHead>
+									)}
 								</TableRow>
 							</TableHeader>
 							<TableBody>
@@ -385,6 +480,27 @@ export function AnalysisDetailPage({
 												</Badge>
 											</TableCell>
 										)}
+										{hasDistinctStats && (
+											<TableCell className="text-right text-sm text-gray-600">
+												{column.distinctCount !== undefined
+													? `${column.distinctCount.toLocaleString()} / ${column.distinctRatio}%`
+													: "-"}
+											</TableCell>
+										)}
+										{hasNumericStats && (
+											<TableCell
+												className="text-right text-sm text-gray-600"
+												title={
+													column.numericStats
+														? describeNumericStats(column.numericStats)
+														: undefined
+												}
+											>
+												{column.numericStats
+													? `${formatNumber(column.numericStats.min)} ~ ${formatNumber(column.numericStats.max)} / ${formatNumber(column.numericStats.mean)}`
+													: "-"}
+											</TableCell>
+										)}
 									</TableRow>
 								))}
 							</TableBody>
@@ -358,6 +358,14 @@ export function TaskManagementPage({
 		}
 	};
 
+	// 质量判定的显示文字和颜色
+	const qualityBadges: Record<string, { label: string; className: string }> =
+		{
+			pass: { label: "质量合格", className: "bg-green-100 text-green-800" },
+			warn: { label: "质量告警", className: "bg-orange-100 text-orange-800" },
+			fail: { label: "质量不合格", className: "bg-red-100 text-red-800" },
+		};
+
 	return (
 		<div className="p-8">
 			{/* Header */}
@@ -476,6 +484,17 @@ export function TaskManagementPage({
 											>
 												{table.tblStatus || "待分析"}
 											</Badge>
+											{table.tblStatus === "分析完成" &&
+												table.qualityStatus &&
+												qualityBadges[table.qualityStatus] && (
+													<Badge
+														className={`ml-2 ${qualityBadges[table.qualityStatus].className}`}
+													>
+														{qualityBadges[table.qualityStatus].label}
+														{typeof table.qualityScore === "number" &&
+															` ${table.qualityScore}`}
+													</Badge>
+												)}
 										</TableCell>
 										<TableCell className="text-right">
 											{table.rowCount.toLocaleString()}
@@ -1,3 +1,5 @@
+import type { AnalysisResultSet } from "@/lib/analysisResults";
+
 export interface DatabaseConfig {
 	id: string;
 	name: string;
@@ -7,6 +9,7 @@ export interface DatabaseConfig {
 	username: string;
 	password: string;
 	database: string;
+	filePath?: string; // 文件类数据源（SQLite、CSV/TSV）的文件或目录路径
 	concurrency: number; // 并发度配置，默认5
 }
 
@@ -39,6 +42,8 @@ export type TaskTable = {
 	tableSize: number;
 	columnCount: number;
 	tblStatus: string; // 表状态：待分析｜分析中｜分析完成
+	qualityStatus?: "pass" | "warn" | "fail" | "skipped" | ""; // 最近一次分析的质量判定
+	qualityScore?: number | null;
 	addedAt: string;
 };
 
@@ -80,7 +85,7 @@ export interface AnalysisResult {
 	databaseId: string;
 	tableName: string;
 	rules: string[];
-	results: Record<string, unknown>;
+	results: AnalysisResultSet;
 	status: "pending" | "running" | "completed" | "failed" | "cancelled";
 	startedAt: Date;
 	completedAt?: Date;
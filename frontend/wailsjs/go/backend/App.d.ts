@@ -16,8 +16,14 @@ export function CreateTask(arg1:string,arg2:string):Promise<Record<string, any>>
 
 export function DeleteAnalysisResult(arg1:string):Promise<void>;
 
+export function DeleteCustomRule(arg1:string):Promise<Record<string, any>>;
+
 export function DeleteDatabaseConnection(arg1:string):Promise<void>;
 
+export function DeleteExpectation(arg1:string):Promise<Record<string, any>>;
+
+export function DeletePattern(arg1:string):Promise<Record<string, any>>;
+
 export function DeleteTask(arg1:string):Promise<Record<string, any>>;
 
 export function GetAllConnectionsWithMetadata():Promise<Array<Record<string, any>>>;
@@ -26,16 +32,26 @@ export function GetAllTasks():Promise<Array<Record<string, any>>>;
 
 export function GetAnalysisResults(arg1:string):Promise<Array<Record<string, any>>>;
 
-export function GetAvailableRules():Promise<Array<string>>;
+export function GetAvailableRules():Promise<Array<backend.RuleSchema>>;
+
+export function GetCustomRules():Promise<Array<backend.CustomRuleDefinition>>;
 
 export function GetDatabaseConnections():Promise<Array<backend.DatabaseConfig>>;
 
 export function GetEnhancedAnalysisResult(arg1:string,arg2:string):Promise<Record<string, any>>;
 
+export function GetExpectations(arg1:string):Promise<Array<backend.Expectation>>;
+
 export function GetMetadataColumns(arg1:string):Promise<Array<Record<string, any>>>;
 
+export function GetMetadataConstraints(arg1:string):Promise<Array<Record<string, any>>>;
+
+export function GetMetadataIndexes(arg1:string):Promise<Record<string, any>>;
+
 export function GetMetadataTables(arg1:string):Promise<Array<Record<string, any>>>;
 
+export function GetPatterns():Promise<Array<backend.PatternDefinition>>;
+
 export function GetTableAnalysisResult(arg1:string,arg2:string):Promise<Record<string, any>>;
 
 export function GetTableSelections():Promise<Array<string>>;
@@ -56,8 +72,18 @@ export function LogFrontendAction(arg1:string,arg2:string,arg3:string):Promise<v
 
 export function RemoveTableFromTask(arg1:string,arg2:string):Promise<Record<string, any>>;
 
+export function ResetAnalysisWatermark(arg1:string,arg2:string):Promise<void>;
+
+export function RetryTaskPartition(arg1:string,arg2:number):Promise<void>;
+
+export function SaveCustomRule(arg1:backend.CustomRuleDefinition):Promise<Record<string, any>>;
+
 export function SaveDatabaseConnection(arg1:backend.DatabaseConfig):Promise<void>;
 
+export function SaveExpectation(arg1:backend.Expectation):Promise<Record<string, any>>;
+
+export function SavePattern(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, any>>;
+
 export function SaveTableSelections(arg1:Array<string>):Promise<void>;
 
 export function StartAnalysisTasks(arg1:string,arg2:Array<string>):Promise<string>;
@@ -69,3 +95,13 @@ export function TestDatabaseConnection(arg1:backend.DatabaseConfig):Promise<stri
 export function UpdateDatabaseMetadata(arg1:string):Promise<Record<string, any>>;
 
 export function UpdateTask(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+
+export function UpdateTaskPartitioning(arg1:string,arg2:backend.PartitionConfig):Promise<Record<string, any>>;
+
+export function UpdateTaskRules(arg1:string,arg2:Array<backend.TaskRuleConfig>):Promise<Record<string, any>>;
+
+export function UpdateTaskSampling(arg1:string,arg2:backend.SamplingConfig):Promise<Record<string, any>>;
+
+export function UpdateTaskTableRules(arg1:string,arg2:string,arg3:Array<backend.TaskRuleConfig>):Promise<Record<string, any>>;
+
+export function UpdateTaskWatermark(arg1:string,arg2:backend.WatermarkConfig):Promise<Record<string, any>>;
@@ -30,10 +30,22 @@ export function DeleteAnalysisResult(arg1) {
   return window['go']['backend']['App']['DeleteAnalysisResult'](arg1);
 }
 
+export function DeleteCustomRule(arg1) {
+  return window['go']['backend']['App']['DeleteCustomRule'](arg1);
+}
+
 export function DeleteDatabaseConnection(arg1) {
   return window['go']['backend']['App']['DeleteDatabaseConnection'](arg1);
 }
 
+export function DeleteExpectation(arg1) {
+  return window['go']['backend']['App']['DeleteExpectation'](arg1);
+}
+
+export function DeletePattern(arg1) {
+  return window['go']['backend']['App']['DeletePattern'](arg1);
+}
+
 export function DeleteTask(arg1) {
   return window['go']['backend']['App']['DeleteTask'](arg1);
 }
@@ -54,6 +66,10 @@ export function GetAvailableRules() {
   return window['go']['backend']['App']['GetAvailableRules']();
 }
 
+export function GetCustomRules() {
+  return window['go']['backend']['App']['GetCustomRules']();
+}
+
 export function GetDatabaseConnections() {
   return window['go']['backend']['App']['GetDatabaseConnections']();
 }
@@ -62,14 +78,30 @@ export function GetEnhancedAnalysisResult(arg1, arg2) {
   return window['go']['backend']['App']['GetEnhancedAnalysisResult'](arg1, arg2);
 }
 
+export function GetExpectations(arg1) {
+  return window['go']['backend']['App']['GetExpectations'](arg1);
+}
+
 export function GetMetadataColumns(arg1) {
   return window['go']['backend']['App']['GetMetadataColumns'](arg1);
 }
 
+export function GetMetadataConstraints(arg1) {
+  return window['go']['backend']['App']['GetMetadataConstraints'](arg1);
+}
+
+export function GetMetadataIndexes(arg1) {
+  return window['go']['backend']['App']['GetMetadataIndexes'](arg1);
+}
+
 export function GetMetadataTables(arg1) {
   return window['go']['backend']['App']['GetMetadataTables'](arg1);
 }
 
+export function GetPatterns() {
+  return window['go']['backend']['App']['GetPatterns']();
+}
+
 export function GetTableAnalysisResult(arg1, arg2) {
   return window['go']['backend']['App']['GetTableAnalysisResult'](arg1, arg2);
 }
@@ -110,10 +142,30 @@ export function RemoveTableFromTask(arg1, arg2) {
   return window['go']['backend']['App']['RemoveTableFromTask'](arg1, arg2);
 }
 
+export function ResetAnalysisWatermark(arg1, arg2) {
+  return window['go']['backend']['App']['ResetAnalysisWatermark'](arg1, arg2);
+}
+
+export function RetryTaskPartition(arg1, arg2) {
+  return window['go']['backend']['App']['RetryTaskPartition'](arg1, arg2);
+}
+
+export function SaveCustomRule(arg1) {
+  return window['go']['backend']['App']['SaveCustomRule'](arg1);
+}
+
 export function SaveDatabaseConnection(arg1) {
   return window['go']['backend']['App']['SaveDatabaseConnection'](arg1);
 }
 
+export function SaveExpectation(arg1) {
+  return window['go']['backend']['App']['SaveExpectation'](arg1);
+}
+
+export function SavePattern(arg1, arg2, arg3, arg4) {
+  return window['go']['backend']['App']['SavePattern'](arg1, arg2, arg3, arg4);
+}
+
 export function SaveTableSelections(arg1) {
   return window['go']['backend']['App']['SaveTableSelections'](arg1);
 }
@@ -137,3 +189,23 @@ export function UpdateDatabaseMetadata(arg1) {
 export function UpdateTask(arg1, arg2, arg3) {
   return window['go']['backend']['App']['UpdateTask'](arg1, arg2, arg3);
 }
+
+export function UpdateTaskPartitioning(arg1, arg2) {
+  return window['go']['backend']['App']['UpdateTaskPartitioning'](arg1, arg2);
+}
+
+export function UpdateTaskRules(arg1, arg2) {
+  return window['go']['backend']['App']['UpdateTaskRules'](arg1, arg2);
+}
+
+export function UpdateTaskSampling(arg1, arg2) {
+  return window['go']['backend']['App']['UpdateTaskSampling'](arg1, arg2);
+}
+
+export function UpdateTaskTableRules(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['UpdateTaskTableRules'](arg1, arg2, arg3);
+}
+
+export function UpdateTaskWatermark(arg1, arg2) {
+  return window['go']['backend']['App']['UpdateTaskWatermark'](arg1, arg2);
+}
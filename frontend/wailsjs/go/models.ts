@@ -1,5 +1,31 @@
 export namespace backend {
 	
+	export class CustomRuleDefinition {
+	    id: string;
+	    name: string;
+	    description: string;
+	    sql: string;
+	    expectation: string;
+	    min?: number;
+	    max?: number;
+	    columns?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CustomRuleDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.sql = source["sql"];
+	        this.expectation = source["expectation"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.columns = source["columns"];
+	    }
+	}
 	export class DatabaseConfig {
 	    id: string;
 	    name: string;
@@ -9,6 +35,7 @@ export namespace backend {
 	    username: string;
 	    password: string;
 	    database: string;
+	    filePath?: string;
 	    concurrency: number;
 	
 	    static createFrom(source: any = {}) {
@@ -25,9 +52,172 @@ export namespace backend {
 	        this.username = source["username"];
 	        this.password = source["password"];
 	        this.database = source["database"];
+	        this.filePath = source["filePath"];
 	        this.concurrency = source["concurrency"];
 	    }
 	}
+	export class Expectation {
+	    id: string;
+	    tableId: string;
+	    columnName: string;
+	    metric: string;
+	    operator: string;
+	    threshold: number;
+	    upper: number;
+	    values?: string[];
+	    severity: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Expectation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.tableId = source["tableId"];
+	        this.columnName = source["columnName"];
+	        this.metric = source["metric"];
+	        this.operator = source["operator"];
+	        this.threshold = source["threshold"];
+	        this.upper = source["upper"];
+	        this.values = source["values"];
+	        this.severity = source["severity"];
+	    }
+	}
+	export class PartitionConfig {
+	    column?: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PartitionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.column = source["column"];
+	        this.count = source["count"];
+	    }
+	}
+	export class PatternDefinition {
+	    id: string;
+	    name: string;
+	    description: string;
+	    expression: string;
+	    builtin: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PatternDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.expression = source["expression"];
+	        this.builtin = source["builtin"];
+	    }
+	}
+	export class RuleParameter {
+	    name: string;
+	    type: string;
+	    description: string;
+	    default?: any;
+	    required: boolean;
+	    min?: number;
+	    max?: number;
+	    options?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleParameter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.required = source["required"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.options = source["options"];
+	    }
+	}
+	export class RuleSchema {
+	    name: string;
+	    description: string;
+	    parameters: RuleParameter[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleSchema(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parameters = this.convertValues(source["parameters"], RuleParameter);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SamplingConfig {
+	    percent: number;
+	    seed?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SamplingConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.percent = source["percent"];
+	        this.seed = source["seed"];
+	    }
+	}
+	export class TaskRuleConfig {
+	    name: string;
+	    params?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskRuleConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.params = source["params"];
+	    }
+	}
+	export class WatermarkConfig {
+	    column: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatermarkConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.column = source["column"];
+	    }
+	}
 
 }
 